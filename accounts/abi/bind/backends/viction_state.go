@@ -0,0 +1,118 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// stateCallGas is the gas allowance of a contract call executed against a state.
+const stateCallGas = 50000000
+
+var (
+	errStateBlockNumber = errors.New("state backend cannot access blocks other than its own")
+	errStateNoRecipient = errors.New("state backend cannot create contracts")
+)
+
+// This nil assignment ensures at compile time that StateBackend implements bind.ContractCaller.
+var _ bind.ContractCaller = (*StateBackend)(nil)
+
+// StateBackend implements bind.ContractCaller on top of a state database, executing
+// contract calls directly in the EVM without going through an RPC endpoint. It allows
+// consensus code, which only has the state of a block at hand, to read contract views
+// through the generated bindings.
+//
+// Calls never modify the state: every change made while executing them is reverted.
+type StateBackend struct {
+	config  *params.ChainConfig
+	header  *types.Header
+	statedb *state.StateDB
+}
+
+// NewStateBackend creates a contract caller executing calls against the state of the
+// given block.
+func NewStateBackend(config *params.ChainConfig, header *types.Header, statedb *state.StateDB) *StateBackend {
+	return &StateBackend{
+		config:  config,
+		header:  header,
+		statedb: statedb,
+	}
+}
+
+// CodeAt returns the code associated with a certain account in the state.
+func (b *StateBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	if blockNumber != nil && blockNumber.Cmp(b.header.Number) != 0 {
+		return nil, errStateBlockNumber
+	}
+	return b.statedb.GetCode(contract), nil
+}
+
+// CallContract executes a contract call against the state and returns its output.
+func (b *StateBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if blockNumber != nil && blockNumber.Cmp(b.header.Number) != 0 {
+		return nil, errStateBlockNumber
+	}
+	if call.To == nil {
+		return nil, errStateNoRecipient
+	}
+	gas := call.Gas
+	if gas == 0 {
+		gas = stateCallGas
+	}
+	value := call.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	difficulty := new(big.Int)
+	if b.header.Difficulty != nil {
+		difficulty.Set(b.header.Difficulty)
+	}
+	snapshot := b.statedb.Snapshot()
+	defer b.statedb.RevertToSnapshot(snapshot)
+
+	blockContext := vm.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		GetHash:     func(uint64) common.Hash { return common.Hash{} },
+		Coinbase:    b.header.Coinbase,
+		GasLimit:    b.header.GasLimit,
+		BlockNumber: new(big.Int).Set(b.header.Number),
+		Time:        new(big.Int).SetUint64(b.header.Time),
+		Difficulty:  difficulty,
+	}
+	txContext := vm.TxContext{
+		Origin:   call.From,
+		GasPrice: new(big.Int),
+	}
+	evm := vm.NewEVM(blockContext, txContext, b.statedb, b.config, vm.Config{})
+	ret, _, err := evm.Call(vm.AccountRef(call.From), *call.To, call.Data, gas, value)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/validator/contract"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+)
+
+// Tests that reading the validator contract through its bindings on top of the state
+// agrees with decoding its storage slots, on the Viction mainnet genesis.
+func TestStateBackendValidatorGenesis(t *testing.T) {
+	genesis := core.DefaultVictionGenesisBlock()
+	db := rawdb.NewMemoryDatabase()
+	block := genesis.ToBlock(db)
+	statedb, err := state.New(block.Root(), state.NewDatabase(db), nil)
+	if err != nil {
+		t.Fatalf("failed to open genesis state: %v", err)
+	}
+	address := genesis.Config.Viction.ValidatorContract
+	caller, err := contract.NewValidatorCaller(address, NewStateBackend(genesis.Config, block.Header(), statedb))
+	if err != nil {
+		t.Fatalf("failed to bind validator contract: %v", err)
+	}
+	opts := new(bind.CallOpts)
+
+	candidates, err := caller.GetCandidates(opts)
+	if err != nil {
+		t.Fatalf("failed to get candidates: %v", err)
+	}
+	if have, want := statedb.VicGetCandidates(address), candidates; !equalAddresses(have, want) {
+		t.Fatalf("candidates mismatch: have %x, want %x", have, want)
+	}
+	if len(candidates) == 0 {
+		t.Fatalf("no candidates in genesis")
+	}
+	for _, candidate := range candidates {
+		owner, err := caller.GetCandidateOwner(opts, candidate)
+		if err != nil {
+			t.Fatalf("failed to get owner of %x: %v", candidate, err)
+		}
+		capacity, err := caller.GetCandidateCap(opts, candidate)
+		if err != nil {
+			t.Fatalf("failed to get cap of %x: %v", candidate, err)
+		}
+		slotOwner, slotCap := statedb.VicGetValidatorInfo(address, candidate)
+		if slotOwner != owner || slotCap.Cmp(capacity) != 0 {
+			t.Errorf("candidate %x: info mismatch: have (%x, %v), want (%x, %v)", candidate, slotOwner, slotCap, owner, capacity)
+		}
+		voters, err := caller.GetVoters(opts, candidate)
+		if err != nil {
+			t.Fatalf("failed to get voters of %x: %v", candidate, err)
+		}
+		if have := statedb.VicGetValidatorVoters(address, candidate); !equalAddresses(have, voters) {
+			t.Errorf("candidate %x: voters mismatch: have %x, want %x", candidate, have, voters)
+		}
+		for _, voter := range voters {
+			voterCap, err := caller.GetVoterCap(opts, candidate, voter)
+			if err != nil {
+				t.Fatalf("failed to get cap of voter %x: %v", voter, err)
+			}
+			if have := statedb.VicGetValidatorVoterCap(address, candidate, voter); have.Cmp(voterCap) != 0 {
+				t.Errorf("candidate %x: voter %x cap mismatch: have %v, want %v", candidate, voter, have, voterCap)
+			}
+		}
+	}
+	// Unknown candidates must be empty on both paths
+	unknown := common.HexToAddress("0xdeadbeef")
+	owner, err := caller.GetCandidateOwner(opts, unknown)
+	if err != nil {
+		t.Fatalf("failed to get owner of unknown candidate: %v", err)
+	}
+	if slotOwner, slotCap := statedb.VicGetValidatorInfo(address, unknown); slotOwner != owner || slotCap.Cmp(big.NewInt(0)) != 0 {
+		t.Errorf("unknown candidate info mismatch: have (%x, %v), want (%x, 0)", slotOwner, slotCap, owner)
+	}
+	// Calls must leave the state untouched
+	if root := statedb.IntermediateRoot(false); root != block.Root() {
+		t.Errorf("state root changed by calls: have %x, want %x", root, block.Root())
+	}
+}
+
+func equalAddresses(a, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
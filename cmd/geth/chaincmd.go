@@ -112,6 +112,7 @@ processing will proceed even if an individual RLP-file import failure occurs.`,
 			utils.DataDirFlag,
 			utils.CacheFlag,
 			utils.SyncModeFlag,
+			utils.ExportRewardsFlag,
 		},
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
@@ -119,7 +120,11 @@ Requires a first argument of the file to write to.
 Optional second and third arguments control the first and
 last block to write. In this mode, the file will be appended
 if already existing. If the file ends with .gz, the output will
-be gzipped.`,
+be gzipped.
+
+On PoSV chains, the --rewards flag additionally exports the epoch
+rewards distributed at the checkpoint blocks of the range as a
+stream of JSON objects into the given file.`,
 	}
 	importPreimagesCommand = cli.Command{
 		Action:    utils.MigrateFlags(importPreimages),
@@ -376,29 +381,36 @@ func exportChain(ctx *cli.Context) error {
 	stack, _ := makeConfigNode(ctx)
 	defer stack.Close()
 
-	chain, _ := utils.MakeChain(ctx, stack, true)
+	chain, db := utils.MakeChain(ctx, stack, true)
 	start := time.Now()
 
 	var err error
 	fp := ctx.Args().First()
+	first, last := uint64(0), chain.CurrentBlock().NumberU64()
 	if len(ctx.Args()) < 3 {
 		err = utils.ExportChain(chain, fp)
 	} else {
 		// This can be improved to allow for numbers larger than 9223372036854775807
-		first, ferr := strconv.ParseInt(ctx.Args().Get(1), 10, 64)
-		last, lerr := strconv.ParseInt(ctx.Args().Get(2), 10, 64)
+		firstNum, ferr := strconv.ParseInt(ctx.Args().Get(1), 10, 64)
+		lastNum, lerr := strconv.ParseInt(ctx.Args().Get(2), 10, 64)
 		if ferr != nil || lerr != nil {
 			utils.Fatalf("Export error in parsing parameters: block number not an integer\n")
 		}
-		if first < 0 || last < 0 {
+		if firstNum < 0 || lastNum < 0 {
 			utils.Fatalf("Export error: block number must be greater than 0\n")
 		}
-		err = utils.ExportAppendChain(chain, fp, uint64(first), uint64(last))
+		first, last = uint64(firstNum), uint64(lastNum)
+		err = utils.ExportAppendChain(chain, fp, first, last)
 	}
 
 	if err != nil {
 		utils.Fatalf("Export error: %v\n", err)
 	}
+	if rp := ctx.String(utils.ExportRewardsFlag.Name); rp != "" {
+		if err := utils.ExportEpochRewards(chain, db, rp, first, last); err != nil {
+			utils.Fatalf("Export error: %v\n", err)
+		}
+	}
 	fmt.Printf("Export done in %v\n", time.Since(start))
 	return nil
 }
@@ -0,0 +1,97 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	forksFromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block of the range to list",
+	}
+	forksToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last block of the range to list (default = current head)",
+	}
+)
+
+var forksCommand = cli.Command{
+	Action:    utils.MigrateFlags(listForks),
+	Name:      "forks",
+	Usage:     "List the irregular state transitions applied by hard forks",
+	ArgsUsage: "",
+	Flags: []cli.Flag{
+		utils.DataDirFlag,
+		utils.AncientFlag,
+		utils.VictionFlag,
+		utils.VictestFlag,
+		forksFromFlag,
+		forksToFlag,
+	},
+	Category: "BLOCKCHAIN COMMANDS",
+	Description: `
+    geth forks [--from N] [--to M]
+
+lists every irregular state transition, such as the Saigon funding or the DAO
+refund, together with the number and hash of the block it modified the state
+of, for each block in the range. The transitions are derived from the chain
+configuration stored in the database, and only the ones of blocks in the local
+canonical chain, up to its head block, are listed.`,
+}
+
+func listForks(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chainDb := utils.MakeChainDatabase(ctx, stack)
+	defer chainDb.Close()
+
+	genesis := rawdb.ReadCanonicalHash(chainDb, 0)
+	if genesis == (common.Hash{}) {
+		return errors.New("no genesis block in database")
+	}
+	config := rawdb.ReadChainConfig(chainDb, genesis)
+	if config == nil {
+		return errors.New("no chain configuration in database")
+	}
+	head := rawdb.ReadHeaderNumber(chainDb, rawdb.ReadHeadBlockHash(chainDb))
+	if head == nil {
+		return errors.New("no head block in database")
+	}
+	first, last := ctx.Uint64(forksFromFlag.Name), *head
+	if ctx.IsSet(forksToFlag.Name) && ctx.Uint64(forksToFlag.Name) < last {
+		last = ctx.Uint64(forksToFlag.Name)
+	}
+	for _, block := range misc.TransitionBlocks(config, first, last) {
+		// Scheduled blocks missing from the local chain were never transitioned
+		hash := rawdb.ReadCanonicalHash(chainDb, block.Number)
+		if hash == (common.Hash{}) {
+			continue
+		}
+		fmt.Printf("%d\t%s\t%s\n", block.Number, hash.Hex(), block.Name)
+	}
+	return nil
+}
@@ -88,11 +88,13 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.TxPoolSponsoredGasFlag,
 		utils.SyncModeFlag,
 		utils.ExitWhenSyncedFlag,
 		utils.GCModeFlag,
 		utils.SnapshotFlag,
 		utils.TxLookupLimitFlag,
+		utils.PosvStrictRewardsFlag,
 		utils.LightServeFlag,
 		utils.LegacyLightServFlag,
 		utils.LightIngressFlag,
@@ -248,6 +250,12 @@ func init() {
 		licenseCommand,
 		// See config.go
 		dumpConfigCommand,
+		// See posvcmd.go
+		posvCommand,
+		// See forkscmd.go
+		forksCommand,
+		// See snapshot.go
+		snapshotCommand,
 		// See cmd/utils/flags_legacy.go
 		utils.ShowDeprecated,
 	}
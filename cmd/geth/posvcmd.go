@@ -0,0 +1,308 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/posv"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/viction"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/trie"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	auditFromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block of the range to audit",
+	}
+	auditToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last block of the range to audit (default = current head)",
+	}
+	auditFormatFlag = cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format of the reward differences (csv or json)",
+		Value: "csv",
+	}
+)
+
+var (
+	posvCommand = cli.Command{
+		Name:     "posv",
+		Usage:    "Inspect the proof-of-stake-voting consensus data",
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+Offline tools operating on the PoSV data of a stopped node.`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "audit-rewards",
+				Usage:     "Replay and audit the epoch rewards of a block range",
+				ArgsUsage: "",
+				Action:    utils.MigrateFlags(auditRewards),
+				Category:  "BLOCKCHAIN COMMANDS",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.AncientFlag,
+					utils.CacheFlag,
+					utils.VictionFlag,
+					utils.VictestFlag,
+					auditFromFlag,
+					auditToFlag,
+					auditFormatFlag,
+				},
+				Description: `
+    geth posv audit-rewards --from N --to M [--format csv|json]
+
+recalculates the epoch rewards of every checkpoint block in the range from
+the stored chain, and compares them with the balance changes actually
+recorded in the state of the checkpoints. Every address whose balance change
+differs from its recalculated reward, including addresses credited without
+being rewarded, is written to the standard output.
+
+The state of the parent of each audited checkpoint and the preimages of the
+account keys must be available, which usually requires the node to have been
+run with --gcmode=archive.`,
+			},
+		},
+	}
+)
+
+// auditEngine is a PoSV engine which does not credit any epoch reward, used to recover
+// the state of checkpoint blocks right before their rewards are credited.
+type auditEngine struct {
+	*posv.Posv
+}
+
+// Finalize implements consensus.Engine, skipping the epoch rewards.
+func (e auditEngine) Finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header) {
+}
+
+// rewardDiff is the difference between the recalculated reward of an address and the
+// change of its balance recorded in the state of a checkpoint block.
+type rewardDiff struct {
+	Number   uint64         `json:"number"`
+	Hash     common.Hash    `json:"hash"`
+	Address  common.Address `json:"address"`
+	Expected *big.Int       `json:"expected"`
+	Actual   *big.Int       `json:"actual"`
+	Stored   *big.Int       `json:"stored"`
+}
+
+func auditRewards(ctx *cli.Context) error {
+	var write func(diff *rewardDiff) error
+	switch format := ctx.String(auditFormatFlag.Name); format {
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		defer w.Flush()
+		if err := w.Write([]string{"number", "hash", "address", "expected", "actual", "stored"}); err != nil {
+			return err
+		}
+		write = func(diff *rewardDiff) error {
+			var stored string
+			if diff.Stored != nil {
+				stored = diff.Stored.String()
+			}
+			return w.Write([]string{fmt.Sprint(diff.Number), diff.Hash.Hex(), diff.Address.Hex(), diff.Expected.String(), diff.Actual.String(), stored})
+		}
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		write = func(diff *rewardDiff) error { return enc.Encode(diff) }
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chainDb := utils.MakeChainDatabase(ctx, stack)
+	defer chainDb.Close()
+
+	config, _, err := core.SetupGenesisBlock(chainDb, utils.MakeGenesis(ctx))
+	if err != nil {
+		return err
+	}
+	if config.Posv == nil || config.Viction == nil {
+		return errors.New("epoch rewards are only available on Viction chains")
+	}
+	cache := &core.CacheConfig{
+		TrieCleanLimit: eth.DefaultConfig.TrieCleanCache,
+		TrieDirtyLimit: eth.DefaultConfig.TrieDirtyCache,
+		TrieTimeLimit:  eth.DefaultConfig.TrieTimeout,
+		Preimages:      true, // Resolves the accounts the checkpoint states differ on
+	}
+	chain, err := core.NewBlockChain(chainDb, cache, config, auditEngine{posv.New(config.Posv, chainDb)}, vm.Config{}, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer chain.Stop()
+
+	epoch := config.Posv.Epoch
+	first, last := ctx.Uint64(auditFromFlag.Name), chain.CurrentBlock().NumberU64()
+	if ctx.IsSet(auditToFlag.Name) {
+		last = ctx.Uint64(auditToFlag.Name)
+	}
+	var audited, mismatches int
+	for number := first + (epoch-first%epoch)%epoch; number <= last; number += epoch {
+		// The first checkpoint carrying rewards is the second one
+		if number <= epoch {
+			continue
+		}
+		diffs, err := auditEpochReward(chainDb, chain, number)
+		if err != nil {
+			return fmt.Errorf("audit failed on #%d: %v", number, err)
+		}
+		for _, diff := range diffs {
+			if err := write(diff); err != nil {
+				return err
+			}
+		}
+		audited++
+		mismatches += len(diffs)
+	}
+	log.Info("Audited epoch rewards", "checkpoints", audited, "mismatches", mismatches)
+	return nil
+}
+
+// auditEpochReward recalculates the epoch reward of a canonical checkpoint block and
+// returns the addresses whose balance change in the checkpoint state differs from it,
+// including the ones credited without being rewarded at all.
+func auditEpochReward(db ethdb.Database, chain *core.BlockChain, number uint64) ([]*rewardDiff, error) {
+	block := chain.GetBlockByNumber(number)
+	if block == nil {
+		return nil, errors.New("block not found")
+	}
+	parent := chain.GetHeader(block.ParentHash(), number-1)
+	if parent == nil {
+		return nil, errors.New("parent not found")
+	}
+	// Replay the checkpoint without rewards to get the state they are credited to
+	statedb, err := chain.StateAt(parent.Root)
+	if err != nil {
+		return nil, fmt.Errorf("parent state missing: %v", err)
+	}
+	if _, _, _, err := chain.Processor().Process(block, statedb, vm.Config{}); err != nil {
+		return nil, err
+	}
+	config := chain.Config()
+	reward, err := viction.CalcEpochReward(nil, config, config.Posv, config.Viction, block.Header(), chain, statedb, log.Root())
+	if err != nil && !errors.Is(err, posv.ErrRewardInvariant) {
+		return nil, err
+	}
+	rewarded, err := chain.StateAt(block.Root())
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint state missing: %v", err)
+	}
+	// Credit the recalculated rewards on the replayed state, and check every account
+	// the checkpoint state disagrees on, rewarded or not
+	credited := statedb.Copy()
+	if reward != nil {
+		for addr, amount := range reward.StakholderRewards {
+			if amount != nil && amount.Sign() > 0 {
+				credited.AddBalance(addr, amount)
+			}
+		}
+	}
+	root, err := credited.Commit(config.IsEIP158(block.Number()))
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := diffAccounts(chain.StateCache(), root, block.Root())
+	if err != nil {
+		return nil, err
+	}
+	stored := posv.ReadEpochReward(db, block.Hash(), number)
+
+	var diffs []*rewardDiff
+	for _, addr := range addrs {
+		expected := new(big.Int)
+		if amount := reward.StakholderRewards[addr]; amount != nil && amount.Sign() > 0 {
+			expected.Set(amount)
+		}
+		actual := new(big.Int).Sub(rewarded.GetBalance(addr), statedb.GetBalance(addr))
+		if actual.Cmp(expected) == 0 {
+			continue
+		}
+		diff := &rewardDiff{
+			Number:   number,
+			Hash:     block.Hash(),
+			Address:  addr,
+			Expected: expected,
+			Actual:   actual,
+		}
+		if stored != nil {
+			diff.Stored = stored.StakholderRewards[addr]
+		}
+		diffs = append(diffs, diff)
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return bytes.Compare(diffs[i].Address[:], diffs[j].Address[:]) < 0
+	})
+	return diffs, nil
+}
+
+// diffAccounts returns the addresses of the accounts which differ between the two
+// state tries, resolved through the preimages of their hashed keys.
+func diffAccounts(db state.Database, a, b common.Hash) ([]common.Address, error) {
+	trA, err := db.OpenTrie(a)
+	if err != nil {
+		return nil, err
+	}
+	trB, err := db.OpenTrie(b)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		addrs []common.Address
+		seen  = make(map[common.Address]bool)
+	)
+	for _, tries := range [][2]state.Trie{{trA, trB}, {trB, trA}} {
+		diff, _ := trie.NewDifferenceIterator(tries[0].NodeIterator(nil), tries[1].NodeIterator(nil))
+		it := trie.NewIterator(diff)
+		for it.Next() {
+			preimage := tries[1].GetKey(it.Key)
+			if preimage == nil {
+				return nil, fmt.Errorf("missing preimage of account %x", it.Key)
+			}
+			if addr := common.BytesToAddress(preimage); !seen[addr] {
+				seen[addr] = true
+				addrs = append(addrs, addr)
+			}
+		}
+		if it.Err != nil {
+			return nil, it.Err
+		}
+	}
+	return addrs, nil
+}
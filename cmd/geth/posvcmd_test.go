@@ -0,0 +1,127 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/posv"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// tamperEngine is an ethash faker accepting PoSV headers and crediting no block
+// reward, but the given amount to an account in the state of the tampered block.
+type tamperEngine struct {
+	consensus.Engine
+	number uint64
+	thief  common.Address
+	amount *big.Int
+}
+
+func (e tamperEngine) Finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header) {
+	if header.Number.Uint64() == e.number {
+		state.AddBalance(e.thief, e.amount)
+	}
+}
+
+func (e tamperEngine) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
+	e.Finalize(chain, header, state, txs, uncles)
+	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
+	return types.NewBlock(header, txs, uncles, receipts, new(trie.Trie)), nil
+}
+
+// Tests that the reward audit reports an account credited in the state of a
+// checkpoint, even though it is neither recalculated nor stored as rewarded,
+// while the accounts the transactions of the checkpoint touched are not.
+func TestAuditEpochReward(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		thief  = common.HexToAddress("0x0000000000000000000000000000000000000bad")
+		db     = rawdb.NewMemoryDatabase()
+	)
+	config := *params.AllEthashProtocolChanges
+	config.Posv = &params.PosvConfig{Period: 2, Epoch: 2, Gap: 1}
+	config.Viction = &params.VictionConfig{
+		VRC25GasPrice:         (*math.Decimal256)(big.NewInt(2500)),
+		RewardPerEpoch:        (*math.Decimal256)(big.NewInt(params.Ether)),
+		ValidatorSignInterval: 1,
+	}
+	// Checkpoints list no validators, so the recalculated rewards are empty
+	extra := make([]byte, posv.ExtraVanity+posv.ExtraSeal)
+	gspec := &core.Genesis{
+		Config:    &config,
+		ExtraData: extra,
+		Alloc:     core.GenesisAlloc{sender: {Balance: big.NewInt(params.Ether)}},
+	}
+	genesis := gspec.MustCommit(db)
+	engine := tamperEngine{Engine: ethash.NewFullFaker(), number: 4, thief: thief, amount: big.NewInt(1000)}
+
+	// Import a chain whose first rewarded checkpoint credits the thief
+	signer := types.NewEIP155Signer(config.ChainID)
+	blocks, _ := core.GenerateChain(&config, genesis, engine, db, 6, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(sender), common.Address{byte(i + 1)}, big.NewInt(1), params.TxGas, big.NewInt(1), nil), signer, key)
+		b.AddTx(tx)
+		b.SetExtra(extra)
+	})
+	archive := &core.CacheConfig{TrieDirtyDisabled: true, Preimages: true}
+	imported, err := core.NewBlockChain(db, archive, &config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	if _, err := imported.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	imported.Stop()
+
+	// Audit the chain the way the command does
+	chain, err := core.NewBlockChain(db, archive, &config, auditEngine{posv.New(config.Posv, db)}, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create audit blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	diffs, err := auditEpochReward(db, chain, 4)
+	if err != nil {
+		t.Fatalf("failed to audit tampered checkpoint: %v", err)
+	}
+	if len(diffs) != 1 {
+		t.Fatalf("tampered checkpoint diff count mismatch: have %d, want 1", len(diffs))
+	}
+	if diff := diffs[0]; diff.Address != thief || diff.Expected.Sign() != 0 || diff.Actual.Cmp(engine.amount) != 0 || diff.Stored != nil {
+		t.Errorf("tampered checkpoint diff mismatch: have %x %v/%v/%v, want %x 0/%v/<nil>", diff.Address, diff.Expected, diff.Actual, diff.Stored, thief, engine.amount)
+	}
+	diffs, err = auditEpochReward(db, chain, 6)
+	if err != nil {
+		t.Fatalf("failed to audit checkpoint: %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("untampered checkpoint diff count mismatch: have %d, want 0", len(diffs))
+	}
+}
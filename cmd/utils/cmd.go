@@ -19,6 +19,8 @@ package utils
 
 import (
 	"compress/gzip"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -28,6 +30,7 @@ import (
 	"syscall"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/posv"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -237,6 +240,55 @@ func ExportAppendChain(blockchain *core.BlockChain, fn string, first uint64, las
 	return nil
 }
 
+// exportedEpochReward is the JSON form of the rewards of a checkpoint block written
+// by ExportEpochRewards.
+type exportedEpochReward struct {
+	Number uint64            `json:"number"`
+	Hash   common.Hash       `json:"hash"`
+	Reward *posv.EpochReward `json:"reward"`
+}
+
+// ExportEpochRewards exports the rewards distributed at the canonical checkpoint blocks
+// within the given range into the specified file as a stream of JSON objects, appending
+// to the file if data already exists in it.
+func ExportEpochRewards(blockchain *core.BlockChain, db ethdb.Database, fn string, first uint64, last uint64) error {
+	config := blockchain.Config().Posv
+	if config == nil || config.Epoch == 0 {
+		return errors.New("epoch rewards are only available on PoSV chains")
+	}
+	log.Info("Exporting epoch rewards", "file", fn)
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_APPEND|os.O_WRONLY, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	// Iterate over the checkpoints and export their rewards
+	enc := json.NewEncoder(writer)
+	for number := first + (config.Epoch-first%config.Epoch)%config.Epoch; number <= last; number += config.Epoch {
+		hash := blockchain.GetCanonicalHash(number)
+		if hash == (common.Hash{}) {
+			return fmt.Errorf("export failed on #%d: not found", number)
+		}
+		reward := posv.ReadEpochReward(db, hash, number)
+		if reward == nil {
+			continue
+		}
+		if err := enc.Encode(&exportedEpochReward{Number: number, Hash: hash, Reward: reward}); err != nil {
+			return err
+		}
+	}
+	log.Info("Exported epoch rewards", "file", fn)
+	return nil
+}
+
 // ImportPreimages imports a batch of exported hash preimages into the database.
 func ImportPreimages(db ethdb.Database, fn string) error {
 	log.Info("Importing preimages", "file", fn)
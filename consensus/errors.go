@@ -34,4 +34,8 @@ var (
 	// ErrInvalidNumber is returned if a block's number doesn't equal its parent's
 	// plus one.
 	ErrInvalidNumber = errors.New("invalid block number")
+
+	// ErrUnattestedBlock is returned when a double validated block is not yet
+	// signed by its attestor.
+	ErrUnattestedBlock = errors.New("block not attested")
 )
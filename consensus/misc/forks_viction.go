@@ -3,7 +3,6 @@ package misc
 import (
 	"math/big"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/vrc25"
 	"github.com/ethereum/go-ethereum/params"
@@ -11,27 +10,30 @@ import (
 
 // ApplySaigonHardFork mint additional token to EcoSystem Multisig preiodly for 4 years
 func ApplySaigonHardFork(statedb *state.StateDB, config *params.VictionConfig, saigonBlock *big.Int, headBlock *big.Int) {
-	if config.SaigonFundInterval == 0 {
-		return
+	if IsSaigonFundBlock(config, saigonBlock, headBlock) && config.SaigonFundAmount != nil {
+		ecoSystemFund := (*big.Int)(config.SaigonFundAmount)
+		statedb.AddBalance(config.SaigonFundAddress, ecoSystemFund)
+	}
+}
+
+// IsSaigonFundBlock reports whether the EcoSystem fund is minted at the head block,
+// which is the first block of each of the SaigonFundRepeat funding intervals.
+func IsSaigonFundBlock(config *params.VictionConfig, saigonBlock *big.Int, headBlock *big.Int) bool {
+	if config.SaigonFundInterval == 0 || headBlock.Cmp(saigonBlock) < 0 {
+		return false
 	}
 	endBlock := new(big.Int).Add(saigonBlock, new(big.Int).SetUint64(config.SaigonFundInterval*(config.SaigonFundRepeat-1))) // additional token will be minted at block 0 of each interval 4 intervals
-	if headBlock.Cmp(saigonBlock) < 0 || headBlock.Cmp(endBlock) > 0 {
-		return
+	if headBlock.Cmp(endBlock) > 0 {
+		return false
 	}
 	blockOfInterval := new(big.Int).Mod(new(big.Int).Sub(headBlock, saigonBlock), new(big.Int).SetUint64(config.SaigonFundInterval))
-	if blockOfInterval.Cmp(big.NewInt(0)) == 0 {
-		if config.SaigonFundAmount != nil {
-			ecoSystemFund := (*big.Int)(config.SaigonFundAmount)
-			statedb.AddBalance(config.SaigonFundAddress, ecoSystemFund)
-		}
-	}
+	return blockOfInterval.Sign() == 0
 }
 
 func ApplyVIPVRC25Upgrade(statedb *state.StateDB, config *params.VictionConfig, atlasBlock *big.Int, headBlock *big.Int) {
 	if headBlock.Cmp(atlasBlock) == 0 {
 		if config.AtlasVRC25MinCap != nil {
-			slotHash := common.BigToHash(new(big.Int).SetUint64(vrc25.SlotVRC25Contract["minCap"]))
-			statedb.SetState(config.VRC25Contract, slotHash, common.BigToHash((*big.Int)(config.AtlasVRC25MinCap)))
+			vrc25.NewRegistry(statedb, config.VRC25Contract).SetMinCap((*big.Int)(config.AtlasVRC25MinCap))
 		}
 	}
 }
@@ -0,0 +1,151 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package misc
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Transition is an irregular state transition, modifying the state at the start
+// of the blocks matched by its fork predicate, before any transaction is applied.
+type Transition struct {
+	Name   string                                                                    // Name the transition is listed under
+	Match  func(config *params.ChainConfig, number *big.Int) bool                    // Fork predicate selecting the blocks
+	Blocks func(config *params.ChainConfig) []*big.Int                               // Blocks selected by the fork predicate
+	Apply  func(config *params.ChainConfig, statedb *state.StateDB, number *big.Int) // State modification
+}
+
+// TransitionBlock is a block modified by an irregular state transition.
+type TransitionBlock struct {
+	Number uint64
+	Name   string
+}
+
+// transitions is the registry of irregular state transitions, in the order they
+// are applied within a block.
+var transitions = []Transition{
+	{
+		Name: "tip-signing",
+		Match: func(config *params.ChainConfig, number *big.Int) bool {
+			return config.Viction != nil && config.TIPSigningBlock != nil && config.TIPSigningBlock.Cmp(number) == 0
+		},
+		Blocks: func(config *params.ChainConfig) []*big.Int {
+			if config.Viction == nil || config.TIPSigningBlock == nil {
+				return nil
+			}
+			return []*big.Int{config.TIPSigningBlock}
+		},
+		Apply: func(config *params.ChainConfig, statedb *state.StateDB, number *big.Int) {
+			statedb.DeleteAddress(config.Viction.ValidatorBlockSignContract)
+		},
+	},
+	{
+		Name: "atlas-vrc25-mincap",
+		Match: func(config *params.ChainConfig, number *big.Int) bool {
+			return config.Viction != nil && config.Viction.AtlasVRC25MinCap != nil && config.AtlasBlock != nil && config.AtlasBlock.Cmp(number) == 0
+		},
+		Blocks: func(config *params.ChainConfig) []*big.Int {
+			if config.Viction == nil || config.Viction.AtlasVRC25MinCap == nil || config.AtlasBlock == nil {
+				return nil
+			}
+			return []*big.Int{config.AtlasBlock}
+		},
+		Apply: func(config *params.ChainConfig, statedb *state.StateDB, number *big.Int) {
+			ApplyVIPVRC25Upgrade(statedb, config.Viction, config.AtlasBlock, number)
+		},
+	},
+	{
+		Name: "saigon-fund",
+		Match: func(config *params.ChainConfig, number *big.Int) bool {
+			return config.Viction != nil && config.Viction.SaigonFundAmount != nil && config.SaigonBlock != nil && IsSaigonFundBlock(config.Viction, config.SaigonBlock, number)
+		},
+		Blocks: func(config *params.ChainConfig) []*big.Int {
+			if config.Viction == nil || config.Viction.SaigonFundAmount == nil || config.SaigonBlock == nil || config.Viction.SaigonFundInterval == 0 {
+				return nil
+			}
+			// The fund is minted at the first block of each funding interval
+			blocks := make([]*big.Int, 0, config.Viction.SaigonFundRepeat)
+			for i := uint64(0); i < config.Viction.SaigonFundRepeat; i++ {
+				offset := new(big.Int).SetUint64(i * config.Viction.SaigonFundInterval)
+				blocks = append(blocks, offset.Add(offset, config.SaigonBlock))
+			}
+			return blocks
+		},
+		Apply: func(config *params.ChainConfig, statedb *state.StateDB, number *big.Int) {
+			ApplySaigonHardFork(statedb, config.Viction, config.SaigonBlock, number)
+		},
+	},
+	{
+		Name: "dao",
+		Match: func(config *params.ChainConfig, number *big.Int) bool {
+			return config.DAOForkSupport && config.DAOForkBlock != nil && config.DAOForkBlock.Cmp(number) == 0
+		},
+		Blocks: func(config *params.ChainConfig) []*big.Int {
+			if !config.DAOForkSupport || config.DAOForkBlock == nil {
+				return nil
+			}
+			return []*big.Int{config.DAOForkBlock}
+		},
+		Apply: func(config *params.ChainConfig, statedb *state.StateDB, number *big.Int) {
+			ApplyDAOHardFork(statedb)
+		},
+	},
+}
+
+// Transitions returns the irregular state transitions matching the block.
+func Transitions(config *params.ChainConfig, number *big.Int) []Transition {
+	var matched []Transition
+	for _, transition := range transitions {
+		if transition.Match(config, number) {
+			matched = append(matched, transition)
+		}
+	}
+	return matched
+}
+
+// TransitionBlocks returns the blocks within [first, last] modified by irregular
+// state transitions, ordered by block number, then by the order the transitions
+// are applied within a block.
+func TransitionBlocks(config *params.ChainConfig, first, last uint64) []TransitionBlock {
+	var blocks []TransitionBlock
+	for _, transition := range transitions {
+		for _, number := range transition.Blocks(config) {
+			if number.IsUint64() && number.Uint64() >= first && number.Uint64() <= last {
+				blocks = append(blocks, TransitionBlock{Number: number.Uint64(), Name: transition.Name})
+			}
+		}
+	}
+	sort.SliceStable(blocks, func(i, j int) bool {
+		return blocks[i].Number < blocks[j].Number
+	})
+	return blocks
+}
+
+// ApplyTransitions modifies the state according to every irregular state transition
+// matching the block, returning the names of the applied transitions.
+func ApplyTransitions(config *params.ChainConfig, statedb *state.StateDB, number *big.Int) []string {
+	var names []string
+	for _, transition := range Transitions(config, number) {
+		transition.Apply(config, statedb, number)
+		names = append(names, transition.Name)
+	}
+	return names
+}
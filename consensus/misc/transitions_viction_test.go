@@ -0,0 +1,81 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package misc
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the irregular state transitions only match and modify the state of
+// the blocks selected by their fork predicates.
+func TestTransitions(t *testing.T) {
+	fund := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	config := &params.ChainConfig{
+		TIPSigningBlock: big.NewInt(10),
+		AtlasBlock:      big.NewInt(20),
+		SaigonBlock:     big.NewInt(20),
+		Viction: &params.VictionConfig{
+			AtlasVRC25MinCap:   (*math.Decimal256)(big.NewInt(7)),
+			SaigonFundAddress:  fund,
+			SaigonFundAmount:   (*math.Decimal256)(big.NewInt(1000)),
+			SaigonFundInterval: 5,
+			SaigonFundRepeat:   3,
+		},
+	}
+	tests := []struct {
+		number uint64
+		want   []string
+	}{
+		{9, nil},
+		{10, []string{"tip-signing"}},
+		{19, nil},
+		{20, []string{"atlas-vrc25-mincap", "saigon-fund"}},
+		{21, nil},
+		{25, []string{"saigon-fund"}},
+		{30, []string{"saigon-fund"}},
+		{35, nil},
+	}
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	for _, tt := range tests {
+		if have := ApplyTransitions(config, statedb, new(big.Int).SetUint64(tt.number)); !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("block #%d: applied transitions mismatch: have %v, want %v", tt.number, have, tt.want)
+		}
+	}
+	if balance := statedb.GetBalance(fund); balance.Cmp(big.NewInt(3000)) != 0 {
+		t.Errorf("saigon fund balance mismatch: have %v, want %v", balance, 3000)
+	}
+	// Listing the transitions of a range agrees with the ones applied at each block
+	want := []TransitionBlock{{10, "tip-signing"}, {20, "atlas-vrc25-mincap"}, {20, "saigon-fund"}, {25, "saigon-fund"}, {30, "saigon-fund"}}
+	if have := TransitionBlocks(config, 0, 100); !reflect.DeepEqual(have, want) {
+		t.Errorf("transition blocks mismatch: have %v, want %v", have, want)
+	}
+	if have := TransitionBlocks(config, 11, 25); !reflect.DeepEqual(have, want[1:4]) {
+		t.Errorf("ranged transition blocks mismatch: have %v, want %v", have, want[1:4])
+	}
+	// Chains without a Viction configuration only apply the Ethereum transitions
+	if have := Transitions(params.MainnetChainConfig, params.MainnetChainConfig.DAOForkBlock); len(have) != 1 || have[0].Name != "dao" {
+		t.Errorf("mainnet DAO block: matched transitions mismatch: have %v, want [dao]", have)
+	}
+}
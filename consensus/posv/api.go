@@ -21,6 +21,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -98,10 +99,179 @@ func (api *API) GetSignersAtHash(hash common.Hash) ([]common.Address, error) {
 	return snap.GetSigners(), nil
 }
 
-// [TO-DO] return nil for network information. --- IGNORE ---
+// ValidatorStats is the performance of a validator over an epoch.
+type ValidatorStats struct {
+	Created     uint64 `json:"created"`     // Number of blocks created
+	Signed      uint64 `json:"signed"`      // Number of blocks signed, counting only blocks due to be signed
+	MissedTurns uint64 `json:"missedTurns"` // Number of blocks created by another validator while in turn
+}
+
+// NetworkInformation returns the chain id and the addresses of the system contracts.
 func (api *API) NetworkInformation() NetworkInformation {
 	api.posv.lock.RLock()
 	defer api.posv.lock.RUnlock()
-	info := NetworkInformation{}
+
+	config := api.chain.Config()
+	info := NetworkInformation{
+		NetworkId: config.ChainID,
+	}
+	if config.Viction != nil {
+		info.TomoValidatorAddress = config.Viction.ValidatorContract
+		info.RelayerRegistrationAddress = config.Viction.RelayerContract
+		info.TomoXListingAddress = config.Viction.TomoXContract
+		info.TomoZAddress = config.Viction.VRC25Contract
+		info.LendingAddress = config.Viction.LendingContract
+	}
 	return info
 }
+
+// GetEpochReward retrieves the rewards of validators and their stakeholders
+// distributed at the given checkpoint block.
+func (api *API) GetEpochReward(number rpc.BlockNumber) (*EpochReward, error) {
+	header, err := api.checkpointHeader(number)
+	if err != nil {
+		return nil, err
+	}
+	if reward := ReadEpochReward(api.posv.db, header.Hash(), header.Number.Uint64()); reward != nil {
+		return reward, nil
+	}
+	// Rewards are not stored for blocks imported before they were persisted, compute them
+	config := api.chain.Config()
+	if api.posv.backend == nil || config.Viction == nil {
+		return nil, errMissingBackend
+	}
+	chain, ok := api.chain.(consensus.ChainReader)
+	if !ok {
+		return nil, errUnknownBlock
+	}
+	// Rewards do not touch the validator contract, so the state of the checkpoint
+	// holds the same stakes as the one the rewards were computed on.
+	statedb, err := api.posv.backend.PosvGetState(header)
+	if err != nil {
+		return nil, err
+	}
+	return api.posv.backend.PosvGetEpochReward(api.posv, config, api.posv.config, config.Viction, header, chain, statedb, log.Root())
+}
+
+// GetPenalties retrieves the validators penalized at the given checkpoint block.
+func (api *API) GetPenalties(number rpc.BlockNumber) ([]common.Address, error) {
+	header, err := api.checkpointHeader(number)
+	if err != nil {
+		return nil, err
+	}
+	return DecodePenaltiesFromHeader(header.Penalties), nil
+}
+
+// GetValidatorStats retrieves the number of blocks created, signed and missed by each
+// validator of the given epoch, which spans the blocks following the checkpoint block
+// epoch*Epoch up to the next checkpoint included.
+//
+// Sign transactions are collected up to the block before the checkpoint of the next
+// epoch, as it is done for rewards, so sign counts of recent epochs are still growing.
+func (api *API) GetValidatorStats(epoch uint64) (map[common.Address]*ValidatorStats, error) {
+	config := api.chain.Config()
+	if api.posv.backend == nil || config.Viction == nil {
+		return nil, errMissingBackend
+	}
+	chain, ok := api.chain.(consensus.ChainReader)
+	if !ok {
+		return nil, errUnknownBlock
+	}
+	var (
+		epochLength = api.posv.config.Epoch
+		first       = epoch*epochLength + 1
+		last        = (epoch + 1) * epochLength
+		head        = api.chain.CurrentHeader().Number.Uint64()
+	)
+	checkpoint := api.chain.GetHeaderByNumber(epoch * epochLength)
+	if checkpoint == nil || first > head {
+		return nil, errUnknownBlock
+	}
+	validators := ExtractValidatorsFromCheckpointHeader(checkpoint)
+	stats := make(map[common.Address]*ValidatorStats, len(validators))
+	for _, validator := range validators {
+		stats[validator] = new(ValidatorStats)
+	}
+	if len(validators) == 0 {
+		return stats, nil
+	}
+	// Count created blocks and turns missed by the validators in turn
+	parentCreator := common.Address{}
+	if epoch > 0 {
+		creator, err := api.posv.Author(checkpoint)
+		if err != nil {
+			return nil, err
+		}
+		parentCreator = creator
+	}
+	hashes := make(map[common.Hash]uint64)
+	for number := first; number <= last && number <= head; number++ {
+		header := api.chain.GetHeaderByNumber(number)
+		if header == nil {
+			return nil, errUnknownBlock
+		}
+		hashes[header.Hash()] = number
+
+		creator, err := api.posv.Author(header)
+		if err != nil {
+			return nil, err
+		}
+		if s, ok := stats[creator]; ok {
+			s.Created++
+		}
+		inturn := validators[(common.IndexOf(validators, parentCreator)+1)%len(validators)]
+		if inturn != creator {
+			stats[inturn].MissedTurns++
+		}
+		parentCreator = creator
+	}
+	// Count signs of the blocks due to be signed, once per validator and block
+	signed := make(map[common.Hash]map[common.Address]bool)
+	for number := first; number < last+epochLength && number <= head; number++ {
+		header := api.chain.GetHeaderByNumber(number)
+		if header == nil {
+			return nil, errUnknownBlock
+		}
+		signer := types.MakeSigner(config, header.Number)
+		for _, tx := range api.posv.backend.PosvGetBlockSignData(config, config.Viction, header, chain) {
+			data := tx.Data()
+			signedHash := common.BytesToHash(data[len(data)-common.HashLength:])
+			signedNumber, ok := hashes[signedHash]
+			if !ok {
+				continue
+			}
+			if config.IsTIP2019(new(big.Int).SetUint64(signedNumber)) && signedNumber%config.Viction.ValidatorSignInterval != 0 {
+				continue
+			}
+			from, err := types.Sender(signer, &tx)
+			if err != nil {
+				continue
+			}
+			if signed[signedHash] == nil {
+				signed[signedHash] = make(map[common.Address]bool)
+			}
+			if s, ok := stats[from]; ok && !signed[signedHash][from] {
+				signed[signedHash][from] = true
+				s.Signed++
+			}
+		}
+	}
+	return stats, nil
+}
+
+// checkpointHeader retrieves the header of the given block, ensuring it is a checkpoint.
+func (api *API) checkpointHeader(number rpc.BlockNumber) (*types.Header, error) {
+	var header *types.Header
+	if number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	if header.Number.Uint64()%api.posv.config.Epoch != 0 {
+		return nil, errNotCheckpointBlock
+	}
+	return header, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package posv_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/posv"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// invariantBackend is a PoSV backend computing epoch rewards which break their
+// invariant.
+type invariantBackend struct {
+	testerBackend
+}
+
+func (invariantBackend) PosvGetEpochReward(c *posv.Posv, config *params.ChainConfig, posvConfig *params.PosvConfig, vicConfig *params.VictionConfig,
+	header *types.Header, chain consensus.ChainReader, state *state.StateDB, logger log.Logger) (*posv.EpochReward, error) {
+	reward := &posv.EpochReward{StakholderRewards: map[common.Address]*big.Int{{0x01}: big.NewInt(2)}}
+	return reward, fmt.Errorf("%w: credited 2, epoch reward 1", posv.ErrRewardInvariant)
+}
+
+func (invariantBackend) PosvDistributeEpochRewards(header *types.Header, state *state.StateDB, epochReward *posv.EpochReward) error {
+	for addr, amount := range epochReward.StakholderRewards {
+		state.AddBalance(addr, amount)
+	}
+	return nil
+}
+
+// headerChain is a chain reader serving only its configuration, enough to finalize
+// a checkpoint with a backend not looking the chain up.
+type headerChain struct {
+	config *params.ChainConfig
+}
+
+func (hc *headerChain) Config() *params.ChainConfig                             { return hc.config }
+func (hc *headerChain) CurrentHeader() *types.Header                            { return nil }
+func (hc *headerChain) GetHeader(hash common.Hash, number uint64) *types.Header { return nil }
+func (hc *headerChain) GetHeaderByNumber(number uint64) *types.Header           { return nil }
+func (hc *headerChain) GetHeaderByHash(hash common.Hash) *types.Header          { return nil }
+func (hc *headerChain) GetBlock(hash common.Hash, number uint64) *types.Block   { return nil }
+
+// Tests that a checkpoint whose rewards break their invariant is still finalized,
+// but reported invalid in strict mode, so the block gets rejected.
+func TestFinalizeStrictRewards(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		config := &params.ChainConfig{
+			ChainID: big.NewInt(1),
+			Posv:    &params.PosvConfig{Period: 2, Epoch: 900, Gap: 5},
+			Viction: &params.VictionConfig{},
+		}
+		engine := posv.New(config.Posv, rawdb.NewMemoryDatabase())
+		engine.SetBackend(invariantBackend{})
+		engine.SetStrictRewards(strict)
+
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		header := &types.Header{
+			Number: big.NewInt(1800),
+			Extra:  make([]byte, posv.ExtraVanity+posv.ExtraSeal),
+		}
+		engine.Finalize(&headerChain{config: config}, header, statedb, nil, nil)
+
+		if balance := statedb.GetBalance(common.Address{0x01}); balance.Cmp(big.NewInt(2)) != 0 {
+			t.Errorf("strict %v: reward balance mismatch: have %v, want 2", strict, balance)
+		}
+		err := engine.FinalizeError(header)
+		switch {
+		case strict && !errors.Is(err, posv.ErrRewardInvariant):
+			t.Errorf("strict mode: finalize error mismatch: have %v, want %v", err, posv.ErrRewardInvariant)
+		case !strict && err != nil:
+			t.Errorf("lenient mode: unexpected finalize error: %v", err)
+		}
+	}
+}
+
+// Tests that a node in strict mode doesn't assemble, thus seal, a checkpoint whose
+// rewards break their invariant, which it would reject on import.
+func TestFinalizeAndAssembleStrictRewards(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		config := &params.ChainConfig{
+			ChainID: big.NewInt(1),
+			Posv:    &params.PosvConfig{Period: 2, Epoch: 900, Gap: 5},
+			Viction: &params.VictionConfig{},
+		}
+		engine := posv.New(config.Posv, rawdb.NewMemoryDatabase())
+		engine.SetBackend(invariantBackend{})
+		engine.SetStrictRewards(strict)
+
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		header := &types.Header{
+			Number: big.NewInt(1800),
+			Extra:  make([]byte, posv.ExtraVanity+posv.ExtraSeal),
+		}
+		block, err := engine.FinalizeAndAssemble(&headerChain{config: config}, header, statedb, nil, nil, nil)
+		switch {
+		case strict && (block != nil || !errors.Is(err, posv.ErrRewardInvariant)):
+			t.Errorf("strict mode: assembled block %v, error %v, want %v", block, err, posv.ErrRewardInvariant)
+		case !strict && (block == nil || err != nil):
+			t.Errorf("lenient mode: failed to assemble block: %v", err)
+		}
+	}
+}
@@ -13,7 +13,6 @@ import (
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
-	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -30,25 +29,22 @@ import (
 const (
 	inmemorySnapshots      = 128 // Number of recent vote snapshots to keep in memory
 	blockSignersCacheLimit = 9000
+	inmemoryEpochRewards   = 16          // Number of recent epoch rewards to keep in memory until their blocks are written
 	epochLength            = uint64(900) // Default number of blocks after which to checkpoint and reset the pending votes
 	M2ByteLength           = 4
 	AddressLength          = uint64(20)             // Length of an address
 	ExtraVanity            = 32                     // Fixed number of extra-data prefix bytes reserved for signer vanity
 	ExtraSeal              = crypto.SignatureLength // Fixed number of extra-data suffix bytes reserved for signer seal
-
-	wiggleTime = 500 * time.Millisecond // Random delay (per signer) to allow concurrent signers
 )
 
+// SignerFn hashes and signs the data to be signed by a backing account.
+type SignerFn func(signer accounts.Account, mimeType string, message []byte) ([]byte, error)
+
 type Masternode struct {
 	Address common.Address
 	Stake   *big.Int
 }
 
-var (
-	diffInTurn = big.NewInt(2) // Block difficulty for in-turn signatures
-	diffNoTurn = big.NewInt(1) // Block difficulty for out-of-turn signatures
-)
-
 // Various error messages to mark blocks invalid. These should be private to
 // prevent engine specific errors from being referenced in the remainder of the
 // codebase, inherently breaking if the engine is swapped out. Please put common
@@ -113,6 +109,14 @@ var (
 
 	errInvalidBlockAttestor = errors.New("invalid block attestor")
 
+	// errMissingAttestorSignature is returned if a block's attestor field doesn't
+	// contain a 65 byte secp256k1 signature.
+	errMissingAttestorSignature = errors.New("attestor 65 byte signature missing")
+
+	// errMissingChainReader is returned if the engine is handed a chain it can't
+	// read blocks from.
+	errMissingChainReader = errors.New("chain reader required")
+
 	// errInvalidVotingChain is returned if an authorization list is attempted to
 	// be modified via out-of-range or non-contiguous headers.
 	errInvalidVotingChain = errors.New("invalid voting chain")
@@ -121,6 +125,14 @@ var (
 	errRecentlySigned = errors.New("recently signed")
 
 	errEmptyValidators = errors.New("validators is empty")
+
+	// errNotCheckpointBlock is returned if a checkpoint is requested for a block
+	// whose number is not a multiple of the epoch length.
+	errNotCheckpointBlock = errors.New("not a checkpoint block")
+
+	// errMissingBackend is returned if an operation requiring chain state is invoked
+	// before the backend is set.
+	errMissingBackend = errors.New("posv backend not set")
 )
 
 // sigHash returns the hash which is used as input for the proof-of-stake-voting
@@ -175,6 +187,34 @@ func ecrecover(header *types.Header, sigcache *lru.ARCCache) (common.Address, er
 	return signer, nil
 }
 
+// ecrecoverAttestor extracts the Ethereum account address of the attestor from
+// the signature stored in the Attestor field of a double validated header.
+func ecrecoverAttestor(header *types.Header, sigcache *lru.ARCCache) (common.Address, error) {
+	// Blocks waiting for their attestor are valid otherwise, tell them apart
+	if len(header.Attestor) == 0 {
+		return common.Address{}, consensus.ErrUnattestedBlock
+	}
+	if len(header.Attestor) != ExtraSeal {
+		return common.Address{}, errMissingAttestorSignature
+	}
+	// If the signature's already cached, return that. The attestor is not part of
+	// the header hash, so the signature is keyed along with it
+	key := string(append(header.Hash().Bytes(), header.Attestor...))
+	if address, known := sigcache.Get(key); known {
+		return address.(common.Address), nil
+	}
+	// Recover the public key and the Ethereum address
+	pubkey, err := crypto.Ecrecover(sigHash(header).Bytes(), header.Attestor)
+	if err != nil {
+		return common.Address{}, err
+	}
+	var attestor common.Address
+	copy(attestor[:], crypto.Keccak256(pubkey[1:])[12:])
+
+	sigcache.Add(key, attestor)
+	return attestor, nil
+}
+
 // Posv is the proof-of-stake-voting consensus engine proposed to support the
 // Ethereum testnet following the Ropsten attacks.
 type Posv struct {
@@ -185,14 +225,18 @@ type Posv struct {
 	signatures       *lru.ARCCache           // Signatures of recent blocks to speed up mining
 	attestSignatures *lru.ARCCache           // Signatures of recent blocks to speed up mining
 	verifiedBlocks   *lru.ARCCache           // Status of recent blocks to speed up syncing
+	epochRewards     *lru.ARCCache           // Rewards distributed by recently finalized checkpoint blocks
+	rewardErrors     *lru.ARCCache           // Reward invariant violations of recently finalized checkpoint blocks
 	proposals        map[common.Address]bool // Current list of proposals we are pushing
 
-	signer common.Address  // Ethereum address of the signing key
-	signFn clique.SignerFn // Signer function to authorize hashes with
-	lock   sync.RWMutex    // Protects the signer fields
+	signer common.Address // Ethereum address of the signing key
+	signFn SignerFn       // Signer function to authorize hashes with
+	lock   sync.RWMutex   // Protects the signer fields
 
 	BlockSigners *lru.Cache
 
+	strictRewards bool // Whether to reject the checkpoint blocks whose rewards break their invariant
+
 	// Hook for posv
 	backend PosvBackend
 }
@@ -211,6 +255,8 @@ func New(config *params.PosvConfig, db ethdb.Database) *Posv {
 	signatures, _ := lru.NewARC(inmemorySnapshots)
 	attestSignatures, _ := lru.NewARC(inmemorySnapshots)
 	verifiedBlocks, _ := lru.NewARC(inmemorySnapshots)
+	epochRewards, _ := lru.NewARC(inmemoryEpochRewards)
+	rewardErrors, _ := lru.NewARC(inmemoryEpochRewards)
 	return &Posv{
 		config:           &conf,
 		db:               db,
@@ -219,6 +265,8 @@ func New(config *params.PosvConfig, db ethdb.Database) *Posv {
 		signatures:       signatures,
 		verifiedBlocks:   verifiedBlocks,
 		attestSignatures: attestSignatures,
+		epochRewards:     epochRewards,
+		rewardErrors:     rewardErrors,
 		proposals:        make(map[common.Address]bool),
 	}
 }
@@ -230,6 +278,13 @@ func (c *Posv) SetBackend(backend PosvBackend) {
 	c.backend = backend
 }
 
+// SetStrictRewards sets whether the checkpoint blocks crediting more rewards than the
+// reward of the epoch are rejected, instead of only reporting it.
+// Must be called right after creation of PoSV.
+func (c *Posv) SetStrictRewards(strict bool) {
+	c.strictRewards = strict
+}
+
 // GetValidators returns the list of validators for the given header.
 // This is a public method to access validators from the backend.
 func (c *Posv) GetValidators(vicConfig *params.VictionConfig, header *types.Header, chain consensus.ChainReader) ([]common.Address, error) {
@@ -247,8 +302,9 @@ func (c *Posv) GetEpoch() uint64 {
 	return epochLength // Default epoch length
 }
 
+// Attestor returns the Ethereum address recovered from the attestor signature of the header.
 func (c *Posv) Attestor(header *types.Header) (common.Address, error) {
-	return ecrecover(header, c.attestSignatures)
+	return ecrecoverAttestor(header, c.attestSignatures)
 }
 
 // SealHash returns the hash of a block prior to it being sealed.
@@ -328,7 +384,10 @@ func (c *Posv) IsMyTurn(signer common.Address, parentNumber uint64, parentHash c
 // Prepare implements consensus.Engine, preparing all the consensus fields of the
 // header for running the transactions on top.
 func (c *Posv) Prepare(chainH consensus.ChainHeaderReader, header *types.Header) error {
-	chain := chainH.(consensus.ChainReader)
+	chain, ok := chainH.(consensus.ChainReader)
+	if !ok {
+		return errMissingChainReader
+	}
 
 	// If the block isn't a checkpoint, cast a random vote (good enough for now)
 	header.Coinbase = common.Address{}
@@ -425,13 +484,17 @@ func (c *Posv) Prepare(chainH consensus.ChainHeaderReader, header *types.Header)
 }
 
 // FinalizeAndAssemble implements consensus.Engine, applying finalization and returning the block.
+// Blocks the processor would reject as finalized, in strict rewards mode, are not assembled.
 func (c *Posv) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction,
 	uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
 	c.Finalize(chain, header, state, txs, uncles)
+	if err := c.FinalizeError(header); err != nil {
+		return nil, err
+	}
 	return types.NewBlock(header, txs, nil, receipts, new(trie.Trie)), nil
 }
 
-// Close implements consensus.Engine. It's a noop for clique as there are no background threads.
+// Close implements consensus.Engine. It's a noop for posv as there are no background threads.
 func (c *Posv) Close() error {
 	return nil
 }
@@ -441,6 +504,10 @@ func (c *Posv) Close() error {
 // Skips block 900 (1*epoch); only calculates and applies at blocks 1800, 2700, ... (2*epoch, 3*epoch, ...).
 func (c *Posv) Finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header) {
 	config := chain.Config()
+	var (
+		epochReward *EpochReward
+		rewardErr   error
+	)
 	if config != nil && config.Posv != nil && config.Viction != nil {
 		number := header.Number.Uint64()
 		epoch := config.Posv.Epoch
@@ -448,8 +515,16 @@ func (c *Posv) Finalize(chain consensus.ChainHeaderReader, header *types.Header,
 		// Apply epoch rewards only at checkpoint blocks, skipping the first checkpoint (e.g. 900).
 		if epoch > 0 && number%epoch == 0 && number > epoch {
 			chainReader := chain.(consensus.ChainReader)
-			epochReward, err := c.backend.PosvGetEpochReward(c, config, config.Posv, config.Viction, header, chainReader, state, log.Root())
-			if err != nil {
+			var err error
+			epochReward, err = c.backend.PosvGetEpochReward(c, config, config.Posv, config.Viction, header, chainReader, state, log.Root())
+			if errors.Is(err, ErrRewardInvariant) {
+				// The rewards are still applied to stay in consensus with the rest of the
+				// network, unless strict mode has the block rejected by the processor
+				if c.strictRewards {
+					rewardErr = err
+				}
+				log.Error("Finalize: epoch reward invariant violated", "block", number, "err", err)
+			} else if err != nil {
 				log.Warn("Finalize: epoch reward failed", "block", number, "err", err)
 			}
 			err = c.backend.PosvDistributeEpochRewards(header, state, epochReward)
@@ -462,6 +537,57 @@ func (c *Posv) Finalize(chain consensus.ChainHeaderReader, header *types.Header,
 	// Always update header fields after any state modifications.
 	header.Root = state.IntermediateRoot(config != nil && config.IsEIP158(header.Number))
 	header.UncleHash = types.CalcUncleHash(nil)
+
+	// Remember the rewards until the block is written, the seal is not known yet
+	if epochReward != nil && len(header.Extra) >= ExtraSeal {
+		c.epochRewards.Add(SealHash(header), epochReward)
+	}
+	if rewardErr != nil && len(header.Extra) >= ExtraSeal {
+		c.rewardErrors.Add(SealHash(header), rewardErr)
+	}
+}
+
+// FinalizeError returns the reason the given header, as finalized, is invalid, which
+// Finalize can't return. It is only set in strict rewards mode, for the checkpoints
+// whose rewards break their invariant.
+func (c *Posv) FinalizeError(header *types.Header) error {
+	if len(header.Extra) < ExtraSeal {
+		return nil
+	}
+	if err, ok := c.rewardErrors.Get(SealHash(header)); ok {
+		return err.(error)
+	}
+	return nil
+}
+
+// FinalizedEpochReward returns the rewards distributed when the given checkpoint header
+// was finalized, or nil if it distributed no rewards or was not finalized recently.
+func (c *Posv) FinalizedEpochReward(header *types.Header) *EpochReward {
+	if len(header.Extra) < ExtraSeal {
+		return nil
+	}
+	if reward, ok := c.epochRewards.Get(SealHash(header)); ok {
+		return reward.(*EpochReward)
+	}
+	return nil
+}
+
+// WriteFinalizedEpochReward stores the rewards distributed by the given checkpoint
+// block when it was finalized, if any, into the database.
+func (c *Posv) WriteFinalizedEpochReward(db ethdb.KeyValueWriter, block *types.Block) {
+	if reward := c.FinalizedEpochReward(block.Header()); reward != nil {
+		WriteEpochReward(db, block.Hash(), block.NumberU64(), reward)
+	}
+}
+
+// CalcEpochReward computes the rewards the given checkpoint header distributes on
+// top of the state its transactions were applied to, the way Finalize does.
+func (c *Posv) CalcEpochReward(chain consensus.ChainReader, header *types.Header, statedb *state.StateDB) (*EpochReward, error) {
+	config := chain.Config()
+	if c.backend == nil || config.Viction == nil {
+		return nil, errMissingBackend
+	}
+	return c.backend.PosvGetEpochReward(c, config, c.config, config.Viction, header, chain, statedb, log.Root())
 }
 
 // APIs implements consensus.Engine, returning the user facing RPC API to allow
@@ -475,9 +601,23 @@ func (c *Posv) APIs(chain consensus.ChainHeaderReader) []rpc.API {
 	}}
 }
 
+// Authorize injects a private key into the consensus engine to mint new blocks
+// with.
+func (c *Posv) Authorize(signer common.Address, signFn SignerFn) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.signer = signer
+	c.signFn = signFn
+}
+
 // Seal implements consensus.Engine, attempting to create a sealed block using
 // the local signing credentials.
-func (c *Posv) Seal(chain consensus.ChainHeaderReader, block *types.Block, results chan<- *types.Block, stop <-chan struct{}) error {
+func (c *Posv) Seal(chainH consensus.ChainHeaderReader, block *types.Block, results chan<- *types.Block, stop <-chan struct{}) error {
+	chain, ok := chainH.(consensus.ChainReader)
+	if !ok {
+		return errMissingChainReader
+	}
 	header := block.Header()
 
 	// Sealing the genesis block is not supported
@@ -498,31 +638,50 @@ func (c *Posv) Seal(chain consensus.ChainHeaderReader, block *types.Block, resul
 	c.lock.RUnlock()
 
 	// Bail out if we're unauthorized to sign a block
+	parent := chain.GetHeader(header.ParentHash, number-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
 	snap, err := c.snapshot(chain, number-1, header.ParentHash, nil)
 	if err != nil {
 		return err
 	}
-	if _, authorized := snap.Signers[signer]; !authorized {
-		return fmt.Errorf("Posv.Seal: %w", errUnauthorizedSigner)
+	validators, err := c.authorizeCreator(chain, header, parent, snap, signer)
+	if err != nil {
+		return fmt.Errorf("Posv.Seal: %w", err)
 	}
-	// If we're amongst the recent signers, wait for the next block
-	for seen, recent := range snap.Recents {
-		if recent == signer {
-			// Signer is among RecentsRLP, only wait if the current block doesn't shift it out
-			if limit := uint64(len(snap.Signers)/2 + 1); number < limit || seen > number-limit {
-				log.Info("Signed recently, must wait for others")
-				return nil
-			}
+	// If we created the parent block, wait for the next block
+	if c.signedRecently(snap, validators, number, signer) {
+		log.Info("Signed recently, must wait for others")
+		return nil
+	}
+	// Refuse blocks whose difficulty does not match our turn, they would be rejected
+	inturn, currentIndex, parentIndex, validatorCount, err := c.IsMyTurn(signer, number-1, header.ParentHash, chain)
+	if err != nil {
+		return err
+	}
+	if header.Difficulty.Cmp(c.calcDifficulty(signer, number-1, header.ParentHash, chain)) != 0 {
+		return errInvalidDifficulty
+	}
+	// Past the first epoch blocks are double validated. The creator only attests its
+	// own blocks when it is paired with itself, the assigned attestor signing them
+	// on import otherwise
+	attest := false
+	if number > c.config.Epoch {
+		attestor, err := c.assignedAttestor(chain, header, signer)
+		if err != nil {
+			return fmt.Errorf("Posv.Seal: %w", err)
 		}
+		attest = attestor == signer
 	}
 	// Sweet, the protocol permits us to sign the block, wait for our time
 	delay := time.Unix(int64(header.Time), 0).Sub(time.Now()) // nolint: gosimple
-	if header.Difficulty.Cmp(diffNoTurn) == 0 {
-		// It's not our turn explicitly to sign, delay it a bit
-		wiggle := time.Duration(len(snap.Signers)/2+1) * wiggleTime
-		delay += time.Duration(rand.Int63n(int64(wiggle))) // nolint: gosec
+	if !inturn {
+		// It's not our turn, leave the validators before us the time to create it
+		backoff := c.backoff(currentIndex, parentIndex, validatorCount)
+		delay += backoff
 
-		log.Trace("Out-of-turn signing requested", "wiggle", common.PrettyDuration(wiggle))
+		log.Trace("Out-of-turn signing requested", "backoff", common.PrettyDuration(backoff))
 	}
 	// Sign all the things!
 	sighash, err := signFn(accounts.Account{Address: signer}, accounts.MimetypePosv, PosvRLP(header))
@@ -530,6 +689,9 @@ func (c *Posv) Seal(chain consensus.ChainHeaderReader, block *types.Block, resul
 		return err
 	}
 	copy(header.Extra[len(header.Extra)-ExtraSeal:], sighash)
+	if attest {
+		header.Attestor = common.CopyBytes(sighash)
+	}
 	// Wait until sealing is terminated or delay timeout.
 	log.Trace("Waiting for slot to sign and propagate", "delay", common.PrettyDuration(delay))
 	go func() {
@@ -549,6 +711,50 @@ func (c *Posv) Seal(chain consensus.ChainHeaderReader, block *types.Block, resul
 	return nil
 }
 
+// Attest signs a double validated block created by another validator if the local
+// signer is its assigned attestor, returning the block unchanged otherwise. The
+// attestor is not part of the block hash, so the attested block keeps its identity.
+func (c *Posv) Attest(chain consensus.ChainReader, block *types.Block) (*types.Block, error) {
+	header := block.Header()
+	if header.Number.Uint64() <= c.config.Epoch || len(header.Attestor) != 0 {
+		return block, nil
+	}
+	c.lock.RLock()
+	signer, signFn := c.signer, c.signFn
+	c.lock.RUnlock()
+
+	if signFn == nil {
+		return block, nil
+	}
+	creator, err := ecrecover(header, c.signatures)
+	if err != nil {
+		return nil, err
+	}
+	attestor, err := c.assignedAttestor(chain, header, creator)
+	if err != nil {
+		return nil, err
+	}
+	if attestor != signer {
+		return block, nil
+	}
+	sighash, err := signFn(accounts.Account{Address: signer}, accounts.MimetypePosv, PosvRLP(header))
+	if err != nil {
+		return nil, err
+	}
+	header.Attestor = sighash
+	return block.WithSeal(header), nil
+}
+
+// backoff returns how long an out-of-turn validator waits past the block time, one
+// period for each validator between the in-turn one and itself.
+func (c *Posv) backoff(currentIndex, parentIndex, validatorCount int) time.Duration {
+	distance := validatorCount
+	if currentIndex != -1 {
+		distance = Distance(currentIndex, parentIndex, validatorCount)
+	}
+	return time.Duration(distance-1) * time.Duration(c.config.Period) * time.Second
+}
+
 // CalcDifficulty is the difficulty adjustment algorithm. It returns the difficulty
 // that a new block should have based on the previous blocks in the chain and the
 // current signer.
@@ -18,13 +18,19 @@
 package posv
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"strconv"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 )
@@ -33,6 +39,10 @@ const (
 	attestorHeaderItemLength = 4
 )
 
+// ErrRewardInvariant is returned if the rewards credited at a checkpoint block exceed
+// the reward of the epoch.
+var ErrRewardInvariant = errors.New("credited rewards exceed epoch reward")
+
 // EpochReward stores number of sign made by each validator and rewards for
 // all stakeholders (validators and voters) in an epoch.
 type EpochReward struct {
@@ -45,6 +55,29 @@ type ValidatorReward struct {
 	Reward *big.Int `json:"reward"`
 }
 
+// ReadEpochReward retrieves the rewards distributed at the given checkpoint block from the database.
+func ReadEpochReward(db ethdb.KeyValueReader, hash common.Hash, number uint64) *EpochReward {
+	data := rawdb.ReadEpochReward(db, hash, number)
+	if len(data) == 0 {
+		return nil
+	}
+	reward := new(EpochReward)
+	if err := json.Unmarshal(data, reward); err != nil {
+		log.Error("Invalid epoch reward JSON", "hash", hash, "number", number, "err", err)
+		return nil
+	}
+	return reward
+}
+
+// WriteEpochReward stores the rewards distributed at the given checkpoint block into the database.
+func WriteEpochReward(db ethdb.KeyValueWriter, hash common.Hash, number uint64, reward *EpochReward) {
+	data, err := json.Marshal(reward)
+	if err != nil {
+		log.Crit("Failed to JSON encode epoch reward", "err", err)
+	}
+	rawdb.WriteEpochReward(db, hash, number, data)
+}
+
 type PosvBackend interface {
 	// Get attestors from list of validators.
 	PosvGetAttestors(vicConfig params.VictionConfig, header *types.Header, validators []common.Address) ([]int64, error)
@@ -65,6 +98,9 @@ type PosvBackend interface {
 	// Penalize validators for creating bad block or not creating block at all.
 	PosvGetPenalties(c *Posv, config *params.ChainConfig, posvConfig *params.PosvConfig, vicConfig *params.VictionConfig, header *types.Header, chain consensus.ChainReader) ([]common.Address, error)
 
+	// Get the state at the given header.
+	PosvGetState(header *types.Header) (*state.StateDB, error)
+
 	// Get eligble validators from the state.
 	PosvGetValidators(vicConfig *params.VictionConfig, header *types.Header, chain consensus.ChainReader) ([]common.Address, error)
 }
@@ -80,6 +116,21 @@ func GetCheckpointHeader(posvConfig *params.PosvConfig, header *types.Header, ch
 	return prevCheckpointHeader
 }
 
+// Return the gap block header whose state decided the validators allowed to seal the given block.
+// Blocks of the first epoch are sealed by the genesis validators, so genesis header is returned.
+func GetGapHeader(posvConfig *params.PosvConfig, header *types.Header, chain consensus.ChainHeaderReader) *types.Header {
+	blockNumber := header.Number.Uint64()
+	if blockNumber == 0 {
+		return chain.GetHeaderByNumber(0)
+	}
+	// Checkpoint blocks are still sealed by the validators of the previous epoch
+	checkpointBlockNumber := (blockNumber - 1) - ((blockNumber - 1) % posvConfig.Epoch)
+	if checkpointBlockNumber < posvConfig.Gap {
+		return chain.GetHeaderByNumber(0)
+	}
+	return chain.GetHeaderByNumber(checkpointBlockNumber - posvConfig.Gap)
+}
+
 // Encode list of attestor numbers into bytes following format of Block.Attestors.
 func EncodeAttestorsForHeader(attestors []int64) []byte {
 	var attestorsBuff []byte
@@ -90,6 +141,21 @@ func EncodeAttestorsForHeader(attestors []int64) []byte {
 	return attestorsBuff
 }
 
+// Decode bytes with format of Block.NewAttestors into list of attestor numbers.
+func DecodeAttestorsFromHeader(attestorsBuff []byte) ([]int64, error) {
+	attestorCount := len(attestorsBuff) / attestorHeaderItemLength
+	attestors := make([]int64, attestorCount)
+	for i := 0; i < attestorCount; i++ {
+		attestorBuff := bytes.Trim(attestorsBuff[i*attestorHeaderItemLength:(i+1)*attestorHeaderItemLength], "\x00")
+		attestor, err := strconv.ParseInt(string(attestorBuff), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		attestors[i] = attestor
+	}
+	return attestors, nil
+}
+
 // Encode list of penalized addresses into bytes following format of Block.Penalties.
 func EncodePenaltiesForHeader(penalties []common.Address) []byte {
 	var penaltiesBuff []byte
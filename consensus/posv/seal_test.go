@@ -0,0 +1,350 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package posv_test
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/posv"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// testerBackend is a PoSV backend for chains within their first epoch, which
+// never need the rewards and whose validator contract holds the genesis set.
+type testerBackend struct{}
+
+var errTesterBackend = errors.New("not available in the first epoch")
+
+func (testerBackend) PosvGetAttestors(vicConfig params.VictionConfig, header *types.Header, validators []common.Address) ([]int64, error) {
+	return nil, errTesterBackend
+}
+
+func (testerBackend) PosvGetBlockSignData(config *params.ChainConfig, vicConfig *params.VictionConfig, header *types.Header, chain consensus.ChainReader) []types.Transaction {
+	return nil
+}
+
+func (testerBackend) PosvGetCreatorAttestorPairs(c *posv.Posv, config *params.ChainConfig, header, checkpointHeader *types.Header) (map[common.Address]common.Address, uint64, error) {
+	return nil, 0, errTesterBackend
+}
+
+func (testerBackend) PosvGetEpochReward(c *posv.Posv, config *params.ChainConfig, posvConfig *params.PosvConfig, vicConfig *params.VictionConfig,
+	header *types.Header, chain consensus.ChainReader, state *state.StateDB, logger log.Logger) (*posv.EpochReward, error) {
+	return nil, errTesterBackend
+}
+
+func (testerBackend) PosvDistributeEpochRewards(header *types.Header, state *state.StateDB, epochReward *posv.EpochReward) error {
+	return errTesterBackend
+}
+
+func (testerBackend) PosvGetPenalties(c *posv.Posv, config *params.ChainConfig, posvConfig *params.PosvConfig, vicConfig *params.VictionConfig, header *types.Header, chain consensus.ChainReader) ([]common.Address, error) {
+	return nil, errTesterBackend
+}
+
+func (testerBackend) PosvGetState(header *types.Header) (*state.StateDB, error) {
+	return nil, errTesterBackend
+}
+
+func (testerBackend) PosvGetValidators(vicConfig *params.VictionConfig, header *types.Header, chain consensus.ChainReader) ([]common.Address, error) {
+	return posv.ExtractValidatorsFromCheckpointHeader(chain.GetHeaderByNumber(0)), nil
+}
+
+// testerChain is a simulated PoSV chain whose genesis validators seal blocks with
+// their own engines, while an engine without signer verifies them.
+type testerChain struct {
+	db      ethdb.Database
+	config  *params.ChainConfig
+	backend posv.PosvBackend
+	chain   *core.BlockChain
+	keys    []*ecdsa.PrivateKey
+	signers []common.Address
+}
+
+func newTesterChain(t *testing.T, validators int) *testerChain {
+	return newEpochTesterChain(t, validators, 900, testerBackend{})
+}
+
+// newEpochTesterChain creates a simulated PoSV chain with the given epoch length,
+// whose engines read the chain state through the backend.
+func newEpochTesterChain(t *testing.T, validators int, epoch uint64, backend posv.PosvBackend) *testerChain {
+	tc := &testerChain{
+		db: rawdb.NewMemoryDatabase(),
+		config: &params.ChainConfig{
+			ChainID:        big.NewInt(1),
+			HomesteadBlock: big.NewInt(0),
+			EIP150Block:    big.NewInt(0),
+			EIP155Block:    big.NewInt(0),
+			EIP158Block:    big.NewInt(0),
+			ByzantiumBlock: big.NewInt(0),
+			Posv:           &params.PosvConfig{Period: 1, Epoch: epoch, Gap: 1},
+			Viction:        &params.VictionConfig{},
+		},
+		backend: backend,
+	}
+	extra := make([]byte, posv.ExtraVanity)
+	for i := 0; i < validators; i++ {
+		key, _ := crypto.GenerateKey()
+		tc.keys = append(tc.keys, key)
+		tc.signers = append(tc.signers, crypto.PubkeyToAddress(key.PublicKey))
+		extra = append(extra, tc.signers[i].Bytes()...)
+	}
+	genesis := &core.Genesis{
+		Config:     tc.config,
+		ExtraData:  append(extra, make([]byte, posv.ExtraSeal)...),
+		GasLimit:   params.GenesisGasLimit,
+		Difficulty: big.NewInt(1),
+	}
+	genesis.MustCommit(tc.db)
+
+	chain, err := core.NewBlockChain(tc.db, nil, tc.config, tc.engine(-1), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	tc.chain = chain
+	return tc
+}
+
+// engine creates a PoSV engine sealing with the key of the validator, or only
+// verifying for a negative index.
+func (tc *testerChain) engine(validator int) *posv.Posv {
+	engine := posv.New(tc.config.Posv, tc.db)
+	engine.SetBackend(tc.backend)
+	if validator >= 0 {
+		key := tc.keys[validator]
+		engine.Authorize(tc.signers[validator], func(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+			return crypto.Sign(crypto.Keccak256(data), key)
+		})
+	}
+	return engine
+}
+
+// prepare assembles a block on top of the head with the given engine.
+func (tc *testerChain) prepare(t *testing.T, engine *posv.Posv) *types.Block {
+	parent := tc.chain.CurrentBlock()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number(), common.Big1),
+		GasLimit:   parent.GasLimit(),
+	}
+	if err := engine.Prepare(tc.chain, header); err != nil {
+		t.Fatalf("failed to prepare block #%d: %v", header.Number, err)
+	}
+	statedb, err := tc.chain.StateAt(parent.Root())
+	if err != nil {
+		t.Fatalf("failed to get parent state: %v", err)
+	}
+	block, err := engine.FinalizeAndAssemble(tc.chain, header, statedb, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to assemble block #%d: %v", header.Number, err)
+	}
+	return block
+}
+
+// seal lets every online validator seal a block on top of the head, and imports
+// the first sealed one once attested by the online validators, returning its
+// creator. Blocks whose attestor is offline must be rejected.
+func (tc *testerChain) seal(t *testing.T, online []int) common.Address {
+	var (
+		engines = make([]*posv.Posv, len(online))
+		results = make(chan *types.Block, len(online))
+		stop    = make(chan struct{})
+	)
+	defer close(stop)
+
+	for i, validator := range online {
+		engines[i] = tc.engine(validator)
+		if err := engines[i].Seal(tc.chain, tc.prepare(t, engines[i]), results, stop); err != nil {
+			t.Fatalf("validator %d failed to seal: %v", validator, err)
+		}
+	}
+	timeout := time.After(time.Duration(len(tc.signers)+1) * time.Duration(tc.config.Posv.Period) * time.Second)
+	for {
+		select {
+		case block := <-results:
+			for _, engine := range engines {
+				attested, err := engine.Attest(tc.chain, block)
+				if err != nil {
+					t.Fatalf("failed to attest block #%d: %v", block.Number(), err)
+				}
+				block = attested
+			}
+			if _, err := tc.chain.InsertChain(types.Blocks{block}); err != nil {
+				if errors.Is(err, consensus.ErrUnattestedBlock) && len(block.Header().Attestor) == 0 {
+					continue
+				}
+				t.Fatalf("sealed block #%d rejected: %v", block.Number(), err)
+			}
+			creator, err := tc.engine(-1).Author(block.Header())
+			if err != nil {
+				t.Fatalf("failed to recover creator of block #%d: %v", block.Number(), err)
+			}
+			return creator
+		case <-timeout:
+			t.Fatalf("no block #%d sealed within the turns of the validators", tc.chain.CurrentBlock().NumberU64()+1)
+		}
+	}
+}
+
+// Tests that the chain keeps being sealed while validators are offline, the next
+// online validators taking over the turns of the offline ones.
+func TestSealRotation(t *testing.T) {
+	tests := []struct {
+		validators int
+		online     []int
+		creators   []int
+	}{
+		// All validators online seal in turn
+		{validators: 3, online: []int{0, 1, 2}, creators: []int{0, 1, 2, 0}},
+		// The validator after an offline one takes its turn
+		{validators: 3, online: []int{0, 1}, creators: []int{0, 1, 0, 1}},
+		// The creator of the parent cannot take the turn, the next online one does
+		{validators: 5, online: []int{0, 3, 4}, creators: []int{0, 3, 4, 0}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run("", func(t *testing.T) {
+			t.Parallel()
+
+			tc := newTesterChain(t, tt.validators)
+			defer tc.chain.Stop()
+
+			for i, want := range tt.creators {
+				if have := tc.seal(t, tt.online); have != tc.signers[want] {
+					t.Fatalf("block #%d: creator mismatch: have %x, want validator %d (%x)", i+1, have, want, tc.signers[want])
+				}
+			}
+		})
+	}
+}
+
+// Tests that validators refuse to seal the blocks the verifier would reject.
+func TestSealRejected(t *testing.T) {
+	t.Parallel()
+
+	tc := newTesterChain(t, 3)
+	defer tc.chain.Stop()
+
+	tc.seal(t, []int{0})
+
+	// The creator of the parent must not create the next block
+	var (
+		results = make(chan *types.Block, 1)
+		stop    = make(chan struct{})
+	)
+	defer close(stop)
+	if err := tc.engine(0).Seal(tc.chain, tc.prepare(t, tc.engine(0)), results, stop); err != nil {
+		t.Fatalf("failed to seal: %v", err)
+	}
+	// A block prepared for another validator's turn must not be sealed
+	if err := tc.engine(2).Seal(tc.chain, tc.prepare(t, tc.engine(1)), results, stop); err == nil || !strings.Contains(err.Error(), "invalid difficulty") {
+		t.Errorf("sealing another validator's block: error mismatch: have %v, want invalid difficulty", err)
+	}
+	// Accounts outside of the validators must not seal at all
+	outsider := newTesterChain(t, 1)
+	defer outsider.chain.Stop()
+	engine := posv.New(tc.config.Posv, tc.db)
+	engine.SetBackend(testerBackend{})
+	engine.Authorize(outsider.signers[0], func(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(data), outsider.keys[0])
+	})
+	if err := engine.Seal(tc.chain, tc.prepare(t, engine), results, stop); err == nil || !strings.Contains(err.Error(), "unauthorized signer") {
+		t.Errorf("sealing by an outsider: error mismatch: have %v, want unauthorized signer", err)
+	}
+	select {
+	case block := <-results:
+		t.Fatalf("block #%d sealed by the creator of its parent", block.Number())
+	case <-time.After(time.Duration(len(tc.signers)) * time.Duration(tc.config.Posv.Period) * time.Second):
+	}
+}
+
+// attestingBackend is a PoSV backend pairing every validator with an attestor,
+// and distributing no rewards at the checkpoints.
+type attestingBackend struct {
+	testerBackend
+	pairs map[common.Address]common.Address
+}
+
+func (attestingBackend) PosvGetAttestors(vicConfig params.VictionConfig, header *types.Header, validators []common.Address) ([]int64, error) {
+	return nil, nil
+}
+
+func (b attestingBackend) PosvGetCreatorAttestorPairs(c *posv.Posv, config *params.ChainConfig, header, checkpointHeader *types.Header) (map[common.Address]common.Address, uint64, error) {
+	return b.pairs, 0, nil
+}
+
+func (attestingBackend) PosvGetEpochReward(c *posv.Posv, config *params.ChainConfig, posvConfig *params.PosvConfig, vicConfig *params.VictionConfig,
+	header *types.Header, chain consensus.ChainReader, state *state.StateDB, logger log.Logger) (*posv.EpochReward, error) {
+	return nil, nil
+}
+
+func (attestingBackend) PosvDistributeEpochRewards(header *types.Header, state *state.StateDB, epochReward *posv.EpochReward) error {
+	return nil
+}
+
+func (attestingBackend) PosvGetPenalties(c *posv.Posv, config *params.ChainConfig, posvConfig *params.PosvConfig, vicConfig *params.VictionConfig, header *types.Header, chain consensus.ChainReader) ([]common.Address, error) {
+	return nil, nil
+}
+
+// Tests that the chain keeps being sealed past the checkpoints while a validator is
+// offline. Blocks are attested by the validators they are paired with on import, so
+// the blocks the offline validator must attest are replaced by other creators'.
+func TestSealAttested(t *testing.T) {
+	t.Parallel()
+
+	backend := attestingBackend{pairs: make(map[common.Address]common.Address)}
+	tc := newEpochTesterChain(t, 4, 3, backend)
+	defer tc.chain.Stop()
+
+	// Pair every validator with the next one, the last one being offline
+	for i, signer := range tc.signers {
+		backend.pairs[signer] = tc.signers[(i+1)%len(tc.signers)]
+	}
+	online := []int{0, 1, 2}
+
+	// Cross the first checkpoint and the next two ones
+	for number := uint64(1); number <= 10; number++ {
+		creator := tc.seal(t, online)
+		if creator == tc.signers[3] {
+			t.Fatalf("block #%d created by the offline validator", number)
+		}
+		if number <= tc.config.Posv.Epoch {
+			continue
+		}
+		if creator == tc.signers[2] {
+			t.Fatalf("block #%d created by the validator paired with the offline one", number)
+		}
+		attestor, err := tc.engine(-1).Attestor(tc.chain.CurrentHeader())
+		if err != nil || attestor != backend.pairs[creator] {
+			t.Fatalf("block #%d: attestor mismatch: have %x (%v), want %x", number, attestor, err, backend.pairs[creator])
+		}
+	}
+}
@@ -30,6 +30,10 @@ import (
 	lru "github.com/hashicorp/golang-lru"
 )
 
+// recentsLimit is the distance within which a creator is remembered as recent.
+// Only the creator of the parent block is barred from creating the next one.
+const recentsLimit = 2
+
 // Snapshot is the state of the authorization voting at a given point in time.
 type Snapshot struct {
 	config   *params.PosvConfig // Consensus engine parameters to fine tune behavior
@@ -195,7 +199,7 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 			snap.Tally = make(map[common.Address]clique.Tally)
 		}
 		// Delete the oldest signer from the recent list to allow it signing again
-		if limit := uint64(len(snap.Signers)/2 + 1); number >= limit {
+		if limit := uint64(recentsLimit); number >= limit {
 			delete(snap.Recents, number-limit)
 		}
 		// Resolve the authorization key and check against signers
@@ -251,7 +255,7 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 				delete(snap.Signers, header.Coinbase)
 
 				// Signer list shrunk, delete any leftover recent caches
-				if limit := uint64(len(snap.Signers)/2 + 1); number >= limit {
+				if limit := uint64(recentsLimit); number >= limit {
 					delete(snap.Recents, number-limit)
 				}
 				// Discard any previous votes the deauthorized signer cast
@@ -136,7 +136,10 @@ func (c *Posv) verifyCascadingFields(chain consensus.ChainHeaderReader, header *
 
 	// If the block is a checkpoint block, verify the signer list
 	if number%c.config.Epoch == 0 {
-		chain := chain.(consensus.ChainReader)
+		chain, ok := chain.(consensus.ChainReader)
+		if !ok {
+			return errMissingChainReader
+		}
 		err := c.verifyValidators(chain, header, parents)
 		if err != nil {
 			log.Debug("Failed to verify validators", "number", number, "err", err)
@@ -215,7 +218,10 @@ func (c *Posv) verifyValidators(chain consensus.ChainReader, header *types.Heade
 // verifySeal checks whether the signature contained in the header satisfies the
 // consensus protocol requirements.
 func (c *Posv) verifySeal(chainH consensus.ChainHeaderReader, header *types.Header, snap *Snapshot) error {
-	chain := chainH.(consensus.ChainReader)
+	chain, ok := chainH.(consensus.ChainReader)
+	if !ok {
+		return errMissingChainReader
+	}
 	// Verifying the genesis block is not supported
 	number := header.Number.Uint64()
 	if number == 0 {
@@ -226,44 +232,29 @@ func (c *Posv) verifySeal(chainH consensus.ChainHeaderReader, header *types.Head
 		return nil
 	}
 	// Resolve the authorization key and check against signers
-	validators, err := c.backend.PosvGetValidators(chain.Config().Viction, header, chain)
-	if err != nil {
-		log.Debug("Failed to get validators", "number", number, "err", err)
-		return err
-	}
 	creator, err := ecrecover(header, c.signatures)
 	if err != nil {
 		log.Debug("Failed to recover signer", "number", number, "err", err)
 		return err
 	}
-
-	if _, ok := snap.Signers[creator]; !ok {
-		if common.IndexOf(validators, creator) == -1 {
-			return errUnauthorizedSigner
-		}
+	parent := chain.GetHeader(header.ParentHash, number-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
 	}
-
-	for seen, recent := range snap.Recents {
-		if len(validators) <= 1 {
-			break
-		}
-		if recent == creator {
-			// Signer is among RecentsRLP, only fail if the current block doesn't shift it out
-			// There is only case that we don't allow signer to create two continuous blocks.
-			if limit := uint64(2); seen > number-limit {
-				// Only take into account the non-epoch blocks
-				if number%c.config.Epoch != 0 {
-					return errUnauthorizedSigner
-				}
-			}
+	if snap == nil {
+		if snap, err = c.snapshot(chain, number-1, header.ParentHash, nil); err != nil {
+			return err
 		}
 	}
+	validators, err := c.authorizeCreator(chain, header, parent, snap, creator)
+	if err != nil {
+		return err
+	}
+	if c.signedRecently(snap, validators, number, creator) {
+		return errUnauthorizedSigner
+	}
 
 	// Ensure that the difficulty corresponds to the turn-ness of the signer
-	parent := chain.GetHeader(header.ParentHash, number-1)
-	if parent == nil {
-		return consensus.ErrUnknownAncestor
-	}
 	difficulty := c.calcDifficulty(creator, parent.Number.Uint64(), parent.Hash(), chain)
 	if header.Difficulty.Int64() != difficulty.Int64() {
 		return errInvalidDifficulty
@@ -275,20 +266,76 @@ func (c *Posv) verifySeal(chainH consensus.ChainHeaderReader, header *types.Head
 		if err != nil {
 			return err
 		}
-
-		checkpointHeader := GetCheckpointHeader(c.config, parent, chain)
-		valAttPairs, _, err := c.backend.PosvGetCreatorAttestorPairs(c, chain.Config(), header, checkpointHeader)
+		assignedAttestor, err := c.assignedAttestor(chain, header, creator)
 		if err != nil {
 			return err
 		}
-		assignedAttestor, ok := valAttPairs[creator]
-		if !ok || attestor != assignedAttestor {
+		if attestor != assignedAttestor {
 			return errInvalidBlockAttestor
 		}
 	}
 	return nil
 }
 
+// assignedAttestor returns the validator paired with the creator to attest the
+// block, checkpoint blocks being attested following the pairs they introduce.
+func (c *Posv) assignedAttestor(chain consensus.ChainReader, header *types.Header, creator common.Address) (common.Address, error) {
+	if c.backend == nil {
+		return common.Address{}, errMissingBackend
+	}
+	checkpointHeader := GetCheckpointHeader(c.config, header, chain)
+	if checkpointHeader == nil {
+		return common.Address{}, consensus.ErrUnknownAncestor
+	}
+	valAttPairs, _, err := c.backend.PosvGetCreatorAttestorPairs(c, chain.Config(), header, checkpointHeader)
+	if err != nil {
+		return common.Address{}, err
+	}
+	attestor, ok := valAttPairs[creator]
+	if !ok {
+		return common.Address{}, errInvalidBlockAttestor
+	}
+	return attestor, nil
+}
+
+// authorizeCreator checks the creator is one of the validators allowed to create
+// the block on top of the parent, returning the validators of the epoch.
+func (c *Posv) authorizeCreator(chain consensus.ChainReader, header, parent *types.Header, snap *Snapshot, creator common.Address) ([]common.Address, error) {
+	validators := ExtractValidatorsFromCheckpointHeader(GetCheckpointHeader(c.config, parent, chain))
+	if _, ok := snap.Signers[creator]; ok || common.IndexOf(validators, creator) != -1 {
+		return validators, nil
+	}
+	// Fallback to the validator contract at the gap block that decided the current epoch
+	if c.backend == nil {
+		return nil, errMissingBackend
+	}
+	gapHeader := GetGapHeader(c.config, header, chain)
+	validators, err := c.backend.PosvGetValidators(chain.Config().Viction, gapHeader, chain)
+	if err != nil {
+		log.Debug("Failed to get validators", "number", header.Number, "err", err)
+		return nil, err
+	}
+	if common.IndexOf(validators, creator) == -1 {
+		return nil, errUnauthorizedSigner
+	}
+	return validators, nil
+}
+
+// signedRecently reports whether the creator signed the parent block. Creating two
+// blocks in a row is only allowed to a single validator, or for checkpoint blocks.
+func (c *Posv) signedRecently(snap *Snapshot, validators []common.Address, number uint64, creator common.Address) bool {
+	if len(validators) <= 1 || number%c.config.Epoch == 0 {
+		return false
+	}
+	for seen, recent := range snap.Recents {
+		// Signer is among RecentsRLP, only fail if the current block doesn't shift it out
+		if recent == creator && seen > number-recentsLimit {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Posv) snapshot(chain consensus.ChainHeaderReader, number uint64, hash common.Hash, parents []*types.Header) (*Snapshot, error) {
 	// Search for a snapshot in memory or on disk for checkpoints
 	var (
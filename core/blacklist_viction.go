@@ -0,0 +1,100 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// legacyBypassLastBlock is the last block in which the balance of blacklisted accounts
+// may be overridden before their transactions are applied.
+const legacyBypassLastBlock = 9147459
+
+// BlacklistProvider decides which addresses are not allowed to send or receive
+// transactions, and which historical balances are overridden before applying them.
+type BlacklistProvider interface {
+	// IsBlacklisted reports whether the address is blacklisted in the block with the
+	// given number. The state the block is applied on is used by on-chain sources, it
+	// may be nil in which case they are not consulted.
+	IsBlacklisted(number *big.Int, statedb *state.StateDB, addr common.Address) bool
+
+	// BypassBalance returns the balance the sender must be given before applying its
+	// transaction in the block with the given number, or nil to leave it untouched.
+	BypassBalance(number *big.Int, addr common.Address) *big.Int
+}
+
+// chainBlacklist is the BlacklistProvider of a chain configuration. It combines the
+// compiled-in legacy blacklist enforced from TIPBlacklist with the optional list and
+// contract of the Viction configuration.
+type chainBlacklist struct {
+	config *params.ChainConfig
+}
+
+// NewBlacklistProvider returns the blacklist provider defined by the chain configuration.
+func NewBlacklistProvider(config *params.ChainConfig) BlacklistProvider {
+	return &chainBlacklist{config: config}
+}
+
+// IsBlacklisted implements BlacklistProvider.
+func (b *chainBlacklist) IsBlacklisted(number *big.Int, statedb *state.StateDB, addr common.Address) bool {
+	vicConfig := b.config.Viction
+	if vicConfig == nil {
+		return false
+	}
+	if b.config.IsTIPBlacklist(number) && vicConfig.IsBlacklisted(addr) {
+		return true
+	}
+	for _, entry := range vicConfig.Blacklist {
+		if entry.Address == addr && entry.Contains(number.Uint64()) {
+			return true
+		}
+	}
+	if statedb != nil && vicConfig.BlacklistContract != (common.Address{}) && number.Uint64() >= vicConfig.BlacklistContractBlock {
+		slot := state.GetStorageKeyForMapping(addr.Hash(), 0)
+		if statedb.GetState(vicConfig.BlacklistContract, slot) != (common.Hash{}) {
+			return true
+		}
+	}
+	return false
+}
+
+// BypassBalance implements BlacklistProvider.
+func (b *chainBlacklist) BypassBalance(number *big.Int, addr common.Address) *big.Int {
+	if b.config.Viction == nil {
+		return nil
+	}
+	if len(b.config.Viction.BypassBalances) == 0 && number.Cmp(big.NewInt(legacyBypassLastBlock)) > 0 {
+		return nil
+	}
+	return b.config.Viction.GetVictionBypassBalance(number.Uint64(), addr)
+}
+
+// ValidateBlacklist rejects transactions sent from or to an address blacklisted in the
+// block with the given number.
+func ValidateBlacklist(blacklist BlacklistProvider, number *big.Int, statedb *state.StateDB, from common.Address, to *common.Address) error {
+	if blacklist.IsBlacklisted(number, statedb, from) {
+		return ErrBlacklistedAddress
+	}
+	if to != nil && blacklist.IsBlacklisted(number, statedb, *to) {
+		return ErrBlacklistedAddress
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the chain blacklist combines the legacy, configured and contract sources,
+// each within the block range it is enforced in.
+func TestChainBlacklist(t *testing.T) {
+	var (
+		legacy   = common.HexToAddress("0x5248bfb72fd4f234e062d3e9bb76f08643004fcd")
+		listed   = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		banned   = common.HexToAddress("0x1000000000000000000000000000000000000002")
+		clean    = common.HexToAddress("0x1000000000000000000000000000000000000003")
+		contract = common.HexToAddress("0x2000000000000000000000000000000000000000")
+	)
+	config := &params.ChainConfig{
+		TIPBlacklistBlock: big.NewInt(100),
+		Viction: &params.VictionConfig{
+			Blacklist: []params.BlacklistEntry{
+				{Address: listed, Start: 50, End: 150},
+			},
+			BlacklistContract:      contract,
+			BlacklistContractBlock: 200,
+		},
+	}
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetState(contract, state.GetStorageKeyForMapping(banned.Hash(), 0), common.BigToHash(common.Big1))
+
+	blacklist := NewBlacklistProvider(config)
+	tests := []struct {
+		number  int64
+		addr    common.Address
+		statedb *state.StateDB
+		want    bool
+	}{
+		{99, legacy, statedb, false},
+		{100, legacy, statedb, true},
+		{49, listed, statedb, false},
+		{50, listed, statedb, true},
+		{149, listed, statedb, true},
+		{150, listed, statedb, false},
+		{199, banned, statedb, false},
+		{200, banned, statedb, true},
+		{200, banned, nil, false},
+		{200, clean, statedb, false},
+	}
+	for i, tt := range tests {
+		if have := blacklist.IsBlacklisted(big.NewInt(tt.number), tt.statedb, tt.addr); have != tt.want {
+			t.Errorf("test %d: address %x at #%d: have %v, want %v", i, tt.addr, tt.number, have, tt.want)
+		}
+	}
+	if err := ValidateBlacklist(blacklist, big.NewInt(200), statedb, clean, &banned); err != ErrBlacklistedAddress {
+		t.Errorf("transaction to blacklisted address: have %v, want %v", err, ErrBlacklistedAddress)
+	}
+	if err := ValidateBlacklist(blacklist, big.NewInt(200), statedb, clean, nil); err != nil {
+		t.Errorf("contract creation from clean address: have %v, want nil", err)
+	}
+	// Chains without a Viction configuration blacklist nothing
+	if NewBlacklistProvider(&params.ChainConfig{TIPBlacklistBlock: common.Big0}).IsBlacklisted(big.NewInt(100), statedb, legacy) {
+		t.Errorf("legacy address blacklisted without Viction configuration")
+	}
+}
+
+// Tests that the legacy balances are only overridden up to the last bypassed block.
+func TestChainBlacklistBypassBalance(t *testing.T) {
+	addr := common.HexToAddress("0x5248bfb72fd4f234e062d3e9bb76f08643004fcd")
+	blacklist := NewBlacklistProvider(&params.ChainConfig{Viction: &params.VictionConfig{}})
+
+	if balance := blacklist.BypassBalance(big.NewInt(9073579), addr); balance == nil || balance.Sign() <= 0 {
+		t.Errorf("bypass balance at #9073579: have %v, want positive", balance)
+	}
+	if balance := blacklist.BypassBalance(big.NewInt(9073580), addr); balance != nil {
+		t.Errorf("bypass balance at #9073580: have %v, want nil", balance)
+	}
+	if balance := blacklist.BypassBalance(big.NewInt(legacyBypassLastBlock+1), addr); balance != nil {
+		t.Errorf("bypass balance after the last bypassed block: have %v, want nil", balance)
+	}
+	// Configured balances are overridden past the legacy ones
+	blacklist = NewBlacklistProvider(&params.ChainConfig{Viction: &params.VictionConfig{
+		BypassBalances: []params.BypassBalanceEntry{{Address: addr, Block: 20000000, Balance: (*math.Decimal256)(big.NewInt(1000))}},
+	}})
+	if balance := blacklist.BypassBalance(big.NewInt(20000000), addr); balance == nil || balance.Int64() != 1000 {
+		t.Errorf("configured bypass balance: have %v, want 1000", balance)
+	}
+	if balance := blacklist.BypassBalance(big.NewInt(20000001), addr); balance != nil {
+		t.Errorf("configured bypass balance in another block: have %v, want nil", balance)
+	}
+	if balance := blacklist.BypassBalance(big.NewInt(9073579), addr); balance == nil || balance.Sign() <= 0 {
+		t.Errorf("legacy bypass balance along configured ones: have %v, want positive", balance)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// epochRewardWriter is implemented by the consensus engines persisting the rewards
+// distributed by the checkpoint blocks they finalized.
+type epochRewardWriter interface {
+	WriteFinalizedEpochReward(db ethdb.KeyValueWriter, block *types.Block)
+}
+
+// writeEpochReward stores the rewards distributed by the block along with its data,
+// if the block is a checkpoint finalized by the engine.
+func (bc *BlockChain) writeEpochReward(db ethdb.KeyValueWriter, block *types.Block) {
+	if writer, ok := bc.engine.(epochRewardWriter); ok {
+		writer.WriteFinalizedEpochReward(db, block)
+	}
+}
@@ -66,6 +66,12 @@ func (b *BlockGen) SetExtra(data []byte) {
 	b.header.Extra = data
 }
 
+// SetPenalties sets the penalties field of the generated block. This method is
+// useful for PoSV tests where checkpoint blocks record penalized validators.
+func (b *BlockGen) SetPenalties(penalties []byte) {
+	b.header.Penalties = penalties
+}
+
 // SetNonce sets the nonce field of the generated block.
 func (b *BlockGen) SetNonce(nonce types.BlockNonce) {
 	b.header.Nonce = nonce
@@ -204,9 +210,8 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 				}
 			}
 		}
-		if config.DAOForkSupport && config.DAOForkBlock != nil && config.DAOForkBlock.Cmp(b.header.Number) == 0 {
-			misc.ApplyDAOHardFork(statedb)
-		}
+		misc.ApplyTransitions(config, statedb, b.header.Number)
+
 		// Execute any user modifications to the block
 		if gen != nil {
 			gen(i, b)
@@ -0,0 +1,52 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ReadEpochReward retrieves the encoded rewards distributed at a checkpoint block.
+// The encoding is left to the consensus engine owning the rewards.
+func ReadEpochReward(db ethdb.KeyValueReader, hash common.Hash, number uint64) []byte {
+	data, _ := db.Get(epochRewardKey(number, hash))
+	return data
+}
+
+// HasEpochReward verifies the existence of the rewards distributed at a checkpoint block.
+func HasEpochReward(db ethdb.Reader, hash common.Hash, number uint64) bool {
+	if has, err := db.Has(epochRewardKey(number, hash)); !has || err != nil {
+		return false
+	}
+	return true
+}
+
+// WriteEpochReward stores the encoded rewards distributed at a checkpoint block.
+func WriteEpochReward(db ethdb.KeyValueWriter, hash common.Hash, number uint64, data []byte) {
+	if err := db.Put(epochRewardKey(number, hash), data); err != nil {
+		log.Crit("Failed to store epoch reward", "err", err)
+	}
+}
+
+// DeleteEpochReward removes the rewards distributed at a checkpoint block.
+func DeleteEpochReward(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(epochRewardKey(number, hash)); err != nil {
+		log.Crit("Failed to delete epoch reward", "err", err)
+	}
+}
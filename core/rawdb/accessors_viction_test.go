@@ -0,0 +1,52 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tests epoch reward storage and pruning along with side chain blocks.
+func TestEpochRewardStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	hash, reward := common.Hash{0x01}, []byte(`{"signers":{},"rewards":{}}`)
+	if entry := ReadEpochReward(db, hash, 900); entry != nil {
+		t.Fatalf("Non existent epoch reward returned: %s", entry)
+	}
+	// Write and verify the reward in the database
+	WriteEpochReward(db, hash, 900, reward)
+	if !HasEpochReward(db, hash, 900) {
+		t.Fatalf("Stored epoch reward not found")
+	}
+	if entry := ReadEpochReward(db, hash, 900); !bytes.Equal(entry, reward) {
+		t.Fatalf("Retrieved epoch reward mismatch: have %s, want %s", entry, reward)
+	}
+	// Frozen canonical blocks keep their rewards
+	DeleteBlockWithoutNumber(db, hash, 900)
+	if entry := ReadEpochReward(db, hash, 900); !bytes.Equal(entry, reward) {
+		t.Fatalf("Epoch reward of frozen block mismatch: have %s, want %s", entry, reward)
+	}
+	// Deleted blocks drop their rewards
+	DeleteBlock(db, hash, 900)
+	if entry := ReadEpochReward(db, hash, 900); entry != nil {
+		t.Fatalf("Deleted epoch reward returned: %s", entry)
+	}
+}
@@ -283,6 +283,7 @@ func InspectDatabase(db ethdb.Database) error {
 		headers         stat
 		bodies          stat
 		receipts        stat
+		epochRewards    stat
 		tds             stat
 		numHashPairings stat
 		hashNumPairings stat
@@ -327,6 +328,8 @@ func InspectDatabase(db ethdb.Database) error {
 			bodies.Add(size)
 		case bytes.HasPrefix(key, blockReceiptsPrefix) && len(key) == (len(blockReceiptsPrefix)+8+common.HashLength):
 			receipts.Add(size)
+		case bytes.HasPrefix(key, epochRewardPrefix) && len(key) == (len(epochRewardPrefix)+8+common.HashLength):
+			epochRewards.Add(size)
 		case bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerTDSuffix):
 			tds.Add(size)
 		case bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerHashSuffix):
@@ -390,6 +393,7 @@ func InspectDatabase(db ethdb.Database) error {
 		{"Key-Value store", "Headers", headers.Size(), headers.Count()},
 		{"Key-Value store", "Bodies", bodies.Size(), bodies.Count()},
 		{"Key-Value store", "Receipt lists", receipts.Size(), receipts.Count()},
+		{"Key-Value store", "Epoch rewards", epochRewards.Size(), epochRewards.Count()},
 		{"Key-Value store", "Difficulties", tds.Size(), tds.Count()},
 		{"Key-Value store", "Block number->hash", numHashPairings.Size(), numHashPairings.Count()},
 		{"Key-Value store", "Block hash->number", hashNumPairings.Size(), hashNumPairings.Count()},
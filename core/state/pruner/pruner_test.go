@@ -0,0 +1,187 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pruner
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// prunableContract stores the number of each block at its slot of that number.
+var prunableContract = common.HexToAddress("0xc0de")
+
+// newPrunableChain creates an archive chain with a state snapshot, whose blocks
+// each write a storage slot of a contract, and returns the canonical blocks.
+func newPrunableChain(t *testing.T, db ethdb.Database) []*types.Block {
+	var (
+		key, _  = crypto.GenerateKey()
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				address:          {Balance: big.NewInt(params.Ether)},
+				prunableContract: {Balance: new(big.Int), Code: []byte{byte(vm.NUMBER), byte(vm.NUMBER), byte(vm.SSTORE)}},
+			},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	)
+	blocks, _ := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, 200, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(address), prunableContract, new(big.Int), 100000, big.NewInt(1), nil), signer, key)
+		b.AddTx(tx)
+	})
+	cacheConfig := &core.CacheConfig{
+		TrieCleanLimit:    256,
+		TrieDirtyLimit:    256,
+		TrieDirtyDisabled: true,
+		TrieTimeLimit:     5 * time.Minute,
+		SnapshotLimit:     256,
+		SnapshotWait:      true,
+	}
+	chain, err := core.NewBlockChain(db, cacheConfig, params.TestChainConfig, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	chain.Stop() // Journals the snapshot layers
+	return append([]*types.Block{genesis}, blocks...)
+}
+
+// newTestPruner creates a pruner with a small bloom filter.
+func newTestPruner(t *testing.T, db ethdb.Database, datadir string) *Pruner {
+	head := rawdb.ReadHeadBlock(db)
+	snaptree, err := snapshot.New(db, trie.NewDatabase(db), 256, head.Root(), false, false, false)
+	if err != nil {
+		t.Fatalf("failed to open snapshot: %v", err)
+	}
+	bloom, err := newStateBloomWithSize(1)
+	if err != nil {
+		t.Fatalf("failed to create bloom: %v", err)
+	}
+	return &Pruner{db: db, stateBloom: bloom, datadir: datadir, headHeader: head.Header(), snaptree: snaptree}
+}
+
+// checkPruned checks that only the target and genesis states are left complete,
+// and that the snapshot was flattened into the target.
+func checkPruned(t *testing.T, db ethdb.Database, datadir string, blocks []*types.Block, target int) {
+	for _, n := range []int{0, target} {
+		tr, err := trie.NewSecure(blocks[n].Root(), trie.NewDatabase(db))
+		if err != nil {
+			t.Fatalf("state of block #%d missing: %v", n, err)
+		}
+		for it := tr.NodeIterator(nil); it.Next(true); {
+			if it.Error() != nil {
+				t.Fatalf("state of block #%d incomplete: %v", n, it.Error())
+			}
+		}
+	}
+	statedb, err := state.New(blocks[target].Root(), state.NewDatabase(db), nil)
+	if err != nil {
+		t.Fatalf("failed to open target state: %v", err)
+	}
+	slot := common.BigToHash(big.NewInt(int64(target)))
+	if value := statedb.GetState(prunableContract, slot); value != slot {
+		t.Errorf("storage of target state mismatch: have %x, want %x", value, slot)
+	}
+	if err := statedb.Error(); err != nil {
+		t.Errorf("storage of target state incomplete: %v", err)
+	}
+	for _, n := range []int{1, target - 1, target + 1, len(blocks) - 1} {
+		if blob := rawdb.ReadTrieNode(db, blocks[n].Root()); len(blob) != 0 {
+			t.Errorf("state root of block #%d not pruned", n)
+		}
+	}
+	if root := rawdb.ReadSnapshotRoot(db); root != blocks[target].Root() {
+		t.Errorf("snapshot root mismatch: have %x, want %x", root, blocks[target].Root())
+	}
+	if path, _, _ := findBloomFilter(datadir); path != "" {
+		t.Errorf("state bloom left behind: %s", path)
+	}
+}
+
+// Tests that pruning keeps the HEAD-127 and genesis states only.
+func TestPrune(t *testing.T) {
+	datadir, err := ioutil.TempDir("", "pruner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(datadir)
+
+	db := rawdb.NewMemoryDatabase()
+	blocks := newPrunableChain(t, db)
+
+	if err := newTestPruner(t, db, datadir).Prune(common.Hash{}); err != nil {
+		t.Fatalf("failed to prune: %v", err)
+	}
+	checkPruned(t, db, datadir, blocks, len(blocks)-128)
+}
+
+// Tests that a pruning interrupted after committing its state bloom resumes from
+// the bloom.
+func TestRecoverPruning(t *testing.T) {
+	datadir, err := ioutil.TempDir("", "pruner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(datadir)
+
+	db := rawdb.NewMemoryDatabase()
+	blocks := newPrunableChain(t, db)
+	target := len(blocks) - 128
+
+	// Commit the bloom of the target state, then delete a part of the stale state
+	p := newTestPruner(t, db, datadir)
+	if err := snapshot.GenerateTrie(p.snaptree, blocks[target].Root(), db, p.stateBloom); err != nil {
+		t.Fatalf("failed to generate state: %v", err)
+	}
+	if err := extractGenesis(db, p.stateBloom); err != nil {
+		t.Fatalf("failed to extract genesis: %v", err)
+	}
+	name := bloomFilterName(datadir, blocks[target].Root())
+	if err := p.stateBloom.Commit(name, name+stateBloomFileTempSuffix); err != nil {
+		t.Fatalf("failed to commit bloom: %v", err)
+	}
+	db.Delete(blocks[1].Root().Bytes())
+
+	if err := RecoverPruning(datadir, db, ""); err != nil {
+		t.Fatalf("failed to recover pruning: %v", err)
+	}
+	checkPruned(t, db, datadir, blocks, target)
+
+	// Nothing is left to recover afterwards
+	if err := RecoverPruning(datadir, db, ""); err != nil {
+		t.Fatalf("failed to recover finished pruning: %v", err)
+	}
+}
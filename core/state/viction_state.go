@@ -31,6 +31,19 @@ var vicValidatorStorageMap = map[string]uint64{
 	"voterWithdrawDelay":     9,
 }
 
+func (statedb *StateDB) VicGetCandidates(contractAddress common.Address) []common.Address {
+	candidatesArrSlot := StorageLocationFromSlot(vicValidatorStorageMap["candidates"])
+
+	arrLength := statedb.GetState(contractAddress, candidatesArrSlot.Hash()).Big().Uint64()
+	candidates := make([]common.Address, 0, arrLength)
+	for i := uint64(0); i < arrLength; i++ {
+		elemSlot := StorageLocationOfDynamicArrayElement(candidatesArrSlot, i, 160)
+		candidate := common.BytesToAddress(statedb.GetState(contractAddress, elemSlot.Hash()).Bytes())
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
 func (statedb *StateDB) VicGetValidatorInfo(contractAddress common.Address, validator common.Address) (common.Address, *big.Int) {
 	validatorMappingSlot := StorageLocationFromSlot(vicValidatorStorageMap["validatorsState"])
 	validatorStructSlot := StorageLocationOfMappingElement(validatorMappingSlot, validator.Hash().Bytes())
@@ -69,3 +82,23 @@ func (statedb *StateDB) VicGetValidatorVoterCap(contractAddress common.Address,
 
 	return new(big.Int).SetBytes(statedb.GetState(contractAddress, voterElemSlot.Hash()).Bytes())
 }
+
+func (statedb *StateDB) VicGetRandomizeSecrets(contractAddress common.Address, validator common.Address) []common.Hash {
+	secretsMappingSlot := StorageLocationFromSlot(vicRandomizeStorageMap["randomSecret"])
+	secretsArrSlot := StorageLocationOfMappingElement(secretsMappingSlot, validator.Hash().Bytes())
+
+	arrLength := statedb.GetState(contractAddress, secretsArrSlot.Hash()).Big().Uint64()
+	secrets := make([]common.Hash, 0, arrLength)
+	for i := uint64(0); i < arrLength; i++ {
+		elemSlot := StorageLocationOfDynamicArrayElement(secretsArrSlot, i, 256)
+		secrets = append(secrets, statedb.GetState(contractAddress, elemSlot.Hash()))
+	}
+	return secrets
+}
+
+func (statedb *StateDB) VicGetRandomizeOpening(contractAddress common.Address, validator common.Address) common.Hash {
+	openingMappingSlot := StorageLocationFromSlot(vicRandomizeStorageMap["randomOpening"])
+	openingSlot := StorageLocationOfMappingElement(openingMappingSlot, validator.Hash().Bytes())
+
+	return statedb.GetState(contractAddress, openingSlot.Hash())
+}
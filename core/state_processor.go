@@ -17,6 +17,8 @@
 package core
 
 import (
+	"fmt"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/misc"
@@ -35,15 +37,17 @@ type StateProcessor struct {
 	config       *params.ChainConfig // Chain configuration options
 	bc           *BlockChain         // Canonical block chain
 	engine       consensus.Engine    // Consensus engine used for block rewards
+	blacklist    BlacklistProvider   // Source of the addresses not allowed to transact
 	victionState *victionProcessorState
 }
 
 // NewStateProcessor initialises a new StateProcessor.
 func NewStateProcessor(config *params.ChainConfig, bc *BlockChain, engine consensus.Engine) *StateProcessor {
 	return &StateProcessor{
-		config: config,
-		bc:     bc,
-		engine: engine,
+		config:    config,
+		bc:        bc,
+		engine:    engine,
+		blacklist: NewBlacklistProvider(config),
 	}
 }
 
@@ -67,9 +71,7 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		gp       = new(GasPool).AddGas(block.GasLimit())
 	)
 	// Mutate the block and state according to any hard-fork specs
-	if p.config.DAOForkSupport && p.config.DAOForkBlock != nil && p.config.DAOForkBlock.Cmp(block.Number()) == 0 {
-		misc.ApplyDAOHardFork(statedb)
-	}
+	misc.ApplyTransitions(p.config, statedb, block.Number())
 	blockContext := NewEVMBlockContext(header, p.bc, nil)
 	vmenv := vm.NewEVM(blockContext, vm.TxContext{}, statedb, p.config, cfg)
 	// Iterate over and process the individual transactions
@@ -93,7 +95,7 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		if !handled {
 			receipt, err = applyTransaction(msg, p.config, p.bc, nil, gp, statedb, header, tx, usedGas, vmenv)
 			if err != nil {
-				return nil, nil, 0, err
+				return nil, nil, 0, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
 			}
 		}
 
@@ -108,7 +110,7 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 	p.engine.Finalize(p.bc, header, statedb, block.Transactions(), block.Uncles())
 
 	// Viction hooks
-	if err := p.afterProcess(block, statedb); err != nil {
+	if err := p.afterProcess(block, header, statedb); err != nil {
 		return nil, nil, 0, err
 	}
 
@@ -151,6 +153,11 @@ func applyTransaction(msg types.Message, config *params.ChainConfig, bc ChainCon
 	receipt := types.NewReceipt(root, result.Failed(), *usedGas)
 	receipt.TxHash = tx.Hash()
 	receipt.GasUsed = result.UsedGas
+	// if the gas was paid by the VRC25 contract, record who paid how much
+	if result.SponsoredFee != nil {
+		receipt.Payer = result.Payer
+		receipt.SponsoredFee = result.SponsoredFee
+	}
 	// if the transaction created a contract, store the creation address in the receipt.
 	if msg.To() == nil {
 		receipt.ContractAddress = crypto.CreateAddress(evm.TxContext.Origin, tx.Nonce())
@@ -1,5 +1,21 @@
 // Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+//
 // This file provides vic-extensions to the geth.
+
 package core
 
 import (
@@ -8,7 +24,6 @@ import (
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/consensus/misc"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vrc25"
@@ -35,71 +50,59 @@ func (p *StateProcessor) beforeProcess(block *types.Block, statedb *state.StateD
 		parrentState:       statedb.Copy(),
 	}
 
-	if p.config.TIPSigningBlock != nil && p.config.TIPSigningBlock.Cmp(header.Number) == 0 {
-		statedb.DeleteAddress(p.config.Viction.ValidatorBlockSignContract)
-	}
-	if p.config.IsAtlas(header.Number) {
-		misc.ApplyVIPVRC25Upgrade(statedb, p.config.Viction, p.config.AtlasBlock, header.Number)
-	}
-	if p.config.SaigonBlock != nil && p.config.SaigonBlock.Cmp(block.Number()) <= 0 {
-		misc.ApplySaigonHardFork(statedb, p.config.Viction, p.config.SaigonBlock, block.Number())
-	}
-
 	// Initialize signers
 	InitSignerInTransactions(p.config, header, block.Transactions())
 
 	return nil
 }
 
-func (p *StateProcessor) afterProcess(block *types.Block, statedb *state.StateDB) error {
-	if !p.config.IsAtlas(block.Number()) {
+// finalizeChecker is a consensus engine able to find a block invalid while finalizing
+// it, which it reports separately as Finalize returns no error.
+type finalizeChecker interface {
+	FinalizeError(header *types.Header) error
+}
+
+func (p *StateProcessor) afterProcess(block *types.Block, header *types.Header, statedb *state.StateDB) error {
+	// Reject the block if the engine found it invalid while finalizing the header
+	if checker, ok := p.engine.(finalizeChecker); ok {
+		if err := checker.FinalizeError(header); err != nil {
+			return err
+		}
+	}
+	if p.config.Viction != nil && !p.config.IsAtlas(block.Number()) {
 		vrc25.UpdateFeeCapacity(statedb, p.config.Viction.VRC25Contract, p.victionState.balanceUpdated, p.victionState.totalFeeUsed)
 	}
 	return nil
 }
 
 func (p *StateProcessor) beforeApplyTransaction(block *types.Block, tx *types.Transaction, msg types.Message, statedb *state.StateDB) error {
-	header := block.Header()
-
 	// Bypass blacklist for legacy blocks (before hardfork)
-	maxBlockNumber := new(big.Int).SetInt64(9147459)
-	if header.Number.Cmp(maxBlockNumber) <= 0 {
-		if val := p.config.Viction.GetVictionBypassBalance(header.Number.Uint64(), msg.From()); val != nil {
-			statedb.SetBalance(msg.From(), val)
-		}
+	if val := p.blacklist.BypassBalance(block.Number(), msg.From()); val != nil {
+		statedb.SetBalance(msg.From(), val)
 	}
 
 	// Check blacklist after hardfork
-	if p.config.IsTIPBlacklist(block.Number()) {
-		if p.config.Viction.IsBlacklisted(msg.From()) {
-			return ErrBlacklistedAddress
-		}
-		if tx.To() != nil && p.config.Viction.IsBlacklisted(*tx.To()) {
-			return ErrBlacklistedAddress
-		}
+	if err := ValidateBlacklist(p.blacklist, block.Number(), statedb, msg.From(), tx.To()); err != nil {
+		return err
 	}
 
-	// TODO: TomoX/TomoZ validation intentionally skipped for now
-	// When needed, add:
-	// - ValidateTomoZApplyTransaction() for TRC21 token registration
-	// - ValidateTomoXApplyTransaction() for TomoX pair registration
-
-	return nil
+	// Check the tokens registered for TomoZ fee sponsorship and TomoX trading
+	return p.validateTokenApplication(statedb, tx, block.Header())
 }
 
 func (p *StateProcessor) applyVictionTransaction(statedb *state.StateDB, tx *types.Transaction, header *types.Header, usedGas *uint64) (bool, *types.Receipt, uint64, error, *big.Int) {
+	if p.config.Viction == nil {
+		return false, nil, 0, nil, nil
+	}
 	// 1. BlockSigner (0x89) - Validator signature transactions
 	if tx.To() != nil && *tx.To() == p.config.Viction.ValidatorBlockSignContract && p.config.IsTIPSigning(header.Number) {
 		return p.applySignTransaction(statedb, tx, header, usedGas)
 	}
 
-	// TODO: TomoX/TomoZ/Lending transactions intentionally skipped for now
-	// When needed, add checks for:
-	// 2. TradingStateAddr (0x92) - TomoX state synchronization
-	// 3. TomoXLendingAddress (0x93) - Lending protocol transactions
-	// 4. TomoXLendingFinalizedTradeAddress (0x94) - Lending finalization
-	// 5. TomoXContract (0x91) - Trading transactions
-	// All would call applyEmptyTransaction()
+	// 2. TomoX (0x91, 0x92) and lending (0x93, 0x94) - Included without execution
+	if IsTomoXTransaction(tx) && p.config.IsTomoXReceiver(header.Number) {
+		return p.applyEmptyTransaction(statedb, tx, header, usedGas)
+	}
 
 	// Not a victionchain-specific transaction, use standard EVM
 	return false, nil, 0, nil, nil
@@ -156,15 +159,15 @@ func (p *StateProcessor) afterApplyTransaction(tx *types.Transaction, msg types.
 				fee = fee.Mul(fee, price)
 			}
 
-			balanceFee := vrc25.GetFeeCapacity(statedb, p.config.Viction.VRC25Contract, tx.To())
+			balanceFee := vrc25.NewRegistry(statedb, p.config.Viction.VRC25Contract).Capacity(*tx.To())
 
 			if receipt.Status == types.ReceiptStatusFailed {
-				if balanceFee != nil && balanceFee.Cmp(fee) > 0 {
+				if balanceFee.Cmp(fee) > 0 {
 					vrc25.PayFeeWithVRC25(statedb, msg.From(), *tx.To())
 				}
 			}
 
-			if balanceFee != nil && balanceFee.Cmp(fee) >= 0 {
+			if balanceFee.Cmp(fee) >= 0 {
 				currentVal, ok := p.victionState.balanceFee[*tx.To()]
 				if !ok {
 					currentVal = balanceFee
@@ -205,3 +208,34 @@ func InitSignerInTransactions(config *params.ChainConfig, header *types.Header,
 	}
 	wg.Wait()
 }
+
+// PrepareBlock runs the Viction hooks preceding the transactions of a block. It
+// allows replaying a block outside of Process, such as when tracing it, and must
+// be called before PrepareTransaction and ApplyVictionTransaction.
+func (p *StateProcessor) PrepareBlock(block *types.Block, statedb *state.StateDB) error {
+	return p.beforeProcess(block, statedb)
+}
+
+// PrepareTransaction runs the Viction hooks preceding the execution of a single
+// transaction of the block being replayed.
+func (p *StateProcessor) PrepareTransaction(block *types.Block, tx *types.Transaction, msg types.Message, statedb *state.StateDB) error {
+	return p.beforeApplyTransaction(block, tx, msg, statedb)
+}
+
+// FinishTransaction runs the Viction hooks following the execution of a single
+// transaction of the block being replayed, such as charging its fee to the VRC25
+// capacity of its recipient.
+func (p *StateProcessor) FinishTransaction(tx *types.Transaction, msg types.Message, statedb *state.StateDB, failed bool, usedGas uint64) error {
+	receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, GasUsed: usedGas}
+	if failed {
+		receipt.Status = types.ReceiptStatusFailed
+	}
+	return p.afterApplyTransaction(tx, msg, statedb, receipt, usedGas, nil)
+}
+
+// ApplyVictionTransaction applies the transaction if it's one of the Viction
+// special transactions included without EVM execution, reporting whether it was.
+func (p *StateProcessor) ApplyVictionTransaction(statedb *state.StateDB, tx *types.Transaction, header *types.Header, usedGas *uint64) (bool, *types.Receipt, error) {
+	handled, receipt, _, err, _ := p.applyVictionTransaction(statedb, tx, header, usedGas)
+	return handled, receipt, err
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vrc25"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the transactions of a block replayed outside of Process are charged
+// to the VRC25 capacity of their recipients the way Process charges them.
+func TestFinishTransaction(t *testing.T) {
+	var (
+		contract  = common.HexToAddress("0x8c0faeb5c6bed2129b8674f262fd45c4e9468bee")
+		sponsored = common.HexToAddress("0x2000000000000000000000000000000000000001")
+		plain     = common.HexToAddress("0x2000000000000000000000000000000000000002")
+		sender    = common.HexToAddress("0x3000000000000000000000000000000000000001")
+	)
+	config := &params.ChainConfig{
+		ChainID:          big.NewInt(1),
+		TIPTRC21FeeBlock: big.NewInt(0),
+		Viction: &params.VictionConfig{
+			TRC21GasPrice: (*math.Decimal256)(big.NewInt(2500)),
+			VRC25Contract: contract,
+		},
+	}
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	vrc25.NewRegistry(statedb, contract).SetCapacity(sponsored, big.NewInt(100000000))
+
+	p := NewStateProcessor(config, nil, nil)
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)})
+	if err := p.PrepareBlock(block, statedb); err != nil {
+		t.Fatalf("failed to prepare block: %v", err)
+	}
+	for _, to := range []common.Address{sponsored, sponsored, plain} {
+		tx := types.NewTransaction(0, to, new(big.Int), params.TxGas, new(big.Int), nil)
+		msg := types.NewMessage(sender, &to, 0, new(big.Int), params.TxGas, new(big.Int), nil, false)
+		if err := p.FinishTransaction(tx, msg, statedb, false, params.TxGas); err != nil {
+			t.Fatalf("failed to finish transaction to %x: %v", to, err)
+		}
+	}
+	// Both sponsored transactions are charged 21000 gas at the TRC21 gas price
+	if have, want := p.victionState.totalFeeUsed, big.NewInt(2*21000*2500); have.Cmp(want) != 0 {
+		t.Errorf("total fee mismatch: have %v, want %v", have, want)
+	}
+	if have, want := p.victionState.balanceUpdated[sponsored], big.NewInt(100000000-2*21000*2500); have == nil || have.Cmp(want) != 0 {
+		t.Errorf("sponsored capacity mismatch: have %v, want %v", have, want)
+	}
+	if have, ok := p.victionState.balanceUpdated[plain]; ok {
+		t.Errorf("unsponsored recipient charged: capacity %v", have)
+	}
+}
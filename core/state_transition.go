@@ -78,6 +78,9 @@ type ExecutionResult struct {
 	UsedGas    uint64 // Total used gas but include the refunded gas
 	Err        error  // Any error encountered during the execution(listed in core/vm/errors.go)
 	ReturnData []byte // Returned data from evm(function result or data supplied with revert opcode)
+
+	Payer        common.Address // Account the gas was bought from, the VRC25 contract if sponsored
+	SponsoredFee *big.Int       // Fee paid by the VRC25 contract, nil if the sender paid the gas
 }
 
 // Unwrap returns the internal evm error which allows us for further
@@ -272,11 +275,16 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	st.refundGas()
 	st.state.AddBalance(st.evm.Context.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice))
 
-	return &ExecutionResult{
+	result := &ExecutionResult{
 		UsedGas:    st.gasUsed(),
 		Err:        vmerr,
 		ReturnData: ret,
-	}, nil
+		Payer:      st.payer,
+	}
+	if st.isVRC25Transaction() {
+		result.SponsoredFee = new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice)
+	}
+	return result, nil
 }
 
 func (st *StateTransition) refundGas() {
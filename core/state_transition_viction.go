@@ -1,27 +1,39 @@
 // Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
 package core
 
 import (
 	"math/big"
 
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/vrc25"
 )
 
-var slotTokensState = vrc25.SlotVRC25Contract["tokensState"]
-
 // buyVRC25Gas checks sponsorship eligibility and deducts the gas fee from the sponsor's storage balance.
 func (st *StateTransition) vrc25BuyGas() error {
 	// Default payer is the sender
 	st.payer = st.msg.From()
 
 	// 1. Check if contract is sponsored (has fee capacity)
-	feeCap := vrc25.GetFeeCapacity(st.state, st.evm.ChainConfig().Viction.VRC25Contract, st.msg.To())
-	if feeCap == nil {
+	victionConfig := st.evm.ChainConfig().Viction
+	if st.msg.To() == nil || victionConfig == nil {
 		return nil // Not sponsored, proceed with standard user payment
 	}
-	victionConfig := st.evm.ChainConfig().Viction
+	registry := vrc25.NewRegistry(st.state, victionConfig.VRC25Contract)
+	feeCap := registry.Capacity(*st.msg.To())
 
 	// 2. Calculate Gas Cost with VRC25 Gas Price
 	vrc25GasFee := new(big.Int).Mul(new(big.Int).SetUint64(st.msg.Gas()), (*big.Int)(victionConfig.VRC25GasPrice))
@@ -33,9 +45,7 @@ func (st *StateTransition) vrc25BuyGas() error {
 
 	// 4. Deduct from Contract's Storage Balance
 	// Note: The native ETH deduction happens in state_transition.go via st.state.SubBalance(st.payer)
-	newFeeCap := new(big.Int).Sub(feeCap, vrc25GasFee)
-	feeCapKey := state.GetStorageKeyForMapping(st.msg.To().Hash(), slotTokensState)
-	st.state.SetState(victionConfig.VRC25Contract, feeCapKey, common.BigToHash(newFeeCap))
+	registry.SetCapacity(*st.msg.To(), new(big.Int).Sub(feeCap, vrc25GasFee))
 
 	// 5. Set Payer to System Contract
 	// This ensures buyGas() deducts native ETH from the system contract
@@ -51,15 +61,11 @@ func (st *StateTransition) isVRC25Transaction() bool {
 
 func (st *StateTransition) vrc25RefundGas(remaining *big.Int) {
 	addr := st.msg.To()
-	// Get current balance
-	feeCap := vrc25.GetFeeCapacity(st.state, st.evm.ChainConfig().Viction.VRC25Contract, addr)
-	if feeCap == nil {
+	if addr == nil {
 		// Should not happen if isSponsoringTransaction is true, but handle safely
 		return
 	}
-
 	// Refund to Contract's Storage Balance
-	newFeeCap := new(big.Int).Add(feeCap, remaining)
-	feeCapKey := state.GetStorageKeyForMapping(addr.Hash(), slotTokensState)
-	st.state.SetState(st.evm.ChainConfig().Viction.VRC25Contract, feeCapKey, common.BigToHash(newFeeCap))
+	registry := vrc25.NewRegistry(st.state, st.evm.ChainConfig().Viction.VRC25Contract)
+	registry.SetCapacity(*addr, new(big.Int).Add(registry.Capacity(*addr), remaining))
 }
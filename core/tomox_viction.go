@@ -0,0 +1,168 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vrc25"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Receivers of the transactions carrying the TomoX orders and matching results,
+// which the legacy client included in blocks without executing them.
+var (
+	TomoXTradingAddress               = common.HexToAddress("0x0000000000000000000000000000000000000091") // TomoX orders
+	TomoXTradingStateAddress          = common.HexToAddress("0x0000000000000000000000000000000000000092") // TomoX matching results
+	TomoXLendingAddress               = common.HexToAddress("0x0000000000000000000000000000000000000093") // Lending orders
+	TomoXLendingFinalizedTradeAddress = common.HexToAddress("0x0000000000000000000000000000000000000094") // Finalized lending trades
+)
+
+// tomoNativeAddress stands for the native coin in TomoX trading pairs.
+var tomoNativeAddress = common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+// tokenCallGas is the gas allowance of the token views called to validate them.
+const tokenCallGas = 50000000
+
+var (
+	tomoZApplySelector = crypto.Keccak256([]byte("apply(address)"))[:4]         // TRC21 issuer: apply a token for fee sponsorship
+	tomoXApplySelector = crypto.Keccak256([]byte("apply(address,address)"))[:4] // TomoX listing: apply a trading pair
+
+	tokenMinFeeSelector    = crypto.Keccak256([]byte("minFee()"))[:4]
+	tokenDecimalsSelector  = crypto.Keccak256([]byte("decimals()"))[:4]
+	tokenBalanceOfSelector = crypto.Keccak256([]byte("balanceOf(address)"))[:4]
+)
+
+var (
+	// ErrInvalidTomoZToken is returned if a token applied for fee sponsorship does
+	// not keep its minimum fee where the node reads it from.
+	ErrInvalidTomoZToken = errors.New("invalid TomoZ token: minFee slot mismatch")
+
+	// ErrInvalidTomoXToken is returned if a token applied for TomoX trading does
+	// not report its decimals or keep its balances where the node reads them from.
+	ErrInvalidTomoXToken = errors.New("invalid TomoX token: decimals or balance slot mismatch")
+)
+
+// IsTomoXTransaction reports whether the transaction is one of the TomoX or TomoX
+// lending transactions that blocks accepted without executing.
+func IsTomoXTransaction(tx *types.Transaction) bool {
+	if tx.To() == nil {
+		return false
+	}
+	switch *tx.To() {
+	case TomoXTradingAddress, TomoXTradingStateAddress, TomoXLendingAddress, TomoXLendingFinalizedTradeAddress:
+		return true
+	}
+	return false
+}
+
+// applyEmptyTransaction includes a transaction in the block without executing it,
+// neither charging gas nor bumping the sender nonce, and only logs its receiver.
+func (p *StateProcessor) applyEmptyTransaction(statedb *state.StateDB, tx *types.Transaction, header *types.Header, usedGas *uint64) (bool, *types.Receipt, uint64, error, *big.Int) {
+	var root []byte
+	if p.config.IsByzantium(header.Number) {
+		statedb.Finalise(true)
+	} else {
+		root = statedb.IntermediateRoot(p.config.IsEIP158(header.Number)).Bytes()
+	}
+	receipt := types.NewReceipt(root, false, *usedGas)
+	receipt.TxHash = tx.Hash()
+	receipt.GasUsed = 0
+
+	log := &types.Log{}
+	log.Address = *tx.To()
+	log.BlockNumber = header.Number.Uint64()
+	statedb.AddLog(log)
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+
+	return true, receipt, 0, nil, nil
+}
+
+// validateTokenApplication checks the tokens applied for TomoZ fee sponsorship or
+// TomoX trading follow the storage layout the node reads them with, rejecting the
+// block otherwise, as long as the TomoX transactions are accepted.
+func (p *StateProcessor) validateTokenApplication(statedb *state.StateDB, tx *types.Transaction, header *types.Header) error {
+	if tx.To() == nil || !p.config.IsTomoXReceiver(header.Number) {
+		return nil
+	}
+	data := tx.Data()
+	switch {
+	case *tx.To() == p.config.Viction.VRC25Contract && len(data) >= 4+32 && bytes.Equal(data[:4], tomoZApplySelector):
+		return p.validateTomoZToken(statedb, header, common.BytesToAddress(data[4:4+32]))
+
+	case *tx.To() == p.config.Viction.TomoXContract && len(data) >= 4+64 && bytes.Equal(data[:4], tomoXApplySelector):
+		// As on the legacy client, only the quote token held by the last word is checked
+		return p.validateTomoXToken(statedb, header, common.BytesToAddress(data[4+32:4+64]))
+	}
+	return nil
+}
+
+// validateTomoZToken checks the minFee view of the token returns the minimum fee
+// kept in its storage.
+func (p *StateProcessor) validateTomoZToken(statedb *state.StateDB, header *types.Header, token common.Address) error {
+	ret, err := p.callToken(statedb, header, token, tokenMinFeeSelector)
+	if err != nil || len(ret) < 32 {
+		return ErrInvalidTomoZToken
+	}
+	if new(big.Int).SetBytes(ret[:32]).Cmp(vrc25.NewToken(statedb, token).MinFee()) != 0 {
+		return ErrInvalidTomoZToken
+	}
+	return nil
+}
+
+// validateTomoXToken checks the token reports its decimals and its balanceOf view
+// returns the balances kept in its storage. The native coin is always valid.
+func (p *StateProcessor) validateTomoXToken(statedb *state.StateDB, header *types.Header, token common.Address) error {
+	if token == tomoNativeAddress {
+		return nil
+	}
+	if ret, err := p.callToken(statedb, header, token, tokenDecimalsSelector); err != nil || len(ret) < 32 {
+		return ErrInvalidTomoXToken
+	}
+	// Probe the balance slot with a balance no real holder has, reverted afterwards
+	var (
+		probe   = common.BytesToAddress(crypto.Keccak256([]byte("tomox balance slot probe")))
+		balance = new(big.Int).SetBytes(crypto.Keccak256(token.Bytes()))
+	)
+	snapshot := statedb.Snapshot()
+	defer statedb.RevertToSnapshot(snapshot)
+
+	vrc25.NewToken(statedb, token).SetBalance(probe, balance)
+	ret, err := p.callToken(statedb, header, token, append(common.CopyBytes(tokenBalanceOfSelector), probe.Hash().Bytes()...))
+	if err != nil || len(ret) < 32 || new(big.Int).SetBytes(ret[:32]).Cmp(balance) != 0 {
+		return ErrInvalidTomoXToken
+	}
+	return nil
+}
+
+// callToken executes a view of the token against the state, reverting any change
+// made by the call.
+func (p *StateProcessor) callToken(statedb *state.StateDB, header *types.Header, token common.Address, input []byte) ([]byte, error) {
+	snapshot := statedb.Snapshot()
+	defer statedb.RevertToSnapshot(snapshot)
+
+	blockContext := NewEVMBlockContext(header, p.bc, &header.Coinbase)
+	evm := vm.NewEVM(blockContext, vm.TxContext{GasPrice: new(big.Int)}, statedb, p.config, vm.Config{})
+	ret, _, err := evm.Call(vm.AccountRef(common.Address{}), token, input, tokenCallGas, new(big.Int))
+	return ret, err
+}
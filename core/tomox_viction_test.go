@@ -0,0 +1,131 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the TomoX and lending transactions are included without execution
+// only while blocks accept them.
+func TestApplyTomoXTransaction(t *testing.T) {
+	config := &params.ChainConfig{
+		ByzantiumBlock: big.NewInt(0),
+		TIPTomoXBlock:  big.NewInt(10),
+		Viction:        &params.VictionConfig{TomoXReceiverEnd: 20},
+	}
+	p := &StateProcessor{config: config}
+	key, _ := crypto.GenerateKey()
+
+	for _, to := range []common.Address{TomoXTradingAddress, TomoXTradingStateAddress, TomoXLendingAddress, TomoXLendingFinalizedTradeAddress} {
+		for number, want := range map[int64]bool{9: false, 10: true, 19: true, 20: false} {
+			statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+			tx, _ := types.SignTx(types.NewTransaction(0, to, new(big.Int), 100000, big.NewInt(1), []byte{1}), types.HomesteadSigner{}, key)
+			statedb.Prepare(tx.Hash(), common.Hash{}, 0)
+
+			usedGas := uint64(21000)
+			handled, receipt, _, err, _ := p.applyVictionTransaction(statedb, tx, &types.Header{Number: big.NewInt(number)}, &usedGas)
+			if err != nil {
+				t.Fatalf("%x at #%d: failed to apply: %v", to, number, err)
+			}
+			if handled != want {
+				t.Errorf("%x at #%d: handled mismatch: have %v, want %v", to, number, handled, want)
+			}
+			if !handled {
+				continue
+			}
+			if receipt.GasUsed != 0 || receipt.CumulativeGasUsed != usedGas || usedGas != 21000 {
+				t.Errorf("%x at #%d: gas charged: receipt %d, cumulative %d, block %d", to, number, receipt.GasUsed, receipt.CumulativeGasUsed, usedGas)
+			}
+			if nonce := statedb.GetNonce(crypto.PubkeyToAddress(key.PublicKey)); nonce != 0 {
+				t.Errorf("%x at #%d: sender nonce bumped to %d", to, number, nonce)
+			}
+			if logs := statedb.GetLogs(tx.Hash()); len(logs) != 1 || logs[0].Address != to {
+				t.Errorf("%x at #%d: logs mismatch: %v", to, number, logs)
+			}
+		}
+	}
+}
+
+// Tests that the tokens applied for TomoZ fee sponsorship and TomoX trading must
+// follow the storage layout the node reads them with.
+func TestValidateTokenApplication(t *testing.T) {
+	var (
+		issuer  = common.HexToAddress("0x8c0faeb5c6bed2129b8674f262fd45c4e9468bee")
+		listing = common.HexToAddress("0xde34dd0f536170993e8cff639ddffcf1a85d3e53")
+
+		slot1Token   = common.HexToAddress("0x1000000000000000000000000000000000000001") // Returns storage slot 1
+		slot2Token   = common.HexToAddress("0x1000000000000000000000000000000000000002") // Returns storage slot 2
+		balanceToken = common.HexToAddress("0x1000000000000000000000000000000000000003") // Returns the balance of the first argument
+	)
+	config := &params.ChainConfig{
+		TIPTomoXBlock: big.NewInt(10),
+		Viction:       &params.VictionConfig{VRC25Contract: issuer, TomoXContract: listing},
+	}
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetCode(slot1Token, common.Hex2Bytes("60015460005260206000f3"))
+	statedb.SetCode(slot2Token, common.Hex2Bytes("60025460005260206000f3"))
+	statedb.SetCode(balanceToken, common.Hex2Bytes("600435600052600060205260406000205460005260206000f3"))
+	for _, token := range []common.Address{slot1Token, slot2Token} {
+		statedb.SetState(token, state.GetStorageKeyForSlot(1), common.BigToHash(big.NewInt(5)))
+	}
+	p := &StateProcessor{config: config}
+
+	apply := func(to common.Address, selector []byte, args ...common.Address) *types.Transaction {
+		data := common.CopyBytes(selector)
+		for _, arg := range args {
+			data = append(data, arg.Hash().Bytes()...)
+		}
+		return types.NewTransaction(0, to, new(big.Int), 100000, big.NewInt(1), data)
+	}
+	tests := []struct {
+		number int64
+		tx     *types.Transaction
+		want   error
+	}{
+		{10, apply(issuer, tomoZApplySelector, slot1Token), nil},
+		{10, apply(issuer, tomoZApplySelector, slot2Token), ErrInvalidTomoZToken},
+		{10, apply(issuer, tomoZApplySelector, common.Address{}), ErrInvalidTomoZToken},
+		{9, apply(issuer, tomoZApplySelector, slot2Token), nil},
+		{10, apply(listing, tomoXApplySelector, slot1Token, balanceToken), nil},
+		{10, apply(listing, tomoXApplySelector, balanceToken, tomoNativeAddress), nil},
+		{10, apply(listing, tomoXApplySelector, balanceToken, slot1Token), ErrInvalidTomoXToken},
+		{9, apply(listing, tomoXApplySelector, balanceToken, slot1Token), nil},
+		{10, apply(listing, tomoZApplySelector, slot2Token), nil},
+
+		// Trailing data doesn't hide the applied tokens
+		{10, apply(issuer, tomoZApplySelector, slot2Token, slot1Token), ErrInvalidTomoZToken},
+		{10, apply(listing, tomoXApplySelector, balanceToken, slot1Token, balanceToken), ErrInvalidTomoXToken},
+	}
+	root := statedb.IntermediateRoot(false)
+	for i, tt := range tests {
+		if err := p.validateTokenApplication(statedb, tt.tx, &types.Header{Number: big.NewInt(tt.number), Difficulty: big.NewInt(1)}); err != tt.want {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.want)
+		}
+	}
+	if have := statedb.IntermediateRoot(false); have != root {
+		t.Errorf("validation modified the state: root %x, want %x", have, root)
+	}
+}
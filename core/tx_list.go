@@ -320,6 +320,13 @@ func (l *txList) Forward(threshold uint64) types.Transactions {
 // is lower than the costgas cap, the caps will be reset to a new high after removing
 // the newly invalidated transactions.
 func (l *txList) Filter(costLimit *big.Int, gasLimit uint64) (types.Transactions, types.Transactions) {
+	return l.FilterCost(costLimit, gasLimit, (*types.Transaction).Cost)
+}
+
+// FilterCost is like Filter, but measures the cost of each transaction with the
+// given function, which lets transactions paid by a VRC25 sponsor only count the
+// value they transfer against the account's funds.
+func (l *txList) FilterCost(costLimit *big.Int, gasLimit uint64, cost func(*types.Transaction) *big.Int) (types.Transactions, types.Transactions) {
 	// If all transactions are below the threshold, short circuit
 	if l.costcap.Cmp(costLimit) <= 0 && l.gascap <= gasLimit {
 		return nil, nil
@@ -329,7 +336,7 @@ func (l *txList) Filter(costLimit *big.Int, gasLimit uint64) (types.Transactions
 
 	// Filter out all the transactions above the account's funds
 	removed := l.txs.Filter(func(tx *types.Transaction) bool {
-		return tx.Gas() > gasLimit || tx.Cost().Cmp(costLimit) > 0
+		return tx.Gas() > gasLimit || cost(tx).Cmp(costLimit) > 0
 	})
 
 	if len(removed) == 0 {
@@ -408,33 +415,54 @@ func (l *txList) LastElement() *types.Transaction {
 
 // priceHeap is a heap.Interface implementation over transactions for retrieving
 // price-sorted transactions to discard when the pool fills up.
-type priceHeap []*types.Transaction
+//
+// Transactions sponsored by a VRC25 token are sorted by the price their sponsor
+// pays instead of the one declared by the sender.
+type priceHeap struct {
+	all            *txLookup                // Lookup to check which transactions are sponsored
+	sponsoredPrice *big.Int                 // Gas price paid for sponsored transactions
+	sponsored      map[common.Hash]struct{} // Sponsored transactions currently in the heap
+	list           []*types.Transaction
+}
+
+// price returns the gas price a transaction within the heap is sorted by.
+func (h *priceHeap) price(tx *types.Transaction) *big.Int {
+	if _, ok := h.sponsored[tx.Hash()]; ok {
+		return h.sponsoredPrice
+	}
+	return tx.GasPrice()
+}
 
-func (h priceHeap) Len() int      { return len(h) }
-func (h priceHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *priceHeap) Len() int      { return len(h.list) }
+func (h *priceHeap) Swap(i, j int) { h.list[i], h.list[j] = h.list[j], h.list[i] }
 
-func (h priceHeap) Less(i, j int) bool {
+func (h *priceHeap) Less(i, j int) bool {
 	// Sort primarily by price, returning the cheaper one
-	switch h[i].GasPriceCmp(h[j]) {
+	switch h.price(h.list[i]).Cmp(h.price(h.list[j])) {
 	case -1:
 		return true
 	case 1:
 		return false
 	}
 	// If the prices match, stabilize via nonces (high nonce is worse)
-	return h[i].Nonce() > h[j].Nonce()
+	return h.list[i].Nonce() > h.list[j].Nonce()
 }
 
 func (h *priceHeap) Push(x interface{}) {
-	*h = append(*h, x.(*types.Transaction))
+	tx := x.(*types.Transaction)
+	if h.all.Sponsored(tx.Hash()) {
+		h.sponsored[tx.Hash()] = struct{}{}
+	}
+	h.list = append(h.list, tx)
 }
 
 func (h *priceHeap) Pop() interface{} {
-	old := *h
+	old := h.list
 	n := len(old)
 	x := old[n-1]
 	old[n-1] = nil
-	*h = old[0 : n-1]
+	h.list = old[0 : n-1]
+	delete(h.sponsored, x.Hash())
 	return x
 }
 
@@ -446,11 +474,22 @@ type txPricedList struct {
 	stales int        // Number of stale price points to (re-heap trigger)
 }
 
-// newTxPricedList creates a new price-sorted transaction heap.
-func newTxPricedList(all *txLookup) *txPricedList {
+// newTxPricedList creates a new price-sorted transaction heap. Transactions that
+// the lookup marks as sponsored are priced at sponsoredPrice.
+func newTxPricedList(all *txLookup, sponsoredPrice *big.Int) *txPricedList {
 	return &txPricedList{
 		all:   all,
-		items: new(priceHeap),
+		items: newPriceHeap(all, sponsoredPrice, 0),
+	}
+}
+
+// newPriceHeap creates an empty price heap with room for size transactions.
+func newPriceHeap(all *txLookup, sponsoredPrice *big.Int, size int) *priceHeap {
+	return &priceHeap{
+		all:            all,
+		sponsoredPrice: sponsoredPrice,
+		sponsored:      make(map[common.Hash]struct{}),
+		list:           make([]*types.Transaction, 0, size),
 	}
 }
 
@@ -465,17 +504,28 @@ func (l *txPricedList) Put(tx *types.Transaction) {
 func (l *txPricedList) Removed(count int) {
 	// Bump the stale counter, but exit if still too low (< 25%)
 	l.stales += count
-	if l.stales <= len(*l.items)/4 {
+	if l.stales <= l.items.Len()/4 {
 		return
 	}
 	// Seems we've reached a critical number of stale transactions, reheap
-	reheap := make(priceHeap, 0, l.all.Count())
+	l.Reheap()
+}
+
+// Reheap forcibly rebuilds the heap based on the current contents of the pool,
+// picking up any change in which transactions are sponsored.
+func (l *txPricedList) Reheap() {
+	reheap := newPriceHeap(l.all, l.items.sponsoredPrice, l.all.Count())
 
-	l.stales, l.items = 0, &reheap
 	l.all.Range(func(hash common.Hash, tx *types.Transaction) bool {
-		*l.items = append(*l.items, tx)
+		reheap.list = append(reheap.list, tx)
 		return true
 	})
+	for _, tx := range reheap.list {
+		if l.all.Sponsored(tx.Hash()) {
+			reheap.sponsored[tx.Hash()] = struct{}{}
+		}
+	}
+	l.stales, l.items = 0, reheap
 	heap.Init(l.items)
 }
 
@@ -485,7 +535,7 @@ func (l *txPricedList) Cap(threshold *big.Int, local *accountSet) types.Transact
 	drop := make(types.Transactions, 0, 128) // Remote underpriced transactions to drop
 	save := make(types.Transactions, 0, 64)  // Local underpriced transactions to keep
 
-	for len(*l.items) > 0 {
+	for l.items.Len() > 0 {
 		// Discard stale transactions if found during cleanup
 		tx := heap.Pop(l.items).(*types.Transaction)
 		if l.all.Get(tx.Hash()) == nil {
@@ -493,7 +543,7 @@ func (l *txPricedList) Cap(threshold *big.Int, local *accountSet) types.Transact
 			continue
 		}
 		// Stop the discards if we've reached the threshold
-		if tx.GasPriceIntCmp(threshold) >= 0 {
+		if l.price(tx).Cmp(threshold) >= 0 {
 			save = append(save, tx)
 			break
 		}
@@ -518,8 +568,8 @@ func (l *txPricedList) Underpriced(tx *types.Transaction, local *accountSet) boo
 		return false
 	}
 	// Discard stale price points if found at the heap start
-	for len(*l.items) > 0 {
-		head := []*types.Transaction(*l.items)[0]
+	for l.items.Len() > 0 {
+		head := l.items.list[0]
 		if l.all.Get(head.Hash()) == nil {
 			l.stales--
 			heap.Pop(l.items)
@@ -528,12 +578,12 @@ func (l *txPricedList) Underpriced(tx *types.Transaction, local *accountSet) boo
 		break
 	}
 	// Check if the transaction is underpriced or not
-	if len(*l.items) == 0 {
+	if l.items.Len() == 0 {
 		log.Error("Pricing query for empty pool") // This cannot happen, print to catch programming errors
 		return false
 	}
-	cheapest := []*types.Transaction(*l.items)[0]
-	return cheapest.GasPriceCmp(tx) >= 0
+	cheapest := l.items.list[0]
+	return l.items.price(cheapest).Cmp(l.price(tx)) >= 0
 }
 
 // Discard finds a number of most underpriced transactions, removes them from the
@@ -545,7 +595,7 @@ func (l *txPricedList) Discard(slots int, local *accountSet) types.Transactions
 		// little check to avoid unpacking / repacking the heap later on, which
 		// is very expensive
 		discardable := 0
-		for _, tx := range *l.items {
+		for _, tx := range l.items.list {
 			if !local.containsTx(tx) {
 				discardable++
 			}
@@ -561,9 +611,9 @@ func (l *txPricedList) Discard(slots int, local *accountSet) types.Transactions
 		return nil
 	}
 	drop := make(types.Transactions, 0, slots)               // Remote underpriced transactions to drop
-	save := make(types.Transactions, 0, len(*l.items)-slots) // Local underpriced transactions to keep
+	save := make(types.Transactions, 0, l.items.Len()-slots) // Local underpriced transactions to keep
 
-	for len(*l.items) > 0 && slots > 0 {
+	for l.items.Len() > 0 && slots > 0 {
 		// Discard stale transactions if found during cleanup
 		tx := heap.Pop(l.items).(*types.Transaction)
 		if l.all.Get(tx.Hash()) == nil {
@@ -583,3 +633,12 @@ func (l *txPricedList) Discard(slots int, local *accountSet) types.Transactions
 	}
 	return drop
 }
+
+// price returns the gas price a transaction is sorted by, which is the price its
+// sponsor pays if the lookup marks it as sponsored.
+func (l *txPricedList) price(tx *types.Transaction) *big.Int {
+	if l.all.Sponsored(tx.Hash()) {
+		return l.items.sponsoredPrice
+	}
+	return tx.GasPrice()
+}
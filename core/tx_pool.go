@@ -105,6 +105,7 @@ var (
 	validTxMeter       = metrics.NewRegisteredMeter("txpool/valid", nil)
 	invalidTxMeter     = metrics.NewRegisteredMeter("txpool/invalid", nil)
 	underpricedTxMeter = metrics.NewRegisteredMeter("txpool/underpriced", nil)
+	blacklistedTxMeter = metrics.NewRegisteredMeter("txpool/blacklisted", nil)
 
 	pendingGauge = metrics.NewRegisteredGauge("txpool/pending", nil)
 	queuedGauge  = metrics.NewRegisteredGauge("txpool/queued", nil)
@@ -148,6 +149,8 @@ type TxPoolConfig struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	SponsoredGas uint64 // Maximum gas of pooled transactions a single VRC25 token may sponsor
 }
 
 // DefaultTxPoolConfig contains the default configurations for the transaction
@@ -165,6 +168,8 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	GlobalQueue:  1024,
 
 	Lifetime: 3 * time.Hour,
+
+	SponsoredGas: 50000000,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -203,6 +208,10 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultTxPoolConfig.Lifetime)
 		conf.Lifetime = DefaultTxPoolConfig.Lifetime
 	}
+	if conf.SponsoredGas < 1 {
+		log.Warn("Sanitizing invalid txpool sponsored gas", "provided", conf.SponsoredGas, "updated", DefaultTxPoolConfig.SponsoredGas)
+		conf.SponsoredGas = DefaultTxPoolConfig.SponsoredGas
+	}
 	return conf
 }
 
@@ -225,6 +234,9 @@ type TxPool struct {
 
 	istanbul bool // Fork indicator whether we are in the istanbul stage.
 
+	blacklist     BlacklistProvider // Source of the addresses not allowed to transact
+	pendingNumber *big.Int          // Number of the next block, against which the blacklist is checked
+
 	currentState  *state.StateDB // Current state in the blockchain head
 	pendingNonces *txNoncer      // Pending state tracking virtual nonces
 	currentMaxGas uint64         // Current gas limit for transaction caps
@@ -264,6 +276,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		chainconfig:     chainconfig,
 		chain:           chain,
 		signer:          types.NewEIP155Signer(chainconfig.ChainID),
+		blacklist:       NewBlacklistProvider(chainconfig),
 		pending:         make(map[common.Address]*txList),
 		queue:           make(map[common.Address]*txList),
 		beats:           make(map[common.Address]time.Time),
@@ -281,7 +294,11 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		log.Info("Setting new local account", "address", addr)
 		pool.locals.add(addr)
 	}
-	pool.priced = newTxPricedList(pool.all)
+	var sponsoredPrice *big.Int
+	if chainconfig.Viction != nil {
+		sponsoredPrice = (*big.Int)(chainconfig.Viction.VRC25GasPrice)
+	}
+	pool.priced = newTxPricedList(pool.all, sponsoredPrice)
 	pool.reset(nil, chain.CurrentBlock().Header())
 
 	// Start the reorg loop early so it can handle requests generated during journal loading.
@@ -516,7 +533,9 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
-func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
+//
+// Sponsored transactions only need the sender to afford the value they transfer.
+func (pool *TxPool) validateTx(tx *types.Transaction, local, sponsored bool) error {
 	// Reject transactions over defined size to prevent DOS attacks
 	if uint64(tx.Size()) > txMaxSize {
 		return ErrOversizedData
@@ -535,9 +554,16 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if err != nil {
 		return ErrInvalidSender
 	}
-	// Drop non-local transactions under our own minimal accepted gas price
+	// Reject transactions from or to blacklisted addresses, they would never be mined
+	if pool.pendingNumber != nil {
+		if err := ValidateBlacklist(pool.blacklist, pool.pendingNumber, pool.currentState, from, tx.To()); err != nil {
+			return err
+		}
+	}
+	// Drop non-local transactions under our own minimal accepted gas price, which
+	// for sponsored transactions is the price their sponsor pays
 	local = local || pool.locals.contains(from) // account may be local even if the transaction arrived from the network
-	if !local && tx.GasPriceIntCmp(pool.gasPrice) < 0 {
+	if !local && pool.txPrice(tx, sponsored).Cmp(pool.gasPrice) < 0 {
 		return ErrUnderpriced
 	}
 	// Ensure the transaction adheres to nonce ordering
@@ -545,7 +571,7 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		return ErrNonceTooLow
 	}
 	// Use custom VRC25 balance validation
-	if err := pool.validateSufficientTransaction(tx, from); err != nil {
+	if err := pool.validateSufficientTransaction(tx, from, sponsored); err != nil {
 		return err
 	}
 	// Ensure the transaction has more gas than the basic tx fee.
@@ -574,10 +600,24 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		knownTxMeter.Mark(1)
 		return false, ErrAlreadyKnown
 	}
+	// Reserve the sponsor's capacity if a VRC25 token would pay for the transaction,
+	// releasing it again if the transaction doesn't make it into the pool
+	sponsored := pool.sponsorable(tx)
+	if sponsored {
+		pool.all.Sponsor(tx, *tx.To())
+		defer func() {
+			if err != nil {
+				pool.all.Unsponsor(hash)
+			}
+		}()
+	}
 	// If the transaction fails basic validation, discard it
-	if err := pool.validateTx(tx, local); err != nil {
+	if err := pool.validateTx(tx, local, sponsored); err != nil {
 		log.Trace("Discarding invalid transaction", "hash", hash, "err", err)
 		invalidTxMeter.Mark(1)
+		if errors.Is(err, ErrBlacklistedAddress) {
+			blacklistedTxMeter.Mark(1)
+		}
 		return false, err
 	}
 	// If the transaction pool is full, discard underpriced transactions
@@ -1171,6 +1211,9 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.pendingNonces = newTxNoncer(statedb)
 	pool.currentMaxGas = newHead.GasLimit
 
+	// Sponsors may have spent or topped up their capacity, check what they still cover
+	pool.responsor()
+
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
 	senderCacher.recover(pool.signer, reinject)
@@ -1179,6 +1222,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	// Update all fork indicator by next pending block number.
 	next := new(big.Int).Add(newHead.Number, big.NewInt(1))
 	pool.istanbul = pool.chainconfig.IsIstanbul(next)
+	pool.pendingNumber = next
 }
 
 // promoteExecutables moves transactions that have become processable from the
@@ -1202,7 +1246,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 		}
 		log.Trace("Removed old queued transactions", "count", len(forwards))
 		// Drop all transactions that are too costly (low balance or out of gas)
-		drops, _ := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
+		drops, _ := list.FilterCost(pool.currentState.GetBalance(addr), pool.currentMaxGas, pool.txCost)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
@@ -1395,7 +1439,7 @@ func (pool *TxPool) demoteUnexecutables() {
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
-		drops, invalids := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
+		drops, invalids := list.FilterCost(pool.currentState.GetBalance(addr), pool.currentMaxGas, pool.txCost)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
@@ -1531,12 +1575,17 @@ type txLookup struct {
 	all   map[common.Hash]*types.Transaction
 	slots int
 	lock  sync.RWMutex
+
+	sponsors     map[common.Hash]txSponsorship // VRC25 token paying for each sponsored transaction
+	sponsoredGas map[common.Address]uint64     // Gas of the pooled transactions each token pays for
 }
 
 // newTxLookup returns a new txLookup structure.
 func newTxLookup() *txLookup {
 	return &txLookup{
-		all: make(map[common.Hash]*types.Transaction),
+		all:          make(map[common.Hash]*types.Transaction),
+		sponsors:     make(map[common.Hash]txSponsorship),
+		sponsoredGas: make(map[common.Address]uint64),
 	}
 }
 
@@ -1595,6 +1644,7 @@ func (t *txLookup) Remove(hash common.Hash) {
 	t.slots -= numSlots(t.all[hash])
 	slotsGauge.Update(int64(t.slots))
 
+	t.unsponsor(hash)
 	delete(t.all, hash)
 }
 
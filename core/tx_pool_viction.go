@@ -1,3 +1,19 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
 package core
 
 import (
@@ -8,25 +24,31 @@ import (
 	"github.com/ethereum/go-ethereum/core/vrc25"
 )
 
+// txSponsorship records the VRC25 token paying for a pooled transaction and the
+// gas reserved against it.
+type txSponsorship struct {
+	token common.Address
+	gas   uint64
+}
+
 // validate sufficient balance for transaction execution, considering VRC25 fee cap if applicable
-func (pool *TxPool) validateSufficientTransaction(tx *types.Transaction, from common.Address) error {
+func (pool *TxPool) validateSufficientTransaction(tx *types.Transaction, from common.Address, sponsored bool) error {
 	balance := pool.currentState.GetBalance(from)
 	requiredBalance := tx.Cost()
 
-	feeCap := vrc25.GetFeeCapacity(pool.currentState, pool.chainconfig.Viction.VRC25Contract, tx.To())
-	if feeCap != nil {
-		if tx.To() != nil {
-			// VRC25 transaction
-			if err := vrc25.ValidateVRC25Transaction(pool.currentState, pool.chainconfig.Viction.VRC25Contract, from, *tx.To(), tx.Data()); err != nil {
+	// Transactions to a registered VRC25 token pay the token fee, whether or not
+	// the pool counts on the token to sponsor their gas
+	if pool.chainconfig.Viction != nil && tx.To() != nil {
+		vrc25Contract := pool.chainconfig.Viction.VRC25Contract
+		if vrc25.NewRegistry(pool.currentState, vrc25Contract).IsSponsored(*tx.To()) {
+			if err := vrc25.ValidateVRC25Transaction(pool.currentState, vrc25Contract, from, *tx.To(), tx.Data()); err != nil {
 				return err
 			}
 		}
-
-		requiredFee := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), (*big.Int)(pool.chainconfig.Viction.VRC25GasPrice))
-		if feeCap.Cmp(requiredFee) >= 0 {
-			// if fee capacity is sufficient, reduce the required balance by gas fee
-			requiredBalance = tx.Value()
-		}
+	}
+	if sponsored {
+		// The sponsor pays the gas, the sender only the transferred value
+		requiredBalance = tx.Value()
 	}
 	if balance.Cmp(requiredBalance) < 0 {
 		return ErrInsufficientFunds
@@ -34,3 +56,144 @@ func (pool *TxPool) validateSufficientTransaction(tx *types.Transaction, from co
 
 	return nil
 }
+
+// sponsorable reports whether the VRC25 token a transaction is sent to would pay
+// for it. The token must have enough capacity left at the head to cover the gas of
+// the transaction on top of the gas already reserved by other pooled transactions
+// it sponsors, and the total may not exceed the per-token SponsoredGas limit.
+func (pool *TxPool) sponsorable(tx *types.Transaction) bool {
+	if pool.chainconfig.Viction == nil || pool.chainconfig.Viction.VRC25GasPrice == nil || tx.To() == nil {
+		return false
+	}
+	token := *tx.To()
+
+	// A replacement frees the gas reserved by the transaction it replaces
+	reserved := pool.all.SponsoredGas(token)
+	if from, err := types.Sender(pool.signer, tx); err == nil {
+		for _, list := range []*txList{pool.pending[from], pool.queue[from]} {
+			if list == nil {
+				continue
+			}
+			if old := list.txs.Get(tx.Nonce()); old != nil && pool.all.SponsoredBy(old.Hash(), token) {
+				reserved -= old.Gas()
+			}
+		}
+	}
+	gas := reserved + tx.Gas()
+	if gas > pool.config.SponsoredGas {
+		return false
+	}
+	capacity := vrc25.NewRegistry(pool.currentState, pool.chainconfig.Viction.VRC25Contract).Capacity(token)
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gas), (*big.Int)(pool.chainconfig.Viction.VRC25GasPrice))
+	return capacity.Cmp(fee) >= 0
+}
+
+// responsor re-validates the sponsorship of every pooled transaction against the
+// capacity the VRC25 tokens have left at the current head. Pending transactions
+// are covered before queued ones, each account's in nonce order. Transactions a
+// token can no longer pay for fall back to being paid by their sender, leaving
+// demoteUnexecutables to drop the ones the sender cannot afford.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) responsor() {
+	if pool.chainconfig.Viction == nil {
+		return
+	}
+	pool.all.ResetSponsors()
+	for _, accounts := range []map[common.Address]*txList{pool.pending, pool.queue} {
+		for _, list := range accounts {
+			for _, tx := range list.Flatten() {
+				if pool.sponsorable(tx) {
+					pool.all.Sponsor(tx, *tx.To())
+				}
+			}
+		}
+	}
+	// Sponsorship decides the price transactions are sorted by
+	pool.priced.Reheap()
+}
+
+// txPrice returns the gas price paid for a transaction, which is the VRC25 gas
+// price if it is sponsored.
+func (pool *TxPool) txPrice(tx *types.Transaction, sponsored bool) *big.Int {
+	if sponsored {
+		return (*big.Int)(pool.chainconfig.Viction.VRC25GasPrice)
+	}
+	return tx.GasPrice()
+}
+
+// txCost returns the funds the sender of a pooled transaction needs, which is only
+// the transferred value if a VRC25 token pays for the gas.
+func (pool *TxPool) txCost(tx *types.Transaction) *big.Int {
+	if pool.all.Sponsored(tx.Hash()) {
+		return tx.Value()
+	}
+	return tx.Cost()
+}
+
+// Sponsor marks a transaction as paid for by the given VRC25 token, reserving its
+// gas against the token.
+func (t *txLookup) Sponsor(tx *types.Transaction, token common.Address) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.unsponsor(tx.Hash())
+	t.sponsors[tx.Hash()] = txSponsorship{token: token, gas: tx.Gas()}
+	t.sponsoredGas[token] += tx.Gas()
+}
+
+// Unsponsor releases the sponsorship of a transaction, if any.
+func (t *txLookup) Unsponsor(hash common.Hash) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.unsponsor(hash)
+}
+
+// unsponsor releases the sponsorship of a transaction, assuming the lock is held.
+func (t *txLookup) unsponsor(hash common.Hash) {
+	sponsorship, ok := t.sponsors[hash]
+	if !ok {
+		return
+	}
+	delete(t.sponsors, hash)
+	t.sponsoredGas[sponsorship.token] -= sponsorship.gas
+	if t.sponsoredGas[sponsorship.token] == 0 {
+		delete(t.sponsoredGas, sponsorship.token)
+	}
+}
+
+// ResetSponsors releases the sponsorship of all transactions.
+func (t *txLookup) ResetSponsors() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.sponsors = make(map[common.Hash]txSponsorship)
+	t.sponsoredGas = make(map[common.Address]uint64)
+}
+
+// Sponsored reports whether a VRC25 token pays for the transaction.
+func (t *txLookup) Sponsored(hash common.Hash) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	_, ok := t.sponsors[hash]
+	return ok
+}
+
+// SponsoredBy reports whether the given VRC25 token pays for the transaction.
+func (t *txLookup) SponsoredBy(hash common.Hash, token common.Address) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	sponsorship, ok := t.sponsors[hash]
+	return ok && sponsorship.token == token
+}
+
+// SponsoredGas returns the gas of the pooled transactions the token pays for.
+func (t *txLookup) SponsoredGas(token common.Address) uint64 {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.sponsoredGas[token]
+}
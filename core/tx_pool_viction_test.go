@@ -0,0 +1,247 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vrc25"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	testVRC25Contract = common.HexToAddress("0x8c0faeb5c6bed2129b8674f262fd45c4e9468bee")
+	testVRC25Token    = common.HexToAddress("0x1000000000000000000000000000000000000025")
+	testVRC25GasPrice = big.NewInt(250)
+)
+
+// setupVRC25TxPool creates a transaction pool on a Viction chain where the test
+// token has capacity to sponsor the given amount of gas.
+func setupVRC25TxPool(config TxPoolConfig, sponsoredGas uint64) (*TxPool, *state.StateDB) {
+	chainconfig := *params.TestChainConfig
+	chainconfig.Viction = &params.VictionConfig{
+		VRC25Contract: testVRC25Contract,
+		VRC25GasPrice: (*math.Decimal256)(testVRC25GasPrice),
+	}
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	setVRC25Capacity(statedb, sponsoredGas)
+
+	blockchain := &testBlockChain{statedb, 10000000, new(event.Feed)}
+	return NewTxPool(config, &chainconfig, blockchain), statedb
+}
+
+// setVRC25Capacity sets the capacity of the test token to sponsor the given gas.
+func setVRC25Capacity(statedb *state.StateDB, gas uint64) {
+	capacity := new(big.Int).Mul(new(big.Int).SetUint64(gas), testVRC25GasPrice)
+	vrc25.NewRegistry(statedb, testVRC25Contract).SetCapacity(testVRC25Token, capacity)
+}
+
+// vrc25Transaction creates a token transfer, which the test token may sponsor.
+func vrc25Transaction(nonce uint64, gaslimit uint64, gasprice *big.Int, key *ecdsa.PrivateKey) *types.Transaction {
+	tx, _ := types.SignTx(types.NewTransaction(nonce, testVRC25Token, big.NewInt(0), gaslimit, gasprice, common.Hex2Bytes("a9059cbb")), types.HomesteadSigner{}, key)
+	return tx
+}
+
+// Tests that sponsored transactions are accepted from senders without funds, up
+// to the capacity and gas limit of their sponsor, and fall back to being paid by
+// the sender after that.
+func TestTransactionSponsoredCapacity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		capacity     uint64 // Gas the token has the capacity to pay for
+		sponsoredGas uint64 // Pool limit on the gas a token may sponsor
+		accepted     int    // Number of transactions expected to be accepted
+	}{
+		{capacity: 300000, sponsoredGas: 1000000, accepted: 3},
+		{capacity: 200000, sponsoredGas: 1000000, accepted: 2},
+		{capacity: 300000, sponsoredGas: 150000, accepted: 1},
+		{capacity: 50000, sponsoredGas: 1000000, accepted: 0},
+	}
+	for i, tt := range tests {
+		config := testTxPoolConfig
+		config.SponsoredGas = tt.sponsoredGas
+
+		pool, _ := setupVRC25TxPool(config, tt.capacity)
+		key, _ := crypto.GenerateKey()
+
+		for nonce := uint64(0); nonce < 3; nonce++ {
+			err := pool.addRemoteSync(vrc25Transaction(nonce, 100000, big.NewInt(1), key))
+			switch {
+			case int(nonce) < tt.accepted && err != nil:
+				t.Errorf("test %d: transaction %d: failed to add sponsored transaction: %v", i, nonce, err)
+			case int(nonce) >= tt.accepted && err != ErrInsufficientFunds:
+				t.Errorf("test %d: transaction %d: error mismatch: have %v, want %v", i, nonce, err, ErrInsufficientFunds)
+			}
+		}
+		if pending, _ := pool.Stats(); pending != tt.accepted {
+			t.Errorf("test %d: pending transactions mismatched: have %d, want %d", i, pending, tt.accepted)
+		}
+		if gas := pool.all.SponsoredGas(testVRC25Token); gas != uint64(tt.accepted)*100000 {
+			t.Errorf("test %d: sponsored gas mismatch: have %d, want %d", i, gas, tt.accepted*100000)
+		}
+		if err := validateTxPoolInternals(pool); err != nil {
+			t.Errorf("test %d: pool internal state corrupted: %v", i, err)
+		}
+		pool.Stop()
+	}
+}
+
+// Tests that sponsored transactions are priced by the VRC25 gas price, so a full
+// pool evicts them according to what their sponsor pays rather than the price the
+// sender declared.
+func TestTransactionSponsoredPricing(t *testing.T) {
+	t.Parallel()
+
+	config := testTxPoolConfig
+	config.GlobalSlots = 2
+	config.GlobalQueue = 2
+
+	pool, statedb := setupVRC25TxPool(config, 1000000)
+	defer pool.Stop()
+
+	sponsored, _ := crypto.GenerateKey()
+	paying, _ := crypto.GenerateKey()
+	statedb.AddBalance(crypto.PubkeyToAddress(paying.PublicKey), big.NewInt(1000000000))
+
+	// Fill the pool with a sponsored transaction declaring the lowest price
+	txs := types.Transactions{
+		vrc25Transaction(0, 100000, big.NewInt(1), sponsored),
+		pricedTransaction(0, 100000, big.NewInt(2), paying),
+		pricedTransaction(1, 100000, big.NewInt(3), paying),
+		pricedTransaction(2, 100000, big.NewInt(4), paying),
+	}
+	for i, err := range pool.AddRemotesSync(txs) {
+		if err != nil {
+			t.Fatalf("transaction %d: failed to add: %v", i, err)
+		}
+	}
+	// A new transaction evicts the cheapest paid one rather than the sponsored one
+	if err := pool.addRemoteSync(pricedTransaction(3, 100000, big.NewInt(5), paying)); err != nil {
+		t.Fatalf("failed to add transaction above the cheapest paid one: %v", err)
+	}
+	if !pool.Has(txs[0].Hash()) {
+		t.Errorf("sponsored transaction evicted before cheaper paid one")
+	}
+	if pool.Has(txs[1].Hash()) {
+		t.Errorf("cheapest paid transaction not evicted")
+	}
+	// Raising the minimum price only drops the sponsored transaction above the VRC25 gas price
+	pool.SetGasPrice(big.NewInt(100))
+	if !pool.Has(txs[0].Hash()) {
+		t.Errorf("sponsored transaction dropped below the VRC25 gas price")
+	}
+	pool.SetGasPrice(big.NewInt(251))
+	if pool.Has(txs[0].Hash()) {
+		t.Errorf("sponsored transaction kept above the VRC25 gas price")
+	}
+	if gas := pool.all.SponsoredGas(testVRC25Token); gas != 0 {
+		t.Errorf("sponsored gas not released: have %d, want 0", gas)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the capacity of a sponsor is re-validated when the pool is reset to
+// a new head, dropping sponsored transactions it can no longer pay for.
+func TestTransactionSponsoredReset(t *testing.T) {
+	t.Parallel()
+
+	pool, statedb := setupVRC25TxPool(testTxPoolConfig, 300000)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		if err := pool.addRemoteSync(vrc25Transaction(nonce, 100000, big.NewInt(1), key)); err != nil {
+			t.Fatalf("transaction %d: failed to add sponsored transaction: %v", nonce, err)
+		}
+	}
+	// The sponsor spends part of its capacity in a new block
+	setVRC25Capacity(statedb, 150000)
+	<-pool.requestReset(nil, nil)
+
+	if pending, queued := pool.Stats(); pending != 1 || queued != 0 {
+		t.Errorf("pool size mismatch: have %d/%d, want 1/0", pending, queued)
+	}
+	if gas := pool.all.SponsoredGas(testVRC25Token); gas != 100000 {
+		t.Errorf("sponsored gas mismatch: have %d, want %d", gas, 100000)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	// Transactions the sender can pay for itself survive losing their sponsor
+	statedb.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000))
+	if err := pool.addRemoteSync(vrc25Transaction(1, 100000, big.NewInt(1), key)); err != nil {
+		t.Fatalf("failed to add paid transaction: %v", err)
+	}
+	setVRC25Capacity(statedb, 0)
+	<-pool.requestReset(nil, nil)
+
+	if pending, _ := pool.Stats(); pending != 2 {
+		t.Errorf("pending transactions mismatched: have %d, want %d", pending, 2)
+	}
+	if gas := pool.all.SponsoredGas(testVRC25Token); gas != 0 {
+		t.Errorf("sponsored gas mismatch: have %d, want 0", gas)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that transactions to a registered VRC25 token must cover the token fee
+// even if the token cannot sponsor them, in which case the sender pays the gas.
+func TestTransactionVRC25FeeOverCapacity(t *testing.T) {
+	t.Parallel()
+
+	// The token has capacity left, but not enough to sponsor the transactions
+	pool, statedb := setupVRC25TxPool(testTxPoolConfig, 50000)
+	defer pool.Stop()
+
+	// The token charges a minimum fee, kept in the second slot of its storage
+	statedb.SetState(testVRC25Token, state.GetStorageKeyForSlot(1), common.BigToHash(big.NewInt(10)))
+
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	statedb.AddBalance(from, big.NewInt(1000000))
+
+	if err := pool.addRemoteSync(vrc25Transaction(0, 100000, big.NewInt(1), key)); err != vrc25.ErrInsufficientFee {
+		t.Fatalf("error mismatch: have %v, want %v", err, vrc25.ErrInsufficientFee)
+	}
+	// Once the sender holds the token fee, it pays for the gas itself
+	vrc25.NewToken(statedb, testVRC25Token).SetBalance(from, big.NewInt(10))
+
+	tx := vrc25Transaction(0, 100000, big.NewInt(1), key)
+	if err := pool.addRemoteSync(tx); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if pool.all.Sponsored(tx.Hash()) {
+		t.Errorf("transaction sponsored above the token capacity")
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
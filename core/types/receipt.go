@@ -61,6 +61,12 @@ type Receipt struct {
 	ContractAddress common.Address `json:"contractAddress"`
 	GasUsed         uint64         `json:"gasUsed" gencodec:"required"`
 
+	// VRC25 fields: These fields are set by vic-geth when the gas of the transaction was
+	// paid by the VRC25 contract instead of its sender. They are stored in the chain
+	// database.
+	Payer        common.Address `json:"payer"`
+	SponsoredFee *big.Int       `json:"sponsoredFee"`
+
 	// Inclusion information: These fields provide information about the inclusion of the
 	// transaction corresponding to this receipt.
 	BlockHash        common.Hash `json:"blockHash,omitempty"`
@@ -73,6 +79,7 @@ type receiptMarshaling struct {
 	Status            hexutil.Uint64
 	CumulativeGasUsed hexutil.Uint64
 	GasUsed           hexutil.Uint64
+	SponsoredFee      *hexutil.Big
 	BlockNumber       *hexutil.Big
 	TransactionIndex  hexutil.Uint
 }
@@ -92,6 +99,17 @@ type storedReceiptRLP struct {
 	Logs              []*LogForStorage
 }
 
+// sponsoredStoredReceiptRLP is the storage encoding of a receipt whose gas was paid
+// by the VRC25 contract. Receipts paid by their sender keep the storedReceiptRLP
+// encoding.
+type sponsoredStoredReceiptRLP struct {
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Logs              []*LogForStorage
+	Payer             common.Address
+	SponsoredFee      *big.Int
+}
+
 // v4StoredReceiptRLP is the storage encoding of a receipt used in database version 4.
 type v4StoredReceiptRLP struct {
 	PostStateOrStatus []byte
@@ -168,6 +186,11 @@ func (r *Receipt) statusEncoding() []byte {
 	return r.PostState
 }
 
+// Sponsored reports whether the gas of the transaction was paid by the VRC25 contract.
+func (r *Receipt) Sponsored() bool {
+	return r.SponsoredFee != nil
+}
+
 // Size returns the approximate memory used by all internal contents. It is used
 // to approximate and limit the memory consumption of various caches.
 func (r *Receipt) Size() common.StorageSize {
@@ -187,15 +210,24 @@ type ReceiptForStorage Receipt
 // EncodeRLP implements rlp.Encoder, and flattens all content fields of a receipt
 // into an RLP stream.
 func (r *ReceiptForStorage) EncodeRLP(w io.Writer) error {
-	enc := &storedReceiptRLP{
-		PostStateOrStatus: (*Receipt)(r).statusEncoding(),
-		CumulativeGasUsed: r.CumulativeGasUsed,
-		Logs:              make([]*LogForStorage, len(r.Logs)),
-	}
+	logs := make([]*LogForStorage, len(r.Logs))
 	for i, log := range r.Logs {
-		enc.Logs[i] = (*LogForStorage)(log)
+		logs[i] = (*LogForStorage)(log)
+	}
+	if (*Receipt)(r).Sponsored() {
+		return rlp.Encode(w, &sponsoredStoredReceiptRLP{
+			PostStateOrStatus: (*Receipt)(r).statusEncoding(),
+			CumulativeGasUsed: r.CumulativeGasUsed,
+			Logs:              logs,
+			Payer:             r.Payer,
+			SponsoredFee:      r.SponsoredFee,
+		})
 	}
-	return rlp.Encode(w, enc)
+	return rlp.Encode(w, &storedReceiptRLP{
+		PostStateOrStatus: (*Receipt)(r).statusEncoding(),
+		CumulativeGasUsed: r.CumulativeGasUsed,
+		Logs:              logs,
+	})
 }
 
 // DecodeRLP implements rlp.Decoder, and loads both consensus and implementation
@@ -212,6 +244,9 @@ func (r *ReceiptForStorage) DecodeRLP(s *rlp.Stream) error {
 	if err := decodeStoredReceiptRLP(r, blob); err == nil {
 		return nil
 	}
+	if err := decodeSponsoredStoredReceiptRLP(r, blob); err == nil {
+		return nil
+	}
 	if err := decodeV3StoredReceiptRLP(r, blob); err == nil {
 		return nil
 	}
@@ -236,6 +271,26 @@ func decodeStoredReceiptRLP(r *ReceiptForStorage, blob []byte) error {
 	return nil
 }
 
+func decodeSponsoredStoredReceiptRLP(r *ReceiptForStorage, blob []byte) error {
+	var stored sponsoredStoredReceiptRLP
+	if err := rlp.DecodeBytes(blob, &stored); err != nil {
+		return err
+	}
+	if err := (*Receipt)(r).setStatus(stored.PostStateOrStatus); err != nil {
+		return err
+	}
+	r.CumulativeGasUsed = stored.CumulativeGasUsed
+	r.Logs = make([]*Log, len(stored.Logs))
+	for i, log := range stored.Logs {
+		r.Logs[i] = (*Log)(log)
+	}
+	r.Bloom = CreateBloom(Receipts{(*Receipt)(r)})
+	r.Payer = stored.Payer
+	r.SponsoredFee = stored.SponsoredFee
+
+	return nil
+}
+
 func decodeV4StoredReceiptRLP(r *ReceiptForStorage, blob []byte) error {
 	var stored v4StoredReceiptRLP
 	if err := rlp.DecodeBytes(blob, &stored); err != nil {
@@ -151,6 +151,63 @@ func encodeAsV3StoredReceiptRLP(want *Receipt) ([]byte, error) {
 	return rlp.EncodeToBytes(stored)
 }
 
+// Tests that the payer and fee of receipts sponsored by the VRC25 contract survive a
+// storage roundtrip, and that other receipts keep the legacy storage encoding.
+func TestSponsoredReceiptStorage(t *testing.T) {
+	receipt := &Receipt{
+		Status:            ReceiptStatusSuccessful,
+		CumulativeGasUsed: 21000,
+		Logs: []*Log{
+			{
+				Address: common.BytesToAddress([]byte{0x11}),
+				Topics:  []common.Hash{common.HexToHash("dead")},
+				Data:    []byte{0x01},
+			},
+		},
+	}
+	receipt.Bloom = CreateBloom(Receipts{receipt})
+
+	legacy, err := encodeAsStoredReceiptRLP(receipt)
+	if err != nil {
+		t.Fatalf("failed to encode legacy receipt: %v", err)
+	}
+	enc, err := rlp.EncodeToBytes((*ReceiptForStorage)(receipt))
+	if err != nil {
+		t.Fatalf("failed to encode receipt: %v", err)
+	}
+	if !bytes.Equal(enc, legacy) {
+		t.Fatalf("unsponsored receipt encoding mismatch: have %x, want %x", enc, legacy)
+	}
+	receipt.Payer = common.HexToAddress("0x8c0faeb5c6bed2129b8674f262fd45c4e9468bee")
+	receipt.SponsoredFee = big.NewInt(5250000000000000)
+
+	if enc, err = rlp.EncodeToBytes((*ReceiptForStorage)(receipt)); err != nil {
+		t.Fatalf("failed to encode sponsored receipt: %v", err)
+	}
+	var dec ReceiptForStorage
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatalf("failed to decode sponsored receipt: %v", err)
+	}
+	if !(*Receipt)(&dec).Sponsored() {
+		t.Fatalf("decoded receipt not sponsored")
+	}
+	if dec.Payer != receipt.Payer {
+		t.Errorf("payer mismatch: have %x, want %x", dec.Payer, receipt.Payer)
+	}
+	if dec.SponsoredFee.Cmp(receipt.SponsoredFee) != 0 {
+		t.Errorf("sponsored fee mismatch: have %v, want %v", dec.SponsoredFee, receipt.SponsoredFee)
+	}
+	if dec.Status != receipt.Status || dec.CumulativeGasUsed != receipt.CumulativeGasUsed || dec.Bloom != receipt.Bloom || len(dec.Logs) != len(receipt.Logs) {
+		t.Errorf("consensus fields mismatch: have %+v, want %+v", dec, receipt)
+	}
+	// The consensus encoding must not be affected by the sponsorship
+	sponsored, _ := rlp.EncodeToBytes(receipt)
+	receipt.Payer, receipt.SponsoredFee = common.Address{}, nil
+	if plain, _ := rlp.EncodeToBytes(receipt); !bytes.Equal(sponsored, plain) {
+		t.Errorf("consensus encoding changed by sponsorship")
+	}
+}
+
 // Tests that receipt data can be correctly derived from the contextual infos
 func TestDeriveFields(t *testing.T) {
 	// Create a few transactions to have receipts for
@@ -0,0 +1,91 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vrc25
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Storage layout of the VRC25 issuer contract (contracts/zerogas).
+const (
+	registryMinCapSlot      = 0 // uint256 _minCap
+	registryTokensSlot      = 1 // address[] _tokens
+	registryTokensStateSlot = 2 // mapping(address => uint256) tokensState
+)
+
+// Registry gives typed access to the state of the VRC25 issuer contract, which keeps
+// the tokens applied for sponsorship and the fee capacity they have left.
+type Registry struct {
+	StateDB vm.StateDB
+	Address common.Address
+}
+
+// NewRegistry returns the registry deployed at the given address.
+func NewRegistry(statedb vm.StateDB, address common.Address) *Registry {
+	return &Registry{StateDB: statedb, Address: address}
+}
+
+// MinCap returns the minimum capacity a token must deposit to apply for sponsorship.
+func (r *Registry) MinCap() *big.Int {
+	return r.StateDB.GetState(r.Address, state.GetStorageKeyForSlot(registryMinCapSlot)).Big()
+}
+
+// SetMinCap overrides the minimum capacity a token must deposit.
+func (r *Registry) SetMinCap(minCap *big.Int) {
+	r.StateDB.SetState(r.Address, state.GetStorageKeyForSlot(registryMinCapSlot), common.BigToHash(minCap))
+}
+
+// Tokens returns the tokens that applied for sponsorship, in application order.
+func (r *Registry) Tokens() []common.Address {
+	length := r.StateDB.GetState(r.Address, state.GetStorageKeyForSlot(registryTokensSlot)).Big()
+	if !length.IsUint64() {
+		return nil
+	}
+	tokens := make([]common.Address, length.Uint64())
+	for i := range tokens {
+		tokens[i] = common.BytesToAddress(r.StateDB.GetState(r.Address, arrayElementKey(registryTokensSlot, uint64(i))).Bytes())
+	}
+	return tokens
+}
+
+// Capacity returns the fee capacity the token has left to sponsor transactions.
+func (r *Registry) Capacity(token common.Address) *big.Int {
+	return r.StateDB.GetState(r.Address, state.GetStorageKeyForMapping(token.Hash(), registryTokensStateSlot)).Big()
+}
+
+// SetCapacity overrides the fee capacity of the token.
+func (r *Registry) SetCapacity(token common.Address, capacity *big.Int) {
+	r.StateDB.SetState(r.Address, state.GetStorageKeyForMapping(token.Hash(), registryTokensStateSlot), common.BigToHash(capacity))
+}
+
+// IsSponsored reports whether the token has any fee capacity left to sponsor
+// transactions sent to it.
+func (r *Registry) IsSponsored(token common.Address) bool {
+	return r.Capacity(token).Sign() > 0
+}
+
+// arrayElementKey returns the storage key of an element of the dynamic array
+// declared at the given slot.
+func arrayElementKey(slot uint64, index uint64) common.Hash {
+	base := crypto.Keccak256Hash(state.GetStorageKeyForSlot(slot).Bytes()).Big()
+	return common.BigToHash(base.Add(base, new(big.Int).SetUint64(index)))
+}
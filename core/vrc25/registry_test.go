@@ -0,0 +1,145 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vrc25_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/zerogas/contract"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vrc25"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// issuerCode returns the code of a token whose every call returns the given issuer,
+// as expected by the apply method of the VRC25 issuer contract.
+func issuerCode(issuer common.Address) []byte {
+	code := append([]byte{byte(0x73)}, issuer.Bytes()...) // PUSH20 issuer
+	return append(code, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3)
+}
+
+// newTestEVM creates an EVM executing plain calls on top of the state.
+func newTestEVM(statedb *state.StateDB, origin common.Address) *vm.EVM {
+	blockContext := vm.BlockContext{
+		CanTransfer: func(db vm.StateDB, addr common.Address, amount *big.Int) bool {
+			return db.GetBalance(addr).Cmp(amount) >= 0
+		},
+		Transfer: func(db vm.StateDB, sender, recipient common.Address, amount *big.Int) {
+			db.SubBalance(sender, amount)
+			db.AddBalance(recipient, amount)
+		},
+		BlockNumber: new(big.Int),
+		Time:        new(big.Int),
+		Difficulty:  new(big.Int),
+		GasLimit:    10000000,
+	}
+	txContext := vm.TxContext{Origin: origin, GasPrice: new(big.Int)}
+	return vm.NewEVM(blockContext, txContext, statedb, params.TestChainConfig, vm.Config{})
+}
+
+// Tests that the registry reads the slots actually written by the compiled VRC25
+// issuer contract, agreeing with its own views.
+func TestRegistryLayout(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(contract.ZeroGasABI))
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	var (
+		issuer = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		tokens = []common.Address{
+			common.HexToAddress("0x2000000000000000000000000000000000000001"),
+			common.HexToAddress("0x2000000000000000000000000000000000000002"),
+		}
+		minCap = big.NewInt(10)
+	)
+	statedb.AddBalance(issuer, big.NewInt(1000))
+	for _, token := range tokens {
+		statedb.SetCode(token, issuerCode(issuer))
+	}
+	// Deploy the issuer contract and apply both tokens, charging the first one again
+	args, err := parsed.Pack("", minCap)
+	if err != nil {
+		t.Fatalf("failed to pack constructor: %v", err)
+	}
+	evm := newTestEVM(statedb, issuer)
+	_, address, _, err := evm.Create(vm.AccountRef(issuer), append(common.FromHex(contract.ZeroGasBin), args...), 10000000, new(big.Int))
+	if err != nil {
+		t.Fatalf("failed to deploy issuer contract: %v", err)
+	}
+	call := func(value int64, method string, args ...interface{}) []byte {
+		input, err := parsed.Pack(method, args...)
+		if err != nil {
+			t.Fatalf("failed to pack %s: %v", method, err)
+		}
+		ret, _, err := evm.Call(vm.AccountRef(issuer), address, input, 10000000, big.NewInt(value))
+		if err != nil {
+			t.Fatalf("failed to call %s: %v", method, err)
+		}
+		return ret
+	}
+	call(10, "apply", tokens[0])
+	call(20, "apply", tokens[1])
+	call(15, "charge", tokens[0])
+
+	registry := vrc25.NewRegistry(statedb, address)
+	if have := registry.MinCap(); have.Cmp(minCap) != 0 {
+		t.Errorf("min cap mismatch: have %v, want %v", have, minCap)
+	}
+	var applied []common.Address
+	if err := parsed.UnpackIntoInterface(&applied, "tokens", call(0, "tokens")); err != nil {
+		t.Fatalf("failed to unpack tokens: %v", err)
+	}
+	if have := registry.Tokens(); len(have) != len(applied) || have[0] != applied[0] || have[1] != applied[1] {
+		t.Errorf("tokens mismatch: have %x, want %x", have, applied)
+	}
+	for i, want := range []int64{25, 20} {
+		var capacity *big.Int
+		if err := parsed.UnpackIntoInterface(&capacity, "getTokenCapacity", call(0, "getTokenCapacity", tokens[i])); err != nil {
+			t.Fatalf("failed to unpack capacity: %v", err)
+		}
+		if capacity.Int64() != want {
+			t.Fatalf("token %d: contract capacity mismatch: have %v, want %v", i, capacity, want)
+		}
+		if have := registry.Capacity(tokens[i]); have.Cmp(capacity) != 0 {
+			t.Errorf("token %d: capacity mismatch: have %v, want %v", i, have, capacity)
+		}
+		if !registry.IsSponsored(tokens[i]) {
+			t.Errorf("token %d: not sponsored", i)
+		}
+	}
+	if registry.IsSponsored(issuer) {
+		t.Errorf("unapplied account sponsored")
+	}
+	// Writes through the registry must be visible to the contract
+	registry.SetCapacity(tokens[1], big.NewInt(7))
+
+	var capacity *big.Int
+	if err := parsed.UnpackIntoInterface(&capacity, "getTokenCapacity", call(0, "getTokenCapacity", tokens[1])); err != nil {
+		t.Fatalf("failed to unpack capacity: %v", err)
+	}
+	if capacity.Int64() != 7 {
+		t.Errorf("updated capacity mismatch: have %v, want 7", capacity)
+	}
+}
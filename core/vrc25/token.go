@@ -0,0 +1,86 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vrc25
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Storage layout of VRC25 (formerly TRC21) tokens.
+const (
+	tokenBalancesSlot = 0 // mapping(address => uint256) _balances
+	tokenMinFeeSlot   = 1 // uint256 _minFee
+	tokenIssuerSlot   = 2 // address _issuer
+)
+
+// Token gives typed access to the state of a VRC25 token contract.
+type Token struct {
+	StateDB vm.StateDB
+	Address common.Address
+}
+
+// NewToken returns the token deployed at the given address.
+func NewToken(statedb vm.StateDB, address common.Address) *Token {
+	return &Token{StateDB: statedb, Address: address}
+}
+
+// BalanceOf returns the token balance of the account.
+func (t *Token) BalanceOf(addr common.Address) *big.Int {
+	return t.StateDB.GetState(t.Address, t.balanceKey(addr)).Big()
+}
+
+// SetBalance overrides the token balance of the account.
+func (t *Token) SetBalance(addr common.Address, balance *big.Int) {
+	t.StateDB.SetState(t.Address, t.balanceKey(addr), common.BigToHash(balance))
+}
+
+// MinFee returns the minimum fee the token charges on every transaction.
+func (t *Token) MinFee() *big.Int {
+	return t.StateDB.GetState(t.Address, state.GetStorageKeyForSlot(tokenMinFeeSlot)).Big()
+}
+
+// Issuer returns the account the token fees are paid to.
+func (t *Token) Issuer() common.Address {
+	return common.BytesToAddress(t.StateDB.GetState(t.Address, state.GetStorageKeyForSlot(tokenIssuerSlot)).Bytes())
+}
+
+// Fee returns the amount of tokens PayFeeWithVRC25 charges the sender: its balance
+// capped to the minimum fee, or zero if the token has no issuer to pay.
+func (t *Token) Fee(from common.Address) *big.Int {
+	balance := t.BalanceOf(from)
+	if balance.Sign() <= 0 || !t.hasIssuer() {
+		return new(big.Int)
+	}
+	minFee := t.MinFee()
+	if balance.Cmp(minFee) < 0 {
+		return balance
+	}
+	return minFee
+}
+
+// hasIssuer reports whether the issuer slot of the token is set.
+func (t *Token) hasIssuer() bool {
+	return t.StateDB.GetState(t.Address, state.GetStorageKeyForSlot(tokenIssuerSlot)) != (common.Hash{})
+}
+
+func (t *Token) balanceKey(addr common.Address) common.Hash {
+	return state.GetStorageKeyForMapping(addr.Hash(), tokenBalancesSlot)
+}
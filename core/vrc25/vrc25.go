@@ -1,4 +1,19 @@
 // Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
 package vrc25
 
 import (
@@ -7,21 +22,10 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/vm"
 )
 
 var (
-	SlotVRC25Contract = map[string]uint64{
-		"minCap":      0,
-		"tokens":      1,
-		"tokensState": 2,
-	}
-	SlotVRC25Token = map[string]uint64{
-		"balances": 0,
-		"minFee":   1,
-		"issuer":   2,
-	}
 	transferFunctionSelector     = common.Hex2Bytes("0xa9059cbb")
 	transferFromFunctionSelector = common.Hex2Bytes("0x23b872dd")
 )
@@ -31,52 +35,20 @@ var (
 	ErrInsufficientFee = errors.New("insufficient VRC25 token fee")
 )
 
+// PayFeeWithVRC25 charges the token fee of a transaction to its sender, crediting it
+// to the issuer of the token.
 func PayFeeWithVRC25(statedb vm.StateDB, from common.Address, token common.Address) error {
-	// 1. Check for valid statedb
 	if statedb == nil {
 		return ErrInvalidParams
 	}
-	// 2. Retrieve the balance of the from address for the VRC25 token
-	slotBalances := SlotVRC25Token["balances"]
-	balanceKey := state.GetStorageKeyForMapping(from.Hash(), slotBalances)
-	balanceHash := statedb.GetState(token, balanceKey)
-
-	if balanceHash != (common.Hash{}) {
-		// 3. Check if balance is positive
-		balance := balanceHash.Big()
-		if balance.Sign() <= 0 {
-			return nil
-		}
-		// 4. Retrieve the issuer address of the token
-		issuerKey := state.GetStorageKeyForSlot(SlotVRC25Token["issuer"])
-		issuerHash := statedb.GetState(token, issuerKey)
-		if issuerHash == (common.Hash{}) {
-			return nil
-		}
-		issuerAddr := common.BytesToAddress(issuerHash.Bytes())
-
-		// 5. Retrieve the minimum fee required by the token
-		minFeeKey := state.GetStorageKeyForSlot(SlotVRC25Token["minFee"])
-		minFeeHash := statedb.GetState(token, minFeeKey)
-		minFee := minFeeHash.Big()
-
-		// 6. Determine the actual fee to charge (lesser of balance or minFee)
-		feeUsed := new(big.Int).Set(minFee)
-		if balance.Cmp(minFee) < 0 {
-			feeUsed.Set(balance)
-		}
-
-		// 7. Deduct the fee from the user's balance and update state
-		newBalance := new(big.Int).Sub(balance, feeUsed)
-		statedb.SetState(token, balanceKey, common.BigToHash(newBalance))
-
-		// 8. Add the fee to the issuer's balance and update state
-		issuerBalanceKey := state.GetStorageKeyForMapping(issuerAddr.Hash(), slotBalances)
-		issuerBalanceHash := statedb.GetState(token, issuerBalanceKey)
-		issuerBalance := issuerBalanceHash.Big()
-		newIssuerBalance := new(big.Int).Add(issuerBalance, feeUsed)
-		statedb.SetState(token, issuerBalanceKey, common.BigToHash(newIssuerBalance))
+	t := NewToken(statedb, token)
+	feeUsed := t.Fee(from)
+	if feeUsed.Sign() == 0 {
+		return nil
 	}
+	issuer := t.Issuer()
+	t.SetBalance(from, new(big.Int).Sub(t.BalanceOf(from), feeUsed))
+	t.SetBalance(issuer, new(big.Int).Add(t.BalanceOf(issuer), feeUsed))
 	return nil
 }
 
@@ -86,24 +58,13 @@ func UpdateFeeCapacity(statedb vm.StateDB, vrc25Contract common.Address, newBala
 	if statedb == nil || len(newBalance) == 0 {
 		return
 	}
-	slotTokensState := SlotVRC25Contract["tokensState"]
+	registry := NewRegistry(statedb, vrc25Contract)
 	for token, value := range newBalance {
-		balanceKey := state.GetStorageKeyForMapping(token.Hash(), slotTokensState)
-		statedb.SetState(vrc25Contract, balanceKey, common.BigToHash(value))
+		registry.SetCapacity(token, value)
 	}
 	statedb.SubBalance(vrc25Contract, totalFeeUsed)
 }
 
-// we use vm.StateDB interface instead of *StateDB
-func GetFeeCapacity(statedb vm.StateDB, vrc25Contract common.Address, addr *common.Address) *big.Int {
-	if addr == nil {
-		return nil
-	}
-	feeCapKey := state.GetStorageKeyForMapping(addr.Hash(), SlotVRC25Contract["tokensState"])
-	feeCapHash := statedb.GetState(vrc25Contract, feeCapKey)
-	return feeCapHash.Big()
-}
-
 // This function validates VRC25 transactions
 // User's balance must be greater than or equal to the required fee
 func ValidateVRC25Transaction(statedb vm.StateDB, vrc25Contract common.Address, from common.Address, to common.Address, data []byte) error {
@@ -111,20 +72,14 @@ func ValidateVRC25Transaction(statedb vm.StateDB, vrc25Contract common.Address,
 		return ErrInvalidParams
 	}
 
-	slotBalances := SlotVRC25Token["balances"]
-	balanceKey := state.GetStorageKeyForMapping(from.Hash(), slotBalances)
-	balanceHash := statedb.GetState(to, balanceKey)
-	minFeeSlot := SlotVRC25Token["minFee"]
-	minFeeKey := state.GetStorageKeyForSlot(minFeeSlot)
-	minFeeHash := statedb.GetState(to, minFeeKey)
+	t := NewToken(statedb, to)
+	balance, minFee := t.BalanceOf(from), t.MinFee()
 
-	if balanceHash == (common.Hash{}) {
-		if minFeeHash != (common.Hash{}) {
+	if balance.Sign() == 0 {
+		if minFee.Sign() != 0 {
 			return ErrInsufficientFee
 		}
 	} else {
-		balance := balanceHash.Big()
-		minFee := minFeeHash.Big()
 		value := big.NewInt(0)
 
 		if len(data) > 4 {
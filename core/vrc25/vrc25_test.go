@@ -0,0 +1,64 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vrc25
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+)
+
+// Tests that the token fee is capped to the balance of the sender, and credited to
+// the issuer of the token.
+func TestPayFeeWithVRC25(t *testing.T) {
+	var (
+		token  = common.HexToAddress("0x2000000000000000000000000000000000000001")
+		issuer = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		rich   = common.HexToAddress("0x3000000000000000000000000000000000000001")
+		poor   = common.HexToAddress("0x3000000000000000000000000000000000000002")
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetState(token, state.GetStorageKeyForSlot(tokenMinFeeSlot), common.BigToHash(big.NewInt(100)))
+	statedb.SetState(token, state.GetStorageKeyForSlot(tokenIssuerSlot), issuer.Hash())
+
+	vrc := NewToken(statedb, token)
+	vrc.SetBalance(rich, big.NewInt(1000))
+	vrc.SetBalance(poor, big.NewInt(30))
+
+	if vrc.Issuer() != issuer {
+		t.Fatalf("issuer mismatch: have %x, want %x", vrc.Issuer(), issuer)
+	}
+	for _, from := range []common.Address{rich, poor} {
+		if err := PayFeeWithVRC25(statedb, from, token); err != nil {
+			t.Fatalf("failed to pay fee of %x: %v", from, err)
+		}
+	}
+	for addr, want := range map[common.Address]int64{rich: 900, poor: 0, issuer: 130} {
+		if have := vrc.BalanceOf(addr); have.Int64() != want {
+			t.Errorf("balance of %x mismatch: have %v, want %d", addr, have, want)
+		}
+	}
+	if err := ValidateVRC25Transaction(statedb, common.Address{}, poor, token, []byte{}); err != ErrInsufficientFee {
+		t.Errorf("empty sender validation: have %v, want %v", err, ErrInsufficientFee)
+	}
+	if err := ValidateVRC25Transaction(statedb, common.Address{}, rich, token, []byte{}); err != nil {
+		t.Errorf("funded sender validation: have %v, want nil", err)
+	}
+}
@@ -202,12 +202,15 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 
 			// Fetch and execute the next block trace tasks
 			for task := range tasks {
-				signer := types.MakeSigner(api.eth.blockchain.Config(), task.block.Number())
 				blockCtx := core.NewEVMBlockContext(task.block.Header(), api.eth.blockchain, nil)
+				processor, err := api.prepareReplay(task.block, task.statedb)
+
 				// Trace all the transactions contained within
 				for i, tx := range task.block.Transactions() {
-					msg, _ := tx.AsMessage(signer)
-					res, err := api.traceTx(ctx, msg, blockCtx, task.statedb, config)
+					var res interface{}
+					if err == nil {
+						res, err = api.traceBlockTx(ctx, processor, task.block, tx, blockCtx, task.statedb, config)
+					}
 					if err != nil {
 						task.results[i] = &txTraceResult{Error: err.Error()}
 						log.Warn("Tracing failed", "hash", tx.Hash(), "block", task.block.NumberU64(), "err", err)
@@ -252,7 +255,10 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 			}
 			close(results)
 		}()
-		// Feed all the blocks both into the tracer, as well as fast process concurrently
+		// Feed all the blocks both into the tracer, as well as fast process concurrently.
+		// The blocks are processed apart from the processor importing the chain, whose
+		// Viction state must not be shared.
+		processor := core.NewStateProcessor(api.eth.blockchain.Config(), api.eth.blockchain, api.eth.engine)
 		for number = start.NumberU64() + 1; number <= end.NumberU64(); number++ {
 			// Stop tracing if interruption was requested
 			select {
@@ -288,7 +294,7 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 				traced += uint64(len(txs))
 			}
 			// Generate the next state snapshot fast without tracing
-			_, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, vm.Config{})
+			_, _, _, err := processor.Process(block, statedb, vm.Config{})
 			if err != nil {
 				failed = err
 				break
@@ -457,10 +463,12 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 	if err != nil {
 		return nil, err
 	}
+	processor, err := api.prepareReplay(block, statedb)
+	if err != nil {
+		return nil, err
+	}
 	// Execute all the transaction contained within the block concurrently
 	var (
-		signer = types.MakeSigner(api.eth.blockchain.Config(), block.Number())
-
 		txs     = block.Transactions()
 		results = make([]*txTraceResult, len(txs))
 
@@ -478,8 +486,7 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 			defer pend.Done()
 			// Fetch and execute the next transaction trace tasks
 			for task := range jobs {
-				msg, _ := txs[task.index].AsMessage(signer)
-				res, err := api.traceTx(ctx, msg, blockCtx, task.statedb, config)
+				res, err := api.traceBlockTx(ctx, processor, block, txs[task.index], blockCtx, task.statedb, config)
 				if err != nil {
 					results[task.index] = &txTraceResult{Error: err.Error()}
 					continue
@@ -495,17 +502,13 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 		jobs <- &txTraceTask{statedb: statedb.Copy(), index: i}
 
 		// Generate the next state snapshot fast without tracing
-		msg, _ := tx.AsMessage(signer)
-		txContext := core.NewEVMTxContext(msg)
-
-		vmenv := vm.NewEVM(blockCtx, txContext, statedb, api.eth.blockchain.Config(), vm.Config{})
-		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas())); err != nil {
+		if err := api.replayTx(processor, block, tx, blockCtx, statedb); err != nil {
 			failed = err
 			break
 		}
 		// Finalize the state so any modifications are written to the trie
 		// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
-		statedb.Finalise(vmenv.ChainConfig().IsEIP158(block.Number()))
+		statedb.Finalise(api.eth.blockchain.Config().IsEIP158(block.Number()))
 	}
 	close(jobs)
 	pend.Wait()
@@ -543,6 +546,10 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 	if err != nil {
 		return nil, err
 	}
+	processor, err := api.prepareReplay(block, statedb)
+	if err != nil {
+		return nil, err
+	}
 	// Retrieve the tracing configurations, or use default values
 	var (
 		logConfig vm.LogConfig
@@ -589,6 +596,24 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 			writer    *bufio.Writer
 			err       error
 		)
+		// Viction special transactions are applied without EVM execution, leaving
+		// nothing to dump for them
+		if err := processor.PrepareTransaction(block, tx, msg, statedb); err != nil {
+			return dumps, err
+		}
+		handled, receipt, err := processor.ApplyVictionTransaction(statedb, tx, block.Header(), new(uint64))
+		if err != nil {
+			return dumps, err
+		}
+		if handled {
+			if err := processor.FinishTransaction(tx, msg, statedb, receipt.Status == types.ReceiptStatusFailed, receipt.GasUsed); err != nil {
+				return dumps, err
+			}
+			if tx.Hash() == txHash {
+				break
+			}
+			continue
+		}
 		// If the transaction needs tracing, swap out the configs
 		if tx.Hash() == txHash || txHash == (common.Hash{}) {
 			// Generate a unique temporary file to dump it into
@@ -612,7 +637,7 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 		}
 		// Execute the transaction and flush any traces to disk
 		vmenv := vm.NewEVM(vmctx, txContext, statedb, chainConfig, vmConf)
-		_, err = core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas()))
+		result, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas()))
 		if writer != nil {
 			writer.Flush()
 		}
@@ -623,6 +648,9 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 		if err != nil {
 			return dumps, err
 		}
+		if err := processor.FinishTransaction(tx, msg, statedb, result.Failed(), result.UsedGas); err != nil {
+			return dumps, err
+		}
 		// Finalize the state so any modifications are written to the trie
 		// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
 		statedb.Finalise(vmenv.ChainConfig().IsEIP158(block.Number()))
@@ -676,11 +704,13 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 			return nil, err
 		}
 	}
-	// State was available at historical point, regenerate
+	// State was available at historical point, regenerate it with a processor apart
+	// from the one importing the chain
 	var (
-		start  = time.Now()
-		logged time.Time
-		proot  common.Hash
+		start     = time.Now()
+		logged    time.Time
+		proot     common.Hash
+		processor = core.NewStateProcessor(api.eth.blockchain.Config(), api.eth.blockchain, api.eth.engine)
 	)
 	for block.NumberU64() < origin {
 		// Print progress logs if long enough time elapsed
@@ -692,7 +722,7 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 		if block = api.eth.blockchain.GetBlockByNumber(block.NumberU64() + 1); block == nil {
 			return nil, fmt.Errorf("block #%d not found", block.NumberU64()+1)
 		}
-		_, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, vm.Config{})
+		_, _, _, err := processor.Process(block, statedb, vm.Config{})
 		if err != nil {
 			return nil, fmt.Errorf("processing block %d failed: %v", block.NumberU64(), err)
 		}
@@ -732,12 +762,12 @@ func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Ha
 	if block == nil {
 		return nil, fmt.Errorf("block %#x not found", blockHash)
 	}
-	msg, vmctx, statedb, err := api.computeTxEnv(block, int(index), reexec)
+	vmctx, statedb, processor, err := api.computeTxEnv(block, int(index), reexec)
 	if err != nil {
 		return nil, err
 	}
 	// Trace the transaction and return
-	return api.traceTx(ctx, msg, vmctx, statedb, config)
+	return api.traceBlockTx(ctx, processor, block, tx, vmctx, statedb, config)
 }
 
 // TraceCall lets you trace a given eth_call. It collects the structured logs created during the execution of EVM
@@ -762,7 +792,7 @@ func (api *PrivateDebugAPI) TraceCall(ctx context.Context, args ethapi.CallArgs,
 		if config != nil && config.Reexec != nil {
 			reexec = *config.Reexec
 		}
-		_, _, statedb, err = api.computeTxEnv(block, 0, reexec)
+		_, statedb, _, err = api.computeTxEnv(block, 0, reexec)
 		if err != nil {
 			return nil, err
 		}
@@ -778,46 +808,79 @@ func (api *PrivateDebugAPI) TraceCall(ctx context.Context, args ethapi.CallArgs,
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
 func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
+	trace, _, err := api.traceMessage(ctx, message, vmctx, statedb, config)
+	return trace, err
+}
+
+// traceMessage is traceTx also returning the result of the execution.
+func (api *PrivateDebugAPI) traceMessage(ctx context.Context, message core.Message, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, *core.ExecutionResult, error) {
 	// Assemble the structured logger or the JavaScript tracer
-	var (
-		tracer    vm.Tracer
-		err       error
-		txContext = core.NewEVMTxContext(message)
-	)
+	tracer, cancel, err := api.newTracer(ctx, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cancel()
+
+	// Run the transaction with tracing enabled.
+	txContext := core.NewEVMTxContext(message)
+	vmenv := vm.NewEVM(vmctx, txContext, statedb, api.eth.blockchain.Config(), vm.Config{Debug: true, Tracer: tracer})
+
+	result, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("tracing failed: %v", err)
+	}
+	// Report the gas fee if it was paid by the VRC25 contract instead of the sender
+	if result.SponsoredFee != nil {
+		if tracer, ok := tracer.(tracers.VictionTracer); ok {
+			tracer.CaptureSponsoredFee(result.Payer, vmctx.Coinbase, result.SponsoredFee)
+		}
+	}
+	trace, err := formatTrace(tracer, result)
+	return trace, result, err
+}
+
+// newTracer configures a new tracer according to the provided configuration. The
+// returned cancel function must be called once tracing is done.
+func (api *PrivateDebugAPI) newTracer(ctx context.Context, config *TraceConfig) (vm.Tracer, context.CancelFunc, error) {
 	switch {
 	case config != nil && config.Tracer != nil:
 		// Define a meaningful timeout of a single transaction trace
 		timeout := defaultTraceTimeout
 		if config.Timeout != nil {
+			var err error
 			if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		}
-		// Constuct the JavaScript tracer to execute with
-		if tracer, err = tracers.New(*config.Tracer); err != nil {
-			return nil, err
+		// Construct the native tracer if there's one by that name, falling back
+		// to the JavaScript tracer to execute with
+		tracer, ok := tracers.NewNative(*config.Tracer)
+		if !ok {
+			js, err := tracers.New(*config.Tracer)
+			if err != nil {
+				return nil, nil, err
+			}
+			tracer = js
 		}
 		// Handle timeouts and RPC cancellations
 		deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
 		go func() {
 			<-deadlineCtx.Done()
-			tracer.(*tracers.Tracer).Stop(errors.New("execution timeout"))
+			tracer.Stop(errors.New("execution timeout"))
 		}()
-		defer cancel()
+		return tracer, cancel, nil
 
 	case config == nil:
-		tracer = vm.NewStructLogger(nil)
+		return vm.NewStructLogger(nil), func() {}, nil
 
 	default:
-		tracer = vm.NewStructLogger(config.LogConfig)
+		return vm.NewStructLogger(config.LogConfig), func() {}, nil
 	}
-	// Run the transaction with tracing enabled.
-	vmenv := vm.NewEVM(vmctx, txContext, statedb, api.eth.blockchain.Config(), vm.Config{Debug: true, Tracer: tracer})
+}
 
-	result, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()))
-	if err != nil {
-		return nil, fmt.Errorf("tracing failed: %v", err)
-	}
+// formatTrace assembles the output of a tracer which ran a message to the given
+// execution result.
+func formatTrace(tracer vm.Tracer, result *core.ExecutionResult) (interface{}, error) {
 	// Depending on the tracer type, format and return the output
 	switch tracer := tracer.(type) {
 	case *vm.StructLogger:
@@ -826,14 +889,20 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 		if len(result.Revert()) > 0 {
 			returnVal = fmt.Sprintf("%x", result.Revert())
 		}
-		return &ethapi.ExecutionResult{
+		res := &ethapi.ExecutionResult{
 			Gas:         result.UsedGas,
 			Failed:      result.Failed(),
 			ReturnValue: returnVal,
 			StructLogs:  ethapi.FormatLogs(tracer.StructLogs()),
-		}, nil
+		}
+		// If the gas was paid by the VRC25 contract, report who paid how much
+		if result.SponsoredFee != nil {
+			res.Payer = &result.Payer
+			res.SponsoredFee = (*hexutil.Big)(result.SponsoredFee)
+		}
+		return res, nil
 
-	case *tracers.Tracer:
+	case tracers.ResultTracer:
 		return tracer.GetResult()
 
 	default:
@@ -841,41 +910,39 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 	}
 }
 
-// computeTxEnv returns the execution environment of a certain transaction.
-func (api *PrivateDebugAPI) computeTxEnv(block *types.Block, txIndex int, reexec uint64) (core.Message, vm.BlockContext, *state.StateDB, error) {
+// computeTxEnv returns the execution environment of a certain transaction, along
+// with the processor to run its Viction hooks with.
+func (api *PrivateDebugAPI) computeTxEnv(block *types.Block, txIndex int, reexec uint64) (vm.BlockContext, *state.StateDB, *core.StateProcessor, error) {
 	// Create the parent state database
 	parent := api.eth.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1)
 	if parent == nil {
-		return nil, vm.BlockContext{}, nil, fmt.Errorf("parent %#x not found", block.ParentHash())
+		return vm.BlockContext{}, nil, nil, fmt.Errorf("parent %#x not found", block.ParentHash())
 	}
 	statedb, err := api.computeStateDB(parent, reexec)
 	if err != nil {
-		return nil, vm.BlockContext{}, nil, err
+		return vm.BlockContext{}, nil, nil, err
 	}
-
+	processor, err := api.prepareReplay(block, statedb)
+	if err != nil {
+		return vm.BlockContext{}, nil, nil, err
+	}
+	context := core.NewEVMBlockContext(block.Header(), api.eth.blockchain, nil)
 	if txIndex == 0 && len(block.Transactions()) == 0 {
-		return nil, vm.BlockContext{}, statedb, nil
+		return context, statedb, processor, nil
 	}
-
 	// Recompute transactions up to the target index.
-	signer := types.MakeSigner(api.eth.blockchain.Config(), block.Number())
-
 	for idx, tx := range block.Transactions() {
-		// Assemble the transaction call message and return if the requested offset
-		msg, _ := tx.AsMessage(signer)
-		txContext := core.NewEVMTxContext(msg)
-		context := core.NewEVMBlockContext(block.Header(), api.eth.blockchain, nil)
+		// Return if the requested offset was reached
 		if idx == txIndex {
-			return msg, context, statedb, nil
+			return context, statedb, processor, nil
 		}
 		// Not yet the searched for transaction, execute on top of the current state
-		vmenv := vm.NewEVM(context, txContext, statedb, api.eth.blockchain.Config(), vm.Config{})
-		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(tx.Gas())); err != nil {
-			return nil, vm.BlockContext{}, nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
+		if err := api.replayTx(processor, block, tx, context, statedb); err != nil {
+			return vm.BlockContext{}, nil, nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
 		}
 		// Ensure any modifications are committed to the state
 		// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
-		statedb.Finalise(vmenv.ChainConfig().IsEIP158(block.Number()))
+		statedb.Finalise(api.eth.blockchain.Config().IsEIP158(block.Number()))
 	}
-	return nil, vm.BlockContext{}, nil, fmt.Errorf("transaction index %d out of range for block %#x", txIndex, block.Hash())
+	return vm.BlockContext{}, nil, nil, fmt.Errorf("transaction index %d out of range for block %#x", txIndex, block.Hash())
 }
@@ -0,0 +1,252 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/consensus/posv"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Reasons of the balance changes applied outside of the transactions of a block.
+const (
+	rewardEpoch      = "epochReward"     // Epoch reward of a validator's stakeholder
+	rewardFoundation = "foundationShare" // Foundation's share of the epoch rewards
+	rewardSaigonFund = "saigonFund"      // Ecosystem fund minted after the Saigon hard fork
+	rewardBypass     = "bypassBalance"   // Balance given to a legacy sender ahead of its transaction
+)
+
+// blockReward is a balance change applied to an account outside of the
+// transactions of a block.
+type blockReward struct {
+	Address common.Address `json:"address"`
+	Amount  *hexutil.Big   `json:"amount"` // Balance difference, negative if lowered
+	Reason  string         `json:"reason"`
+	TxHash  *common.Hash   `json:"txHash,omitempty"` // Transaction the change was applied ahead of
+}
+
+// prepareReplay readies the parent state of a block for replaying its transactions
+// the way the StateProcessor does, returning the processor to run the Viction hooks
+// of the individual transactions with. A fresh processor is used so the tracers
+// don't interfere with the one importing blocks.
+func (api *PrivateDebugAPI) prepareReplay(block *types.Block, statedb *state.StateDB) (*core.StateProcessor, error) {
+	config := api.eth.blockchain.Config()
+
+	processor := core.NewStateProcessor(config, api.eth.blockchain, api.eth.engine)
+	if err := processor.PrepareBlock(block, statedb); err != nil {
+		return nil, err
+	}
+	misc.ApplyTransitions(config, statedb, block.Number())
+	return processor, nil
+}
+
+// replayTx applies a transaction of the block on top of the given state without
+// tracing it, running the same Viction hooks as the StateProcessor before and
+// after it.
+func (api *PrivateDebugAPI) replayTx(processor *core.StateProcessor, block *types.Block, tx *types.Transaction, vmctx vm.BlockContext, statedb *state.StateDB) error {
+	msg, _ := tx.AsMessage(types.MakeSigner(api.eth.blockchain.Config(), block.Number()))
+	if err := processor.PrepareTransaction(block, tx, msg, statedb); err != nil {
+		return err
+	}
+	handled, receipt, err := processor.ApplyVictionTransaction(statedb, tx, block.Header(), new(uint64))
+	if err != nil {
+		return err
+	}
+	if handled {
+		return processor.FinishTransaction(tx, msg, statedb, receipt.Status == types.ReceiptStatusFailed, receipt.GasUsed)
+	}
+	vmenv := vm.NewEVM(vmctx, core.NewEVMTxContext(msg), statedb, api.eth.blockchain.Config(), vm.Config{})
+	result, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas()))
+	if err != nil {
+		return err
+	}
+	return processor.FinishTransaction(tx, msg, statedb, result.Failed(), result.UsedGas)
+}
+
+// traceBlockTx traces a transaction of the block on top of a state prepared for
+// it by the processor. The Viction special transactions applied without EVM
+// execution are reported to the tracer as a synthetic frame, so the trace agrees
+// with the transaction's receipt.
+func (api *PrivateDebugAPI) traceBlockTx(ctx context.Context, processor *core.StateProcessor, block *types.Block, tx *types.Transaction, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
+	msg, _ := tx.AsMessage(types.MakeSigner(api.eth.blockchain.Config(), block.Number()))
+	if err := processor.PrepareTransaction(block, tx, msg, statedb); err != nil {
+		return nil, err
+	}
+	handled, receipt, err := processor.ApplyVictionTransaction(statedb, tx, block.Header(), new(uint64))
+	if err != nil {
+		return nil, err
+	}
+	if !handled {
+		trace, result, err := api.traceMessage(ctx, msg, vmctx, statedb, config)
+		if err != nil {
+			return nil, err
+		}
+		return trace, processor.FinishTransaction(tx, msg, statedb, result.Failed(), result.UsedGas)
+	}
+	tracer, cancel, err := api.newTracer(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	typ := tracers.SignFrame
+	if core.IsTomoXTransaction(tx) {
+		typ = tracers.TomoXFrame
+	}
+	if vtracer, ok := tracer.(tracers.VictionTracer); ok {
+		vtracer.CaptureSystemTx(statedb, typ, msg.From(), *tx.To(), tx.Data(), tx.Gas())
+	} else {
+		// Tracers unaware of Viction see the transaction as a call without code
+		tracer.CaptureStart(msg.From(), *tx.To(), false, tx.Data(), tx.Gas(), new(big.Int))
+		tracer.CaptureEnd(nil, 0, 0, nil)
+	}
+	if err := processor.FinishTransaction(tx, msg, statedb, receipt.Status == types.ReceiptStatusFailed, receipt.GasUsed); err != nil {
+		return nil, err
+	}
+	return formatTrace(tracer, &core.ExecutionResult{Payer: msg.From()})
+}
+
+// TraceBlockRewards returns the balance changes applied by a block outside of its
+// transactions, in the order they were applied: the Saigon fund, the bypass
+// balances of legacy senders and the epoch rewards of the stakeholders.
+func (api *PrivateDebugAPI) TraceBlockRewards(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, config *TraceConfig) ([]*blockReward, error) {
+	block, err := api.eth.APIBackend.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %v not found", blockNrOrHash)
+	}
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	parent := api.eth.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent %#x not found", block.ParentHash())
+	}
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	statedb, err := api.computeStateDB(parent, reexec)
+	if err != nil {
+		return nil, err
+	}
+	// Replay the block the way the StateProcessor does, collecting the balance
+	// changes of the irregular state transitions and the Viction hooks
+	var (
+		chainConfig = api.eth.blockchain.Config()
+		vicConfig   = chainConfig.Viction
+		rewards     []*blockReward
+	)
+	processor := core.NewStateProcessor(chainConfig, api.eth.blockchain, api.eth.engine)
+	if err := processor.PrepareBlock(block, statedb); err != nil {
+		return nil, err
+	}
+	for _, name := range misc.ApplyTransitions(chainConfig, statedb, block.Number()) {
+		if name == "saigon-fund" {
+			rewards = append(rewards, &blockReward{
+				Address: vicConfig.SaigonFundAddress,
+				Amount:  (*hexutil.Big)(new(big.Int).Set((*big.Int)(vicConfig.SaigonFundAmount))),
+				Reason:  rewardSaigonFund,
+			})
+		}
+	}
+	var (
+		signer    = types.MakeSigner(chainConfig, block.Number())
+		blacklist = core.NewBlacklistProvider(chainConfig)
+		vmctx     = core.NewEVMBlockContext(block.Header(), api.eth.blockchain, nil)
+	)
+	for _, tx := range block.Transactions() {
+		msg, _ := tx.AsMessage(signer)
+		if val := blacklist.BypassBalance(block.Number(), msg.From()); val != nil {
+			if diff := new(big.Int).Sub(val, statedb.GetBalance(msg.From())); diff.Sign() != 0 {
+				hash := tx.Hash()
+				rewards = append(rewards, &blockReward{
+					Address: msg.From(),
+					Amount:  (*hexutil.Big)(diff),
+					Reason:  rewardBypass,
+					TxHash:  &hash,
+				})
+			}
+		}
+		if err := api.replayTx(processor, block, tx, vmctx, statedb); err != nil {
+			return nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
+		}
+		statedb.Finalise(chainConfig.IsEIP158(block.Number()))
+	}
+	// Epoch rewards are distributed at the checkpoints, skipping the first one
+	engine, ok := api.eth.engine.(*posv.Posv)
+	if !ok || chainConfig.Posv == nil || vicConfig == nil {
+		return rewards, nil
+	}
+	number, epoch := block.NumberU64(), chainConfig.Posv.Epoch
+	if epoch == 0 || number%epoch != 0 || number <= epoch {
+		return rewards, nil
+	}
+	reward := posv.ReadEpochReward(api.eth.ChainDb(), block.Hash(), number)
+	if reward == nil {
+		// Rewards are not stored for blocks imported before they were persisted,
+		// compute them on the replayed state same as Finalize does
+		reward, err = engine.CalcEpochReward(api.eth.blockchain, block.Header(), statedb)
+		if err != nil && !errors.Is(err, posv.ErrRewardInvariant) {
+			return nil, err
+		}
+	}
+	return append(rewards, epochRewardChanges(vicConfig.RewardFoundationAddress, reward)...), nil
+}
+
+// epochRewardChanges lists the balance changes of the stakeholders credited with
+// the given epoch reward, ordered by address.
+func epochRewardChanges(foundation common.Address, reward *posv.EpochReward) []*blockReward {
+	if reward == nil {
+		return nil
+	}
+	var changes []*blockReward
+	for addr, amount := range reward.StakholderRewards {
+		if amount == nil || amount.Sign() <= 0 {
+			continue
+		}
+		reason := rewardEpoch
+		if addr == foundation {
+			reason = rewardFoundation
+		}
+		changes = append(changes, &blockReward{
+			Address: addr,
+			Amount:  (*hexutil.Big)(new(big.Int).Set(amount)),
+			Reason:  reason,
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return bytes.Compare(changes[i].Address[:], changes[j].Address[:]) < 0
+	})
+	return changes
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/posv"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Tests that the epoch rewards are listed by address, with the foundation's share
+// told apart from the rewards of the stakeholders.
+func TestEpochRewardChanges(t *testing.T) {
+	var (
+		foundation = common.HexToAddress("0x0000000000000000000000000000000000000068")
+		holder1    = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		holder2    = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	)
+	if changes := epochRewardChanges(foundation, nil); len(changes) != 0 {
+		t.Fatalf("changes without rewards: have %d, want 0", len(changes))
+	}
+	reward := &posv.EpochReward{
+		StakholderRewards: map[common.Address]*big.Int{
+			holder2:    big.NewInt(300),
+			foundation: big.NewInt(100),
+			holder1:    big.NewInt(200),
+		},
+	}
+	changes := epochRewardChanges(foundation, reward)
+
+	want := []struct {
+		addr   common.Address
+		amount int64
+		reason string
+	}{
+		{foundation, 100, rewardFoundation},
+		{holder1, 200, rewardEpoch},
+		{holder2, 300, rewardEpoch},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("change count mismatch: have %d, want %d", len(changes), len(want))
+	}
+	for i, change := range changes {
+		if change.Address != want[i].addr || change.Amount.ToInt().Int64() != want[i].amount || change.Reason != want[i].reason || change.TxHash != nil {
+			t.Errorf("change %d mismatch: have %x %v %s, want %x %d %s", i, change.Address, change.Amount, change.Reason, want[i].addr, want[i].amount, want[i].reason)
+		}
+	}
+}
+
+// rewardBackend is a PoSV backend computing a fixed epoch reward.
+type rewardBackend struct {
+	posv.PosvBackend
+	reward *posv.EpochReward
+}
+
+func (b rewardBackend) PosvGetEpochReward(c *posv.Posv, config *params.ChainConfig, posvConfig *params.PosvConfig, vicConfig *params.VictionConfig,
+	header *types.Header, chain consensus.ChainReader, state *state.StateDB, logger log.Logger) (*posv.EpochReward, error) {
+	return b.reward, nil
+}
+
+// Tests that replaying a checkpoint block lists the Saigon fund, the balance given
+// to a bypassed sender and the epoch rewards, computing the rewards if they were
+// not stored.
+func TestTraceBlockRewards(t *testing.T) {
+	var (
+		key, _     = crypto.GenerateKey()
+		sender     = crypto.PubkeyToAddress(key.PublicKey)
+		fund       = common.HexToAddress("0x0000000000000000000000000000000000000099")
+		foundation = common.HexToAddress("0x0000000000000000000000000000000000000068")
+		holder     = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		bypass     = new(big.Int).Mul(big.NewInt(5), big.NewInt(params.Ether))
+		db         = rawdb.NewMemoryDatabase()
+	)
+	config := *params.AllEthashProtocolChanges
+	config.Posv = &params.PosvConfig{Period: 2, Epoch: 2, Gap: 1}
+	config.SaigonBlock = big.NewInt(4)
+	config.Viction = &params.VictionConfig{
+		VRC25GasPrice:           (*math.Decimal256)(big.NewInt(2500)),
+		RewardFoundationAddress: foundation,
+		SaigonFundAddress:       fund,
+		SaigonFundAmount:        (*math.Decimal256)(big.NewInt(1000)),
+		SaigonFundInterval:      100,
+		SaigonFundRepeat:        1,
+		BypassBalances:          []params.BypassBalanceEntry{{Address: sender, Block: 4, Balance: (*math.Decimal256)(bypass)}},
+	}
+	gspec := &core.Genesis{
+		Config: &config,
+		Alloc:  core.GenesisAlloc{sender: {Balance: big.NewInt(params.Ether)}},
+	}
+	genesis := gspec.MustCommit(db)
+	signer := types.NewEIP155Signer(config.ChainID)
+
+	blocks, _ := core.GenerateChain(&config, genesis, ethash.NewFaker(), db, 4, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(sender), common.Address{0x01}, big.NewInt(1), params.TxGas, big.NewInt(1), nil), signer, key)
+		b.AddTx(tx)
+	})
+	chain, err := core.NewBlockChain(db, nil, &config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks[:3]); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	// The generator doesn't give the bypassed balance, so the checkpoint is stored
+	// without being imported
+	checkpoint := blocks[3]
+	rawdb.WriteBlock(db, checkpoint)
+
+	engine := posv.New(config.Posv, db)
+	engine.SetBackend(rewardBackend{reward: &posv.EpochReward{
+		StakholderRewards: map[common.Address]*big.Int{foundation: big.NewInt(100), holder: big.NewInt(200)},
+	}})
+	eth := &Ethereum{blockchain: chain, engine: engine, chainDb: db}
+	eth.APIBackend = &EthAPIBackend{eth: eth}
+	api := NewPrivateDebugAPI(eth)
+
+	parent, _ := chain.StateAt(blocks[2].Root())
+	txHash := checkpoint.Transactions()[0].Hash()
+
+	type change struct {
+		addr   common.Address
+		amount *big.Int
+		reason string
+		tx     *common.Hash
+	}
+	check := func(want []change) {
+		t.Helper()
+		rewards, err := api.TraceBlockRewards(context.Background(), rpc.BlockNumberOrHashWithHash(checkpoint.Hash(), false), nil)
+		if err != nil {
+			t.Fatalf("failed to trace block rewards: %v", err)
+		}
+		if len(rewards) != len(want) {
+			t.Fatalf("reward count mismatch: have %d, want %d", len(rewards), len(want))
+		}
+		for i, reward := range rewards {
+			if reward.Address != want[i].addr || reward.Amount.ToInt().Cmp(want[i].amount) != 0 || reward.Reason != want[i].reason || (reward.TxHash == nil) != (want[i].tx == nil) || (reward.TxHash != nil && *reward.TxHash != *want[i].tx) {
+				t.Errorf("reward %d mismatch: have %x %v %s, want %x %v %s", i, reward.Address, reward.Amount, reward.Reason, want[i].addr, want[i].amount, want[i].reason)
+			}
+		}
+	}
+	// Rewards not stored are computed on the replayed state
+	base := []change{
+		{fund, big.NewInt(1000), rewardSaigonFund, nil},
+		{sender, new(big.Int).Sub(bypass, parent.GetBalance(sender)), rewardBypass, &txHash},
+	}
+	check(append(base,
+		change{foundation, big.NewInt(100), rewardFoundation, nil},
+		change{holder, big.NewInt(200), rewardEpoch, nil},
+	))
+	// Stored rewards are listed as they were distributed
+	posv.WriteEpochReward(db, checkpoint.Hash(), checkpoint.NumberU64(), &posv.EpochReward{
+		StakholderRewards: map[common.Address]*big.Int{holder: big.NewInt(300)},
+	})
+	check(append(base, change{holder, big.NewInt(300), rewardEpoch, nil}))
+}
@@ -35,11 +35,13 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
+	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
@@ -78,6 +80,7 @@ type Ethereum struct {
 	APIBackend *EthAPIBackend
 
 	miner     *miner.Miner
+	signer    *blockSigner // Emits block sign transactions on PoSV chains
 	gasPrice  *big.Int
 	etherbase common.Address
 
@@ -125,6 +128,10 @@ func New(stack *node.Node, config *Config) (*Ethereum, error) {
 	}
 	log.Info("Initialised chain configuration", "config", chainConfig)
 
+	if err := pruner.RecoverPruning(stack.ResolvePath(""), chainDb, stack.ResolvePath(config.TrieCleanCacheJournal)); err != nil {
+		log.Error("Failed to recover state", "error", err)
+	}
+
 	eth := &Ethereum{
 		config:            config,
 		chainDb:           chainDb,
@@ -145,6 +152,7 @@ func New(stack *node.Node, config *Config) (*Ethereum, error) {
 		if posvEngine, ok := eth.engine.(*posv.Posv); ok {
 			// Set Ethereum instance as PosvBackend (implements PosvGetEpochReward)
 			posvEngine.SetBackend(eth)
+			posvEngine.SetStrictRewards(config.PosvStrictRewards)
 			log.Info("PosvBackend set on Posv engine")
 		} else {
 			log.Warn("Posv config present but engine is not Posv type", "engineType", fmt.Sprintf("%T", eth.engine))
@@ -210,9 +218,19 @@ func New(stack *node.Node, config *Config) (*Ethereum, error) {
 	if eth.protocolManager, err = NewProtocolManager(chainConfig, checkpoint, config.SyncMode, config.NetworkId, eth.eventMux, eth.txPool, eth.engine, eth.blockchain, chainDb, cacheLimit, config.Whitelist); err != nil {
 		return nil, err
 	}
+	if posvEngine, ok := eth.engine.(*posv.Posv); ok {
+		// Attest the propagated blocks the local validator is paired with
+		eth.protocolManager.blockFetcher.SetBlockAttester(func(block *types.Block) (*types.Block, error) {
+			return posvEngine.Attest(eth.blockchain, block)
+		})
+	}
 	eth.miner = miner.New(eth, &config.Miner, chainConfig, eth.EventMux(), eth.engine, eth.isLocalBlock)
 	eth.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
 
+	if chainConfig.Posv != nil && chainConfig.Viction != nil && chainConfig.Viction.ValidatorSignInterval > 0 {
+		eth.signer = newBlockSigner(eth)
+	}
+
 	eth.APIBackend = &EthAPIBackend{stack.Config().ExtRPCEnabled(), eth, nil}
 	gpoParams := config.GPO
 	if gpoParams.Default == nil {
@@ -473,6 +491,14 @@ func (s *Ethereum) StartMining(threads int) error {
 			}
 			clique.Authorize(eb, wallet.SignData)
 		}
+		if posv, ok := s.engine.(*posv.Posv); ok {
+			wallet, err := s.accountManager.Find(accounts.Account{Address: eb})
+			if wallet == nil || err != nil {
+				log.Error("Etherbase account unavailable locally", "err", err)
+				return fmt.Errorf("signer missing: %v", err)
+			}
+			posv.Authorize(eb, wallet.SignData)
+		}
 		// If mining is started, we can disable the transaction rejection mechanism
 		// introduced to speed sync times.
 		atomic.StoreUint32(&s.protocolManager.acceptTxs, 1)
@@ -522,6 +548,9 @@ func (s *Ethereum) Protocols() []p2p.Protocol {
 		protos[i].Attributes = []enr.Entry{s.currentEthEntry()}
 		protos[i].DialCandidates = s.dialCandidates
 	}
+	if s.config.SnapshotCache > 0 {
+		protos = append(protos, snap.MakeProtocols((*snapHandler)(s.protocolManager))...)
+	}
 	return protos
 }
 
@@ -543,6 +572,11 @@ func (s *Ethereum) Start() error {
 	}
 	// Start the networking layer and the light server if requested
 	s.protocolManager.Start(maxPeers)
+
+	// Start emitting block sign transactions if running a PoSV chain
+	if s.signer != nil {
+		s.signer.start()
+	}
 	return nil
 }
 
@@ -555,6 +589,9 @@ func (s *Ethereum) Stop() error {
 	// Then stop everything else.
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
+	if s.signer != nil {
+		s.signer.stop()
+	}
 	s.txPool.Stop()
 	s.miner.Stop()
 	s.blockchain.Stop()
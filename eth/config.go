@@ -157,6 +157,9 @@ type Config struct {
 	// Ethash options
 	Ethash ethash.Config
 
+	// PoSV options
+	PosvStrictRewards bool `toml:",omitempty"` // Whether to reject the checkpoint blocks crediting more rewards than the epoch reward
+
 	// Transaction pool options
 	TxPool core.TxPoolConfig
 
@@ -82,6 +82,10 @@ type bodyRequesterFn func([]common.Hash) error
 // headerVerifierFn is a callback type to verify a block's header for fast propagation.
 type headerVerifierFn func(header *types.Header) error
 
+// blockAttesterFn is a callback type to sign a double validated block the local
+// node is the attestor of, returning the block as it is otherwise.
+type blockAttesterFn func(block *types.Block) (*types.Block, error)
+
 // blockBroadcasterFn is a callback type for broadcasting a block to connected peers.
 type blockBroadcasterFn func(block *types.Block, propagate bool)
 
@@ -187,6 +191,7 @@ type BlockFetcher struct {
 	insertHeaders  headersInsertFn    // Injects a batch of headers into the chain
 	insertChain    chainInsertFn      // Injects a batch of blocks into the chain
 	dropPeer       peerDropFn         // Drops a peer for misbehaving
+	attestBlock    blockAttesterFn    // Attests a propagated block before verifying it (optional)
 
 	// Testing hooks
 	announceChangeHook func(common.Hash, bool)           // Method to call upon adding or deleting a hash from the blockAnnounce list
@@ -225,6 +230,12 @@ func NewBlockFetcher(light bool, getHeader HeaderRetrievalFn, getBlock blockRetr
 	}
 }
 
+// SetBlockAttester sets the callback attesting the propagated blocks before they
+// are verified, so the blocks the local node attests get propagated attested.
+func (f *BlockFetcher) SetBlockAttester(attestBlock blockAttesterFn) {
+	f.attestBlock = attestBlock
+}
+
 // Start boots up the announcement based synchroniser, accepting and processing
 // hash notifications and block fetches until termination requested.
 func (f *BlockFetcher) Start() {
@@ -793,6 +804,15 @@ func (f *BlockFetcher) importBlocks(peer string, block *types.Block) {
 			log.Debug("Unknown parent of propagated block", "peer", peer, "number", block.Number(), "hash", hash, "parent", block.ParentHash())
 			return
 		}
+		// Sign the block if the local node is its attestor
+		if f.attestBlock != nil {
+			attested, err := f.attestBlock(block)
+			if err != nil {
+				log.Debug("Propagated block attestation failed", "peer", peer, "number", block.Number(), "hash", hash, "err", err)
+				return
+			}
+			block = attested
+		}
 		// Quickly validate the header and propagate the block if it passes
 		switch err := f.verifyHeader(block.Header()); err {
 		case nil:
@@ -803,6 +823,11 @@ func (f *BlockFetcher) importBlocks(peer string, block *types.Block) {
 		case consensus.ErrFutureBlock:
 			// Weird future block, don't fail, but neither propagate
 
+		case consensus.ErrUnattestedBlock:
+			// Block waiting for its attestor, the attested one will be propagated
+			log.Debug("Propagated block not attested yet", "peer", peer, "number", block.Number(), "hash", hash)
+			return
+
 		default:
 			// Something went very wrong, drop the peer
 			log.Debug("Propagated block verification failed", "peer", peer, "number", block.Number(), "hash", hash, "err", err)
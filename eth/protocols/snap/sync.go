@@ -0,0 +1,1907 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	// emptyRoot is the known root hash of an empty trie.
+	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+	// emptyCode is the known hash of the empty EVM bytecode.
+	emptyCode = crypto.Keccak256Hash(nil)
+)
+
+const (
+	// maxRequestSize is the maximum number of bytes to request from a remote peer.
+	maxRequestSize = 512 * 1024
+
+	// maxStorageSetRequestCount is the maximum number of contracts to request the
+	// storage of in a single query. If this number is too low, we're not filling
+	// responses fully and waste round trip times. If it's too high, we're capping
+	// responses and waste bandwidth.
+	maxStorageSetRequestCount = maxRequestSize / 1024
+
+	// maxCodeRequestCount is the maximum number of bytecode blobs to request in a
+	// single query. If this number is too low, we're not filling responses fully
+	// and waste round trip times. If it's too high, we're capping responses and
+	// waste bandwidth.
+	//
+	// Depoyed bytecodes are currently capped at 24KB, so the minimum request
+	// size should be maxRequestSize / 24K. Assuming that most contracts do not
+	// come close to that, requesting 4x should be a good approximation.
+	maxCodeRequestCount = maxRequestSize / (24 * 1024) * 4
+
+	// maxTrieRequestCount is the maximum number of trie node blobs to request in
+	// a single query. If this number is too low, we're not filling responses fully
+	// and waste round trip times. If it's too high, we're capping responses and
+	// waste bandwidth.
+	maxTrieRequestCount = 512
+
+	// requestTimeout is the maximum time a peer is allowed to spend on serving
+	// a single network request.
+	requestTimeout = 10 * time.Second
+
+	// accountConcurrency is the number of chunks to split the account trie into
+	// to allow concurrent retrievals.
+	accountConcurrency = 16
+)
+
+// errCancelled is returned from snap syncing if the operation was prematurely
+// terminated.
+var errCancelled = errors.New("sync cancelled")
+
+// accountRequest tracks a pending account range request to ensure responses are
+// to actual requests and to validate any security constraints.
+//
+// Concurrency note: account requests and responses are handled concurrently from
+// the main runloop to allow Merkle proof verifications on the peer's thread and
+// to drop on invalid response. The request struct must contain all the data to
+// construct the response without accessing runloop internals (i.e. task). That
+// is only included to allow the runloop to match a response to the task being
+// synced without having yet another set of maps.
+type accountRequest struct {
+	peer string // Peer to which this request is assigned
+	id   uint64 // Request ID of this request
+
+	stale   chan struct{} // Channel to signal the sync cycle ended with this request in flight
+	timeout *time.Timer   // Timer to track delivery timeout
+
+	origin common.Hash  // First account requested to allow continuation checks
+	task   *accountTask // Task which this request is filling (only access fields through the runloop!!)
+}
+
+// accountResponse is an already Merkle-verified remote response to an account
+// range request. It contains the subtrie for the requested account range and
+// the database that's going to be filled with the internal nodes on commit.
+type accountResponse struct {
+	task *accountTask // Task which this request is filling
+
+	hashes   []common.Hash    // Account hashes in the returned range
+	accounts []*state.Account // Expanded accounts in the returned range
+
+	cont bool // Whether the account range has a continuation
+}
+
+// bytecodeRequest tracks a pending bytecode request to ensure responses are to
+// actual requests and to validate any security constraints.
+//
+// Concurrency note: bytecode requests and responses are handled concurrently from
+// the main runloop to allow Keccak256 hash verifications on the peer's thread and
+// to drop on invalid response. The request struct must contain all the data to
+// construct the response without accessing runloop internals (i.e. task). That
+// is only included to allow the runloop to match a response to the task being
+// synced without having yet another set of maps.
+type bytecodeRequest struct {
+	peer string // Peer to which this request is assigned
+	id   uint64 // Request ID of this request
+
+	stale   chan struct{} // Channel to signal the sync cycle ended with this request in flight
+	timeout *time.Timer   // Timer to track delivery timeout
+
+	hashes []common.Hash // Bytecode hashes to validate responses
+	task   *accountTask  // Task which this request is filling, nil if healing
+}
+
+// bytecodeResponse is an already verified remote response to a bytecode request.
+type bytecodeResponse struct {
+	req   *bytecodeRequest // Request this response is filling
+	codes [][]byte         // Actual bytecodes to store into the database (nil = missing)
+}
+
+// storageRequest tracks a pending storage ranges request to ensure responses are
+// to actual requests and to validate any security constraints.
+//
+// Concurrency note: storage requests and responses are handled concurrently from
+// the main runloop to allow Merkle proof verifications on the peer's thread and
+// to drop on invalid response. The request struct must contain all the data to
+// construct the response without accessing runloop internals (i.e. tasks). That
+// is only included to allow the runloop to match a response to the task being
+// synced without having yet another set of maps.
+type storageRequest struct {
+	peer string // Peer to which this request is assigned
+	id   uint64 // Request ID of this request
+
+	stale   chan struct{} // Channel to signal the sync cycle ended with this request in flight
+	timeout *time.Timer   // Timer to track delivery timeout
+
+	accounts []common.Hash // Account hashes to validate responses
+	roots    []common.Hash // Storage roots to validate responses
+
+	origin common.Hash // First storage slot requested to allow continuation checks
+
+	mainTask *accountTask // Task which this response belongs to (only access fields through the runloop!!)
+	subTask  *storageTask // Task which this response is filling (only access fields through the runloop!!)
+}
+
+// storageResponse is an already Merkle-verified remote response to a storage
+// range request. It contains the slots of the requested storage ranges, the
+// last one possibly being incomplete.
+type storageResponse struct {
+	req *storageRequest // Request this response is filling
+
+	hashes [][]common.Hash // Storage slot hashes in the returned range
+	slots  [][][]byte      // Storage slot values in the returned range
+
+	cont bool // Whether the last storage range has a continuation
+}
+
+// trienodeHealRequest tracks a pending state trie request to ensure responses
+// are to actual requests and to validate any security constraints.
+type trienodeHealRequest struct {
+	peer string // Peer to which this request is assigned
+	id   uint64 // Request ID of this request
+
+	stale   chan struct{} // Channel to signal the sync cycle ended with this request in flight
+	timeout *time.Timer   // Timer to track delivery timeout
+
+	hashes []common.Hash   // Trie node hashes to validate responses
+	paths  []trie.SyncPath // Trie node paths requested for rescheduling
+}
+
+// trienodeHealResponse is an already verified remote response to a trie node
+// request.
+type trienodeHealResponse struct {
+	req   *trienodeHealRequest // Request this response is filling
+	nodes [][]byte             // Actual trie nodes to store into the database (nil = missing)
+}
+
+// accountTask represents the sync task for a chunk of the account snapshot.
+type accountTask struct {
+	// These fields get serialized to leveldb on shutdown
+	Next common.Hash // Next account to sync in this interval
+	Last common.Hash // Last account to sync in this interval
+
+	// These fields are internals used during runtime
+	req  *accountRequest  // Pending request to fill this task
+	res  *accountResponse // Validate response filling this task
+	pend int              // Number of pending subtasks for this round
+
+	needCode  []bool // Flags whether the filling accounts need code retrieval
+	needState []bool // Flags whether the filling accounts need storage retrieval
+	needHeal  []bool // Flags whether the filling accounts's state was chunked and need healing
+
+	codeTasks  map[common.Hash]struct{}     // Code hashes that need retrieval
+	stateTasks map[common.Hash]common.Hash  // Account hashes->roots that need full state retrieval
+	subTasks   map[common.Hash]*storageTask // Account hashes->large storage tasks in progress
+
+	genTrie *trie.StackTrie // Generator of the account trie nodes of the chunk
+
+	done bool // Flag whether the task can be removed
+}
+
+// storageTask represents the sync task for a large storage trie, retrieved
+// chunk by chunk in ascending order.
+type storageTask struct {
+	Next common.Hash // Next storage slot to sync in this storage trie
+	root common.Hash // Storage root hash for this instance
+
+	req     *storageRequest // Pending request to fill this task
+	genTrie *trie.StackTrie // Generator of the storage trie nodes
+}
+
+// syncProgress is a database entry to allow suspending and resuming a snapshot
+// state sync. Opposed to full and fast sync, there is no way to restart a
+// suspended snap sync without prior knowledge of the suspension point.
+type syncProgress struct {
+	Tasks []*accountTask // The suspended account tasks
+
+	// Status report during syncing phase
+	AccountSynced  uint64             // Number of accounts downloaded
+	AccountBytes   common.StorageSize // Number of account trie bytes persisted to disk
+	BytecodeSynced uint64             // Number of bytecodes downloaded
+	BytecodeBytes  common.StorageSize // Number of bytecode bytes downloaded
+	StorageSynced  uint64             // Number of storage slots downloaded
+	StorageBytes   common.StorageSize // Number of storage trie bytes persisted to disk
+
+	// Status report during healing phase
+	TrienodeHealSynced uint64             // Number of state trie nodes downloaded
+	TrienodeHealBytes  common.StorageSize // Number of state trie bytes persisted to disk
+	BytecodeHealSynced uint64             // Number of bytecodes downloaded
+	BytecodeHealBytes  common.StorageSize // Number of bytecodes persisted to disk
+}
+
+// SyncPeer abstracts out the methods required for a peer to be synced against
+// with the goal of allowing the construction of mock peers without the full
+// blown networking.
+type SyncPeer interface {
+	// ID retrieves the peer's unique identifier.
+	ID() string
+
+	// RequestAccountRange fetches a batch of accounts rooted in a specific account
+	// trie, starting with the origin.
+	RequestAccountRange(id uint64, root, origin, limit common.Hash, bytes uint64) error
+
+	// RequestStorageRanges fetches a batch of storage slots belonging to one or
+	// more accounts. If slots from only one account is requested, an origin marker
+	// may also be used to retrieve from there.
+	RequestStorageRanges(id uint64, root common.Hash, accounts []common.Hash, origin, limit []byte, bytes uint64) error
+
+	// RequestByteCodes fetches a batch of bytecodes by hash.
+	RequestByteCodes(id uint64, hashes []common.Hash, bytes uint64) error
+
+	// RequestTrieNodes fetches a batch of account or storage trie nodes rooted in
+	// a specific state trie.
+	RequestTrieNodes(id uint64, root common.Hash, paths []TrieNodePathSet, bytes uint64) error
+
+	// Log retrieves the peer's own contextual logger.
+	Log() log.Logger
+}
+
+// Syncer is an Ethereum account and storage trie syncer based on snapshots and
+// the snap protocol. It's purpose is to download all the accounts and storage
+// slots from remote peers and reassemble chunks of the state trie, on top of
+// which a state sync can be run to fix any gaps / overlaps.
+//
+// Every network request has a variety of failure events:
+//   - The peer disconnects after task assignment, failing to send the request
+//   - The peer disconnects after sending the request, before delivering on it
+//   - The peer remains connected, but does not deliver a response in time
+//   - The peer delivers a stale response after a previous timeout
+//   - The peer delivers a refusal to serve the requested state
+type Syncer struct {
+	db    ethdb.KeyValueStore // Database to store the trie nodes into (and dedup)
+	bloom *trie.SyncBloom     // Bloom filter to deduplicate nodes for state fixup
+
+	batch  ethdb.Batch  // Batch accumulating the data retrieved for the database
+	writer *bloomWriter // Writer of the generated trie nodes into the batch
+
+	root    common.Hash    // Current state trie root being synced
+	tasks   []*accountTask // Current account task set being synced
+	healer  *trie.Sync     // State trie sync scheduler for fixing gaps / overlaps
+	healing bool           // Flag whether the account tasks are done and healing started
+
+	healNodes map[common.Hash]trie.SyncPath // Missing trie nodes not yet assigned for retrieval
+	healCodes map[common.Hash]struct{}      // Missing bytecodes not yet assigned for retrieval
+
+	update  chan struct{}       // Notification channel for possible sync progression
+	peers   map[string]SyncPeer // Currently active peers to download from
+	idlers  map[string]struct{} // Peers that aren't serving requests
+	stale   chan struct{}       // Channel closed when the current sync cycle ends
+	nostate map[string]struct{} // Peers that failed to deliver state data for the root
+
+	accountReqs  map[uint64]*accountRequest      // Account requests currently running
+	bytecodeReqs map[uint64]*bytecodeRequest     // Bytecode requests currently running
+	storageReqs  map[uint64]*storageRequest      // Storage requests currently running
+	trienodeReqs map[uint64]*trienodeHealRequest // Trie node heal requests currently running
+
+	accountReqFails  chan *accountRequest      // Failed account range requests to revert
+	bytecodeReqFails chan *bytecodeRequest     // Failed bytecode requests to revert
+	storageReqFails  chan *storageRequest      // Failed storage requests to revert
+	trienodeReqFails chan *trienodeHealRequest // Failed trie node requests to revert
+
+	accountResps  chan *accountResponse      // Account sub-tries to integrate into the database
+	bytecodeResps chan *bytecodeResponse     // Bytecodes to integrate into the database
+	storageResps  chan *storageResponse      // Storage sub-tries to integrate into the database
+	trienodeResps chan *trienodeHealResponse // Trie nodes to integrate into the database
+
+	accountSynced  uint64             // Number of accounts downloaded
+	accountBytes   common.StorageSize // Number of account trie bytes persisted to disk
+	bytecodeSynced uint64             // Number of bytecodes downloaded
+	bytecodeBytes  common.StorageSize // Number of bytecode bytes downloaded
+	storageSynced  uint64             // Number of storage slots downloaded
+	storageBytes   common.StorageSize // Number of storage trie bytes persisted to disk
+
+	trienodeHealSynced uint64             // Number of state trie nodes downloaded
+	trienodeHealBytes  common.StorageSize // Number of state trie bytes persisted to disk
+	bytecodeHealSynced uint64             // Number of bytecodes downloaded
+	bytecodeHealBytes  common.StorageSize // Number of bytecodes persisted to disk
+
+	startTime time.Time // Time instance when snapshot sync started
+	logTime   time.Time // Time instance when status was last reported
+
+	lock sync.RWMutex // Protects fields that can change outside of sync (peers, reqs, root)
+}
+
+// NewSyncer creates a new snapshot syncer to download the Ethereum state over the
+// snap protocol.
+func NewSyncer(db ethdb.KeyValueStore, bloom *trie.SyncBloom) *Syncer {
+	batch := db.NewBatch()
+	return &Syncer{
+		db:     db,
+		bloom:  bloom,
+		batch:  batch,
+		writer: &bloomWriter{batch: batch, bloom: bloom},
+
+		update:  make(chan struct{}, 1),
+		peers:   make(map[string]SyncPeer),
+		idlers:  make(map[string]struct{}),
+		stale:   make(chan struct{}),
+		nostate: make(map[string]struct{}),
+
+		accountReqs:  make(map[uint64]*accountRequest),
+		bytecodeReqs: make(map[uint64]*bytecodeRequest),
+		storageReqs:  make(map[uint64]*storageRequest),
+		trienodeReqs: make(map[uint64]*trienodeHealRequest),
+
+		accountReqFails:  make(chan *accountRequest),
+		bytecodeReqFails: make(chan *bytecodeRequest),
+		storageReqFails:  make(chan *storageRequest),
+		trienodeReqFails: make(chan *trienodeHealRequest),
+
+		accountResps:  make(chan *accountResponse),
+		bytecodeResps: make(chan *bytecodeResponse),
+		storageResps:  make(chan *storageResponse),
+		trienodeResps: make(chan *trienodeHealResponse),
+	}
+}
+
+// Register injects a new data source into the syncer's peerset.
+func (s *Syncer) Register(peer SyncPeer) error {
+	// Make sure the peer is not registered yet
+	id := peer.ID()
+
+	s.lock.Lock()
+	if _, ok := s.peers[id]; ok {
+		log.Error("Snap peer already registered", "id", id)
+
+		s.lock.Unlock()
+		return errors.New("already registered")
+	}
+	s.peers[id] = peer
+	s.idlers[id] = struct{}{}
+	s.lock.Unlock()
+
+	// Notify any active syncs that a new peer can be assigned data
+	s.notify()
+	return nil
+}
+
+// Unregister injects a new data source into the syncer's peerset.
+func (s *Syncer) Unregister(id string) error {
+	// Remove all traces of the peer from the registry
+	s.lock.Lock()
+	if _, ok := s.peers[id]; !ok {
+		log.Error("Snap peer not registered", "id", id)
+
+		s.lock.Unlock()
+		return errors.New("not registered")
+	}
+	delete(s.peers, id)
+	delete(s.idlers, id)
+
+	// Gather the requests assigned to the peer and unlink them
+	var (
+		accountReqs  []*accountRequest
+		bytecodeReqs []*bytecodeRequest
+		storageReqs  []*storageRequest
+		trienodeReqs []*trienodeHealRequest
+	)
+	for reqid, req := range s.accountReqs {
+		if req.peer == id {
+			req.timeout.Stop()
+			delete(s.accountReqs, reqid)
+			accountReqs = append(accountReqs, req)
+		}
+	}
+	for reqid, req := range s.bytecodeReqs {
+		if req.peer == id {
+			req.timeout.Stop()
+			delete(s.bytecodeReqs, reqid)
+			bytecodeReqs = append(bytecodeReqs, req)
+		}
+	}
+	for reqid, req := range s.storageReqs {
+		if req.peer == id {
+			req.timeout.Stop()
+			delete(s.storageReqs, reqid)
+			storageReqs = append(storageReqs, req)
+		}
+	}
+	for reqid, req := range s.trienodeReqs {
+		if req.peer == id {
+			req.timeout.Stop()
+			delete(s.trienodeReqs, reqid)
+			trienodeReqs = append(trienodeReqs, req)
+		}
+	}
+	s.lock.Unlock()
+
+	// Hand the requests of the peer back to the sync cycle for reassignment
+	for _, req := range accountReqs {
+		s.scheduleRevertAccountRequest(req)
+	}
+	for _, req := range bytecodeReqs {
+		s.scheduleRevertBytecodeRequest(req)
+	}
+	for _, req := range storageReqs {
+		s.scheduleRevertStorageRequest(req)
+	}
+	for _, req := range trienodeReqs {
+		s.scheduleRevertTrienodeHealRequest(req)
+	}
+	return nil
+}
+
+// Sync starts (or resumes a previous) sync cycle to iterate over an state trie
+// with the given root and reconstruct the nodes based on the snapshot leaves.
+// Previously downloaded segments will not be redownloaded of fixed, rather any
+// errors will be healed after the leaves are fully accumulated.
+func (s *Syncer) Sync(root common.Hash, cancel chan struct{}) error {
+	// Move the trie root from any previous value, revert stateless markers for
+	// any peers and initialize the syncer if it was not yet run
+	s.lock.Lock()
+	s.root = root
+	s.healer = state.NewStateSync(root, s.db, s.bloom)
+	s.healing = false
+	s.healNodes = make(map[common.Hash]trie.SyncPath)
+	s.healCodes = make(map[common.Hash]struct{})
+	s.stale = make(chan struct{})
+	s.nostate = make(map[string]struct{})
+	s.lock.Unlock()
+
+	if s.startTime == (time.Time{}) {
+		s.startTime = time.Now()
+	}
+	// Retrieve the previous sync status from LevelDB and abort if already synced
+	s.loadSyncStatus()
+	if len(s.tasks) == 0 && s.healer.Pending() == 0 {
+		log.Debug("Snapshot sync already completed")
+		return nil
+	}
+	defer func() { // Persist any progress, independent of failure
+		s.cleanup()
+		s.saveSyncStatus()
+	}()
+
+	log.Debug("Starting snapshot sync cycle", "root", root)
+	for {
+		// Remove all completed tasks and terminate sync if everything's done
+		s.cleanAccountTasks()
+		if len(s.tasks) == 0 && s.healer.Pending() == 0 {
+			return s.commit()
+		}
+		// Assign all the data retrieval tasks to any free peers
+		if len(s.tasks) > 0 {
+			s.assignAccountTasks()
+			s.assignBytecodeTasks()
+			s.assignStorageTasks()
+		} else {
+			if !s.healing {
+				// Make the generated trie nodes visible to the healer
+				if err := s.flush(true); err != nil {
+					return err
+				}
+				log.Debug("Starting state trie healing", "root", root)
+				s.healing = true
+			}
+			s.assignTrienodeHealTasks()
+			s.assignBytecodeHealTasks()
+		}
+		// Wait for something to happen
+		select {
+		case <-s.update:
+			// Something happened (new peer, delivery, timeout), recheck tasks
+		case <-cancel:
+			return errCancelled
+
+		case req := <-s.accountReqFails:
+			s.revertAccountRequest(req)
+		case req := <-s.bytecodeReqFails:
+			s.revertBytecodeRequest(req)
+		case req := <-s.storageReqFails:
+			s.revertStorageRequest(req)
+		case req := <-s.trienodeReqFails:
+			s.revertTrienodeHealRequest(req)
+
+		case res := <-s.accountResps:
+			s.processAccountResponse(res)
+		case res := <-s.bytecodeResps:
+			s.processBytecodeResponse(res)
+		case res := <-s.storageResps:
+			s.processStorageResponse(res)
+		case res := <-s.trienodeResps:
+			s.processTrienodeHealResponse(res)
+		}
+		if err := s.flush(false); err != nil {
+			return err
+		}
+		// Report stats if something meaningful happened
+		s.report(false)
+	}
+}
+
+// loadSyncStatus retrieves a previously aborted sync status from the database,
+// or generates a fresh one if none is available.
+func (s *Syncer) loadSyncStatus() {
+	var progress syncProgress
+
+	if status := rawdb.ReadSnapshotSyncStatus(s.db); status != nil {
+		if err := json.Unmarshal(status, &progress); err != nil {
+			log.Error("Failed to decode snap sync status", "err", err)
+		} else {
+			for _, task := range progress.Tasks {
+				log.Debug("Scheduled account sync task", "from", task.Next, "last", task.Last)
+				s.initAccountTask(task)
+			}
+			s.tasks = progress.Tasks
+
+			s.accountSynced = progress.AccountSynced
+			s.accountBytes = progress.AccountBytes
+			s.bytecodeSynced = progress.BytecodeSynced
+			s.bytecodeBytes = progress.BytecodeBytes
+			s.storageSynced = progress.StorageSynced
+			s.storageBytes = progress.StorageBytes
+
+			s.trienodeHealSynced = progress.TrienodeHealSynced
+			s.trienodeHealBytes = progress.TrienodeHealBytes
+			s.bytecodeHealSynced = progress.BytecodeHealSynced
+			s.bytecodeHealBytes = progress.BytecodeHealBytes
+			return
+		}
+	}
+	// Either we've failed to decode the previus state, or there was none.
+	// Start a fresh sync by chunking up the account range and scheduling
+	// them for retrieval.
+	s.tasks = nil
+	s.accountSynced, s.accountBytes = 0, 0
+	s.bytecodeSynced, s.bytecodeBytes = 0, 0
+	s.storageSynced, s.storageBytes = 0, 0
+	s.trienodeHealSynced, s.trienodeHealBytes = 0, 0
+	s.bytecodeHealSynced, s.bytecodeHealBytes = 0, 0
+
+	var next common.Hash
+	step := new(big.Int).Sub(
+		new(big.Int).Div(
+			new(big.Int).Exp(common.Big2, common.Big256, nil),
+			big.NewInt(accountConcurrency),
+		), common.Big1,
+	)
+	for i := 0; i < accountConcurrency; i++ {
+		last := common.BigToHash(new(big.Int).Add(next.Big(), step))
+		if i == accountConcurrency-1 {
+			// Make sure we don't overflow if the step is not a proper divisor
+			last = common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+		}
+		task := &accountTask{Next: next, Last: last}
+		s.initAccountTask(task)
+		s.tasks = append(s.tasks, task)
+
+		log.Debug("Created account sync task", "from", next, "last", last)
+		next = common.BigToHash(new(big.Int).Add(last.Big(), common.Big1))
+	}
+}
+
+// initAccountTask sets up the runtime fields of a fresh or resumed account task.
+// The trie generator starts from scratch, any nodes crossing the resumption point
+// will be filled by healing.
+func (s *Syncer) initAccountTask(task *accountTask) {
+	task.codeTasks = make(map[common.Hash]struct{})
+	task.stateTasks = make(map[common.Hash]common.Hash)
+	task.subTasks = make(map[common.Hash]*storageTask)
+	task.genTrie = trie.NewStackTrie(s.writer)
+}
+
+// saveSyncStatus marshals the remaining sync tasks into leveldb.
+func (s *Syncer) saveSyncStatus() {
+	// Push the filled prefix of any half-filled account ranges to disk
+	for _, task := range s.tasks {
+		s.forwardAccountTask(task)
+	}
+	s.cleanAccountTasks()
+	if err := s.flush(true); err != nil {
+		log.Error("Failed to persist snap sync data", "err", err)
+		return
+	}
+	// Store the actual progress markers
+	progress := &syncProgress{
+		Tasks:              s.tasks,
+		AccountSynced:      s.accountSynced,
+		AccountBytes:       s.accountBytes,
+		BytecodeSynced:     s.bytecodeSynced,
+		BytecodeBytes:      s.bytecodeBytes,
+		StorageSynced:      s.storageSynced,
+		StorageBytes:       s.storageBytes,
+		TrienodeHealSynced: s.trienodeHealSynced,
+		TrienodeHealBytes:  s.trienodeHealBytes,
+		BytecodeHealSynced: s.bytecodeHealSynced,
+		BytecodeHealBytes:  s.bytecodeHealBytes,
+	}
+	if progress.Tasks == nil {
+		progress.Tasks = []*accountTask{} // Distinguish a finished sync from a fresh one
+	}
+	status, err := json.Marshal(progress)
+	if err != nil {
+		panic(err) // This can only fail during implementation
+	}
+	rawdb.WriteSnapshotSyncStatus(s.db, status)
+}
+
+// cleanAccountTasks removes account range retrieval tasks that have already been
+// completed.
+func (s *Syncer) cleanAccountTasks() {
+	for i := 0; i < len(s.tasks); i++ {
+		if s.tasks[i].done {
+			s.tasks = append(s.tasks[:i], s.tasks[i+1:]...)
+			i--
+		}
+	}
+}
+
+// commit flushes the healer and any pending data into the database after the
+// sync cycle finished.
+func (s *Syncer) commit() error {
+	if err := s.healer.Commit(s.batch); err != nil {
+		return err
+	}
+	if err := s.flush(true); err != nil {
+		return err
+	}
+	s.report(true)
+	return nil
+}
+
+// flush writes the accumulated batch into the database if it grew large enough,
+// or unconditionally if forced.
+func (s *Syncer) flush(force bool) error {
+	if !force && s.batch.ValueSize() < ethdb.IdealBatchSize {
+		return nil
+	}
+	if err := s.batch.Write(); err != nil {
+		return fmt.Errorf("failed to persist snap sync data: %v", err)
+	}
+	s.batch.Reset()
+	return nil
+}
+
+// cleanup detaches all the requests still in flight from the sync cycle that's
+// ending, handing their tasks back and returning their peers to the idle set.
+func (s *Syncer) cleanup() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for id, req := range s.accountReqs {
+		req.timeout.Stop()
+		delete(s.accountReqs, id)
+		s.markIdle(req.peer)
+		req.task.req = nil
+	}
+	for id, req := range s.bytecodeReqs {
+		req.timeout.Stop()
+		delete(s.bytecodeReqs, id)
+		s.markIdle(req.peer)
+	}
+	for id, req := range s.storageReqs {
+		req.timeout.Stop()
+		delete(s.storageReqs, id)
+		s.markIdle(req.peer)
+	}
+	for id, req := range s.trienodeReqs {
+		req.timeout.Stop()
+		delete(s.trienodeReqs, id)
+		s.markIdle(req.peer)
+	}
+	// Release any deliveries blocked on the ended sync cycle
+	close(s.stale)
+}
+
+// notify pings the sync cycle that something might have changed, without
+// blocking if a notification is already pending.
+func (s *Syncer) notify() {
+	select {
+	case s.update <- struct{}{}:
+	default:
+	}
+}
+
+// markIdle returns a peer to the idle set if it's still registered. The caller
+// must hold the lock.
+func (s *Syncer) markIdle(id string) {
+	if _, ok := s.peers[id]; ok {
+		s.idlers[id] = struct{}{}
+	}
+}
+
+// idlePeer picks an idle peer that's still assumed to have the state being
+// synced, removing it from the idle set. The caller must hold the lock.
+func (s *Syncer) idlePeer() (string, SyncPeer) {
+	for id := range s.idlers {
+		if _, ok := s.nostate[id]; ok {
+			continue
+		}
+		delete(s.idlers, id)
+		return id, s.peers[id]
+	}
+	return "", nil
+}
+
+// requestID generates a request ID not in use by any running request. The
+// caller must hold the lock.
+func (s *Syncer) requestID() uint64 {
+	for {
+		id := uint64(rand.Int63())
+		if _, ok := s.accountReqs[id]; ok {
+			continue
+		}
+		if _, ok := s.bytecodeReqs[id]; ok {
+			continue
+		}
+		if _, ok := s.storageReqs[id]; ok {
+			continue
+		}
+		if _, ok := s.trienodeReqs[id]; ok {
+			continue
+		}
+		return id
+	}
+}
+
+// assignAccountTasks attempts to match idle peers to pending account range
+// retrievals.
+func (s *Syncer) assignAccountTasks() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, task := range s.tasks {
+		// Skip any tasks already filling or waiting for their subtasks
+		if task.req != nil || task.res != nil {
+			continue
+		}
+		id, peer := s.idlePeer()
+		if peer == nil {
+			return
+		}
+		req := &accountRequest{
+			peer:   id,
+			id:     s.requestID(),
+			stale:  s.stale,
+			origin: task.Next,
+			task:   task,
+		}
+		req.timeout = time.AfterFunc(requestTimeout, func() {
+			peer.Log().Debug("Account range request timed out", "reqid", req.id)
+			s.lock.Lock()
+			if _, ok := s.accountReqs[req.id]; !ok {
+				s.lock.Unlock()
+				return
+			}
+			delete(s.accountReqs, req.id)
+			s.markIdle(req.peer)
+			s.lock.Unlock()
+
+			s.scheduleRevertAccountRequest(req)
+		})
+		s.accountReqs[req.id] = req
+		task.req = req
+
+		go func(root common.Hash, limit common.Hash) {
+			if err := peer.RequestAccountRange(req.id, root, req.origin, limit, maxRequestSize); err != nil {
+				peer.Log().Debug("Failed to request account range", "err", err)
+				s.failRequest(req.id)
+			}
+		}(s.root, task.Last)
+	}
+}
+
+// assignBytecodeTasks attempts to match idle peers to pending code retrievals.
+func (s *Syncer) assignBytecodeTasks() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, task := range s.tasks {
+		if len(task.codeTasks) == 0 {
+			continue
+		}
+		id, peer := s.idlePeer()
+		if peer == nil {
+			return
+		}
+		hashes := make([]common.Hash, 0, maxCodeRequestCount)
+		for hash := range task.codeTasks {
+			delete(task.codeTasks, hash)
+			hashes = append(hashes, hash)
+			if len(hashes) >= maxCodeRequestCount {
+				break
+			}
+		}
+		s.sendBytecodeRequest(id, peer, hashes, task)
+	}
+}
+
+// sendBytecodeRequest assigns a bytecode retrieval to a peer and sends it over
+// the network. The caller must hold the lock.
+func (s *Syncer) sendBytecodeRequest(id string, peer SyncPeer, hashes []common.Hash, task *accountTask) {
+	req := &bytecodeRequest{
+		peer:   id,
+		id:     s.requestID(),
+		stale:  s.stale,
+		hashes: hashes,
+		task:   task,
+	}
+	req.timeout = time.AfterFunc(requestTimeout, func() {
+		peer.Log().Debug("Bytecode request timed out", "reqid", req.id)
+		s.lock.Lock()
+		if _, ok := s.bytecodeReqs[req.id]; !ok {
+			s.lock.Unlock()
+			return
+		}
+		delete(s.bytecodeReqs, req.id)
+		s.markIdle(req.peer)
+		s.lock.Unlock()
+
+		s.scheduleRevertBytecodeRequest(req)
+	})
+	s.bytecodeReqs[req.id] = req
+
+	go func() {
+		if err := peer.RequestByteCodes(req.id, hashes, maxRequestSize); err != nil {
+			peer.Log().Debug("Failed to request bytecodes", "err", err)
+			s.failRequest(req.id)
+		}
+	}()
+}
+
+// assignStorageTasks attempts to match idle peers to pending storage range
+// retrievals.
+func (s *Syncer) assignStorageTasks() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, task := range s.tasks {
+		// Large storage tries are retrieved chunk by chunk, one account per request
+		for account, subtask := range task.subTasks {
+			if subtask.req != nil {
+				continue
+			}
+			id, peer := s.idlePeer()
+			if peer == nil {
+				return
+			}
+			req := &storageRequest{
+				accounts: []common.Hash{account},
+				roots:    []common.Hash{subtask.root},
+				origin:   subtask.Next,
+				mainTask: task,
+				subTask:  subtask,
+			}
+			subtask.req = req
+			s.sendStorageRequest(id, peer, req)
+		}
+		// Small storage tries are batched together, retrieved from their start
+		for len(task.stateTasks) > 0 {
+			id, peer := s.idlePeer()
+			if peer == nil {
+				return
+			}
+			req := &storageRequest{mainTask: task}
+			for account, root := range task.stateTasks {
+				delete(task.stateTasks, account)
+				req.accounts = append(req.accounts, account)
+				req.roots = append(req.roots, root)
+				if len(req.accounts) >= maxStorageSetRequestCount {
+					break
+				}
+			}
+			s.sendStorageRequest(id, peer, req)
+		}
+	}
+}
+
+// sendStorageRequest assigns a storage range retrieval to a peer and sends it
+// over the network. The caller must hold the lock.
+func (s *Syncer) sendStorageRequest(id string, peer SyncPeer, req *storageRequest) {
+	req.peer = id
+	req.id = s.requestID()
+	req.stale = s.stale
+	req.timeout = time.AfterFunc(requestTimeout, func() {
+		peer.Log().Debug("Storage request timed out", "reqid", req.id)
+		s.lock.Lock()
+		if _, ok := s.storageReqs[req.id]; !ok {
+			s.lock.Unlock()
+			return
+		}
+		delete(s.storageReqs, req.id)
+		s.markIdle(req.peer)
+		s.lock.Unlock()
+
+		s.scheduleRevertStorageRequest(req)
+	})
+	s.storageReqs[req.id] = req
+
+	var origin []byte
+	if req.subTask != nil {
+		origin = req.origin[:]
+	}
+	go func(root common.Hash) {
+		if err := peer.RequestStorageRanges(req.id, root, req.accounts, origin, nil, maxRequestSize); err != nil {
+			peer.Log().Debug("Failed to request storage", "err", err)
+			s.failRequest(req.id)
+		}
+	}(s.root)
+}
+
+// assignTrienodeHealTasks attempts to match idle peers to trie node requests to
+// heal any trie errors caused by the snap sync's chunked retrieval model.
+func (s *Syncer) assignTrienodeHealTasks() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for {
+		// Top up the pending trie nodes from the healer's missing set
+		if len(s.healNodes) < maxTrieRequestCount {
+			nodes, paths, codes := s.healer.Missing(maxTrieRequestCount)
+			for i, hash := range nodes {
+				s.healNodes[hash] = paths[i]
+			}
+			for _, hash := range codes {
+				s.healCodes[hash] = struct{}{}
+			}
+		}
+		if len(s.healNodes) == 0 {
+			return
+		}
+		id, peer := s.idlePeer()
+		if peer == nil {
+			return
+		}
+		var (
+			hashes = make([]common.Hash, 0, maxTrieRequestCount)
+			paths  = make([]trie.SyncPath, 0, maxTrieRequestCount)
+		)
+		for hash, path := range s.healNodes {
+			delete(s.healNodes, hash)
+
+			hashes = append(hashes, hash)
+			paths = append(paths, path)
+			if len(hashes) >= maxTrieRequestCount {
+				break
+			}
+		}
+		// Group the storage trie paths of the same account into path sets
+		var pathsets []TrieNodePathSet
+		for _, path := range paths {
+			if len(path) == 1 {
+				pathsets = append(pathsets, TrieNodePathSet{path[0]})
+				continue
+			}
+			if n := len(pathsets); n > 0 && len(pathsets[n-1]) > 1 && bytes.Equal(pathsets[n-1][0], path[0]) {
+				pathsets[n-1] = append(pathsets[n-1], path[1])
+				continue
+			}
+			pathsets = append(pathsets, TrieNodePathSet{path[0], path[1]})
+		}
+		req := &trienodeHealRequest{
+			peer:   id,
+			id:     s.requestID(),
+			stale:  s.stale,
+			hashes: hashes,
+			paths:  paths,
+		}
+		req.timeout = time.AfterFunc(requestTimeout, func() {
+			peer.Log().Debug("Trienode heal request timed out", "reqid", req.id)
+			s.lock.Lock()
+			if _, ok := s.trienodeReqs[req.id]; !ok {
+				s.lock.Unlock()
+				return
+			}
+			delete(s.trienodeReqs, req.id)
+			s.markIdle(req.peer)
+			s.lock.Unlock()
+
+			s.scheduleRevertTrienodeHealRequest(req)
+		})
+		s.trienodeReqs[req.id] = req
+
+		go func(root common.Hash) {
+			if err := peer.RequestTrieNodes(req.id, root, pathsets, maxRequestSize); err != nil {
+				peer.Log().Debug("Failed to request trienode healers", "err", err)
+				s.failRequest(req.id)
+			}
+		}(s.root)
+	}
+}
+
+// assignBytecodeHealTasks attempts to match idle peers to bytecode requests to
+// heal any trie errors caused by the snap sync's chunked retrieval model.
+func (s *Syncer) assignBytecodeHealTasks() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for len(s.healCodes) > 0 {
+		id, peer := s.idlePeer()
+		if peer == nil {
+			return
+		}
+		hashes := make([]common.Hash, 0, maxCodeRequestCount)
+		for hash := range s.healCodes {
+			delete(s.healCodes, hash)
+			hashes = append(hashes, hash)
+			if len(hashes) >= maxCodeRequestCount {
+				break
+			}
+		}
+		s.sendBytecodeRequest(id, peer, hashes, nil)
+	}
+}
+
+// failRequest detaches a request that couldn't be sent from the running set and
+// hands it back to the sync cycle for reassignment.
+func (s *Syncer) failRequest(id uint64) {
+	s.lock.Lock()
+	var revert func()
+	if req, ok := s.accountReqs[id]; ok {
+		delete(s.accountReqs, id)
+		req.timeout.Stop()
+		s.markIdle(req.peer)
+		revert = func() { s.scheduleRevertAccountRequest(req) }
+	}
+	if req, ok := s.bytecodeReqs[id]; ok {
+		delete(s.bytecodeReqs, id)
+		req.timeout.Stop()
+		s.markIdle(req.peer)
+		revert = func() { s.scheduleRevertBytecodeRequest(req) }
+	}
+	if req, ok := s.storageReqs[id]; ok {
+		delete(s.storageReqs, id)
+		req.timeout.Stop()
+		s.markIdle(req.peer)
+		revert = func() { s.scheduleRevertStorageRequest(req) }
+	}
+	if req, ok := s.trienodeReqs[id]; ok {
+		delete(s.trienodeReqs, id)
+		req.timeout.Stop()
+		s.markIdle(req.peer)
+		revert = func() { s.scheduleRevertTrienodeHealRequest(req) }
+	}
+	s.lock.Unlock()
+
+	if revert != nil {
+		revert()
+	}
+}
+
+// scheduleRevertAccountRequest asks the event loop to clean up an account range
+// request and return all failed retrieval tasks to the scheduler for reassignment.
+func (s *Syncer) scheduleRevertAccountRequest(req *accountRequest) {
+	select {
+	case s.accountReqFails <- req:
+		// Sync event loop notified
+	case <-req.stale:
+		// Sync cycle got cancelled
+	}
+}
+
+// revertAccountRequest cleans up an account range request and returns all failed
+// retrieval tasks to the scheduler for reassignment.
+func (s *Syncer) revertAccountRequest(req *accountRequest) {
+	log.Debug("Reverting account request", "peer", req.peer, "reqid", req.id)
+	if req.task.req == req {
+		req.task.req = nil
+	}
+}
+
+// scheduleRevertBytecodeRequest asks the event loop to clean up a bytecode request
+// and return all failed retrieval tasks to the scheduler for reassignment.
+func (s *Syncer) scheduleRevertBytecodeRequest(req *bytecodeRequest) {
+	select {
+	case s.bytecodeReqFails <- req:
+		// Sync event loop notified
+	case <-req.stale:
+		// Sync cycle got cancelled
+	}
+}
+
+// revertBytecodeRequest cleans up a bytecode request and returns all failed
+// retrieval tasks to the scheduler for reassignment.
+func (s *Syncer) revertBytecodeRequest(req *bytecodeRequest) {
+	log.Debug("Reverting bytecode request", "peer", req.peer, "reqid", req.id)
+	for _, hash := range req.hashes {
+		if req.task != nil {
+			req.task.codeTasks[hash] = struct{}{}
+		} else {
+			s.healCodes[hash] = struct{}{}
+		}
+	}
+}
+
+// scheduleRevertStorageRequest asks the event loop to clean up a storage range
+// request and return all failed retrieval tasks to the scheduler for reassignment.
+func (s *Syncer) scheduleRevertStorageRequest(req *storageRequest) {
+	select {
+	case s.storageReqFails <- req:
+		// Sync event loop notified
+	case <-req.stale:
+		// Sync cycle got cancelled
+	}
+}
+
+// revertStorageRequest cleans up a storage range request and returns all failed
+// retrieval tasks to the scheduler for reassignment.
+func (s *Syncer) revertStorageRequest(req *storageRequest) {
+	log.Debug("Reverting storage request", "peer", req.peer, "reqid", req.id)
+	if req.subTask != nil {
+		req.subTask.req = nil
+		return
+	}
+	for i, account := range req.accounts {
+		req.mainTask.stateTasks[account] = req.roots[i]
+	}
+}
+
+// scheduleRevertTrienodeHealRequest asks the event loop to clean up a trienode
+// heal request and return all failed retrieval tasks to the scheduler for
+// reassignment.
+func (s *Syncer) scheduleRevertTrienodeHealRequest(req *trienodeHealRequest) {
+	select {
+	case s.trienodeReqFails <- req:
+		// Sync event loop notified
+	case <-req.stale:
+		// Sync cycle got cancelled
+	}
+}
+
+// revertTrienodeHealRequest cleans up a trienode heal request and returns all
+// failed retrieval tasks to the scheduler for reassignment.
+func (s *Syncer) revertTrienodeHealRequest(req *trienodeHealRequest) {
+	log.Debug("Reverting trienode heal request", "peer", req.peer, "reqid", req.id)
+	for i, hash := range req.hashes {
+		s.healNodes[hash] = req.paths[i]
+	}
+}
+
+// processAccountResponse integrates an already validated account range response
+// into the account tasks.
+func (s *Syncer) processAccountResponse(res *accountResponse) {
+	// Switch the task from pending to filling
+	res.task.req = nil
+	res.task.res = res
+
+	// Ensure that the response doesn't overflow into the subsequent task
+	last := res.task.Last.Big()
+	for i, hash := range res.hashes {
+		// Mark the range complete if the last is already included.
+		// Keep iteration to delete the extra states if exists.
+		cmp := hash.Big().Cmp(last)
+		if cmp == 0 {
+			res.cont = false
+			continue
+		}
+		if cmp > 0 {
+			// Chunk overflown, cut off excess
+			res.hashes = res.hashes[:i]
+			res.accounts = res.accounts[:i]
+			res.cont = false // Mark range completed
+			break
+		}
+	}
+	// Iterate over all the accounts and assemble which ones need further sub-
+	// filling before the entire account range can be persisted.
+	res.task.needCode = make([]bool, len(res.accounts))
+	res.task.needState = make([]bool, len(res.accounts))
+	res.task.needHeal = make([]bool, len(res.accounts))
+	res.task.pend = 0
+
+	for i, account := range res.accounts {
+		// Check if the account is a contract with an unknown code
+		if !bytes.Equal(account.CodeHash, emptyCode[:]) {
+			if code := rawdb.ReadCode(s.db, common.BytesToHash(account.CodeHash)); len(code) == 0 {
+				res.task.codeTasks[common.BytesToHash(account.CodeHash)] = struct{}{}
+				res.task.needCode[i] = true
+				res.task.pend++
+			}
+		}
+		// Check if the account is a contract with an unknown storage trie
+		if account.Root != emptyRoot {
+			if node := rawdb.ReadTrieNode(s.db, account.Root); len(node) == 0 {
+				res.task.stateTasks[res.hashes[i]] = account.Root
+				res.task.needState[i] = true
+				res.task.pend++
+			}
+		}
+	}
+	s.accountSynced += uint64(len(res.accounts))
+
+	// If the account range contained no contracts, or all have been fully filled
+	// beforehand, short circuit storage filling and forward to the next task
+	if res.task.pend == 0 {
+		s.forwardAccountTask(res.task)
+	}
+}
+
+// processBytecodeResponse integrates an already validated bytecode response
+// into the account tasks or the healer.
+func (s *Syncer) processBytecodeResponse(res *bytecodeResponse) {
+	var (
+		task   = res.req.task
+		codes  uint64
+		synced common.StorageSize
+	)
+	for i, hash := range res.req.hashes {
+		code := res.codes[i]
+
+		// If the bytecode was not delivered, reschedule it
+		if code == nil {
+			if task != nil {
+				task.codeTasks[hash] = struct{}{}
+			} else {
+				s.healCodes[hash] = struct{}{}
+			}
+			continue
+		}
+		// Code was delivered, mark it not needed any more
+		codes++
+		synced += common.StorageSize(len(code))
+
+		if task == nil {
+			if err := s.healer.Process(trie.SyncResult{Hash: hash, Data: code}); err != nil && err != trie.ErrAlreadyProcessed && err != trie.ErrNotRequested {
+				log.Error("Invalid bytecode processed", "hash", hash, "err", err)
+			}
+			continue
+		}
+		rawdb.WriteCode(s.batch, hash, code)
+		s.bloom.Add(hash[:])
+
+		for j, account := range task.res.accounts {
+			if task.needCode[j] && hash == common.BytesToHash(account.CodeHash) {
+				task.needCode[j] = false
+				task.pend--
+			}
+		}
+	}
+	if task == nil {
+		if err := s.healer.Commit(s.batch); err != nil {
+			log.Error("Failed to commit healing data", "err", err)
+		}
+		s.bytecodeHealSynced += codes
+		s.bytecodeHealBytes += synced
+		return
+	}
+	s.bytecodeSynced += codes
+	s.bytecodeBytes += synced
+
+	// If this delivery completed the last pending task, forward the account task
+	// to the next chunk
+	if task.res != nil && task.pend == 0 {
+		s.forwardAccountTask(task)
+	}
+}
+
+// processStorageResponse integrates an already validated storage response
+// into the account tasks.
+func (s *Syncer) processStorageResponse(res *storageResponse) {
+	var (
+		req   = res.req
+		task  = req.mainTask
+		slots uint64
+	)
+	for i, account := range req.accounts {
+		// If the storage was not delivered, reschedule it
+		if i >= len(res.hashes) {
+			if req.subTask != nil {
+				req.subTask.req = nil
+			} else {
+				task.stateTasks[account] = req.roots[i]
+			}
+			continue
+		}
+		slots += uint64(len(res.hashes[i]))
+
+		// If the last storage range is incomplete, switch to chunked retrieval
+		if i == len(res.hashes)-1 && res.cont {
+			subtask := req.subTask
+			if subtask == nil {
+				subtask = &storageTask{
+					root:    req.roots[i],
+					genTrie: trie.NewStackTrie(s.writer),
+				}
+				task.subTasks[account] = subtask
+
+				log.Debug("Chunking large storage trie", "account", account, "root", req.roots[i])
+			}
+			for j, hash := range res.hashes[i] {
+				subtask.genTrie.Update(hash[:], res.slots[i][j])
+			}
+			subtask.Next = incHash(res.hashes[i][len(res.hashes[i])-1])
+			subtask.req = nil
+			continue
+		}
+		// Storage range complete, generate all the trie nodes of it
+		generator := trie.NewStackTrie(s.writer)
+		if req.subTask != nil {
+			generator = req.subTask.genTrie
+			delete(task.subTasks, account)
+		}
+		for j, hash := range res.hashes[i] {
+			generator.Update(hash[:], res.slots[i][j])
+		}
+		root, err := generator.Commit()
+		if err != nil {
+			log.Error("Failed to generate storage trie", "account", account, "err", err)
+		}
+		heal := root != req.roots[i]
+		if heal {
+			log.Debug("Storage trie mismatch, scheduling for healing", "account", account, "have", root, "want", req.roots[i])
+		}
+		for j, hash := range task.res.hashes {
+			if task.needState[j] && hash == account {
+				task.needState[j] = false
+				task.needHeal[j] = heal
+				task.pend--
+			}
+		}
+	}
+	s.storageSynced += slots
+
+	// If this delivery completed the last pending task, forward the account task
+	// to the next chunk
+	if task.res != nil && task.pend == 0 {
+		s.forwardAccountTask(task)
+	}
+}
+
+// processTrienodeHealResponse integrates an already validated trienode response
+// into the healer tasks.
+func (s *Syncer) processTrienodeHealResponse(res *trienodeHealResponse) {
+	for i, hash := range res.req.hashes {
+		node := res.nodes[i]
+
+		// If the trie node was not delivered, reschedule it
+		if node == nil {
+			s.healNodes[hash] = res.req.paths[i]
+			continue
+		}
+		// Push the trie node into the state syncer
+		s.trienodeHealSynced++
+		s.trienodeHealBytes += common.StorageSize(len(node))
+
+		err := s.healer.Process(trie.SyncResult{Hash: hash, Data: node})
+		switch err {
+		case nil:
+		case trie.ErrAlreadyProcessed, trie.ErrNotRequested:
+			log.Debug("Redundant trie node delivered", "hash", hash, "err", err)
+		default:
+			log.Error("Invalid trienode processed", "hash", hash, "err", err)
+		}
+	}
+	if err := s.healer.Commit(s.batch); err != nil {
+		log.Error("Failed to commit healing data", "err", err)
+	}
+}
+
+// forwardAccountTask takes a filled account task and persists anything available
+// into the database, after which it forwards the next account marker so that the
+// task's next chunk may be filled.
+func (s *Syncer) forwardAccountTask(task *accountTask) {
+	res := task.res
+	if res == nil {
+		return // nothing to forward
+	}
+	// Feed the filled accounts into the trie generator in order, stopping at the
+	// first one still missing data. Accounts whose storage could not be fully
+	// reconstructed are left out, so the trie nodes above them are healed.
+	for i, hash := range res.hashes {
+		if task.needCode[i] || task.needState[i] {
+			// Drop the forwarded prefix, keep the rest pending
+			res.hashes, res.accounts = res.hashes[i:], res.accounts[i:]
+			task.needCode, task.needState, task.needHeal = task.needCode[i:], task.needState[i:], task.needHeal[i:]
+			return
+		}
+		if !task.needHeal[i] {
+			blob, err := rlp.EncodeToBytes(res.accounts[i])
+			if err != nil {
+				panic(err) // Account was already decoded, what happened
+			}
+			task.genTrie.Update(hash[:], blob)
+			s.accountBytes += common.StorageSize(common.HashLength + len(blob))
+		}
+		task.Next = incHash(hash)
+	}
+	task.res = nil
+
+	// All accounts marked as complete, track if the entire task is done
+	if !res.cont {
+		if _, err := task.genTrie.Commit(); err != nil {
+			log.Error("Failed to generate account trie", "err", err)
+		}
+		task.done = true
+	}
+}
+
+// OnAccounts is a callback method to invoke when a range of accounts are
+// received from a remote peer.
+func (s *Syncer) OnAccounts(peer SyncPeer, id uint64, hashes []common.Hash, accounts [][]byte, proof [][]byte) error {
+	size := common.StorageSize(len(hashes) * common.HashLength)
+	for _, account := range accounts {
+		size += common.StorageSize(len(account))
+	}
+	for _, node := range proof {
+		size += common.StorageSize(len(node))
+	}
+	logger := peer.Log().New("reqid", id)
+	logger.Trace("Delivering range of accounts", "hashes", len(hashes), "accounts", len(accounts), "proofs", len(proof), "bytes", size)
+
+	// Whether or not the response is valid, we can mark the peer as idle and
+	// notify the scheduler to assign a new task. If the response is invalid,
+	// we'll drop the peer in a bit.
+	s.lock.Lock()
+	defer s.notify()
+
+	req, ok := s.accountReqs[id]
+	if !ok || req.peer != peer.ID() {
+		// Request stale, perhaps the peer timed out but came through in the end
+		logger.Warn("Unexpected account range packet")
+		s.lock.Unlock()
+		return nil
+	}
+	delete(s.accountReqs, id)
+	s.markIdle(req.peer)
+
+	// Clean up the request timeout timer, we'll see how to proceed further based
+	// on the actual delivered content
+	req.timeout.Stop()
+
+	// Response is valid, but check if peer is signalling that it does not have
+	// the requested data. For account range queries that means the state being
+	// retrieved was either already pruned remotely, or the peer is not yet
+	// synced to our head.
+	if len(hashes) == 0 && len(accounts) == 0 && len(proof) == 0 {
+		logger.Debug("Peer rejected account range request", "root", s.root)
+		s.nostate[req.peer] = struct{}{}
+		s.lock.Unlock()
+
+		// Signal this request as failed, and ready for rescheduling
+		s.scheduleRevertAccountRequest(req)
+		return nil
+	}
+	root := s.root
+	s.lock.Unlock()
+
+	// Reconstruct a partial trie from the response and verify it
+	keys := make([][]byte, len(hashes))
+	for i, key := range hashes {
+		keys[i] = common.CopyBytes(key[:])
+	}
+	nodes := make(light.NodeList, len(proof))
+	for i, node := range proof {
+		nodes[i] = node
+	}
+	proofdb := nodes.NodeSet()
+
+	var end []byte
+	if len(keys) > 0 {
+		end = keys[len(keys)-1]
+	}
+	err, cont := trie.VerifyRangeProof(root, req.origin[:], end, keys, accounts, proofdb)
+	if err != nil {
+		logger.Warn("Account range failed proof", "err", err)
+		// Signal this request as failed, and ready for rescheduling
+		s.scheduleRevertAccountRequest(req)
+		return err
+	}
+	accs := make([]*state.Account, len(accounts))
+	for i, account := range accounts {
+		acc := new(state.Account)
+		if err := rlp.DecodeBytes(account, acc); err != nil {
+			panic(err) // We created these blobs, we must be able to decode them
+		}
+		accs[i] = acc
+	}
+	response := &accountResponse{
+		task:     req.task,
+		hashes:   hashes,
+		accounts: accs,
+		cont:     cont,
+	}
+	select {
+	case s.accountResps <- response:
+	case <-req.stale:
+	}
+	return nil
+}
+
+// OnByteCodes is a callback method to invoke when a batch of contract
+// bytes codes are received from a remote peer.
+func (s *Syncer) OnByteCodes(peer SyncPeer, id uint64, bytecodes [][]byte) error {
+	var size common.StorageSize
+	for _, code := range bytecodes {
+		size += common.StorageSize(len(code))
+	}
+	logger := peer.Log().New("reqid", id)
+	logger.Trace("Delivering set of bytecodes", "bytecodes", len(bytecodes), "bytes", size)
+
+	// Whether or not the response is valid, we can mark the peer as idle and
+	// notify the scheduler to assign a new task. If the response is invalid,
+	// we'll drop the peer in a bit.
+	s.lock.Lock()
+	defer s.notify()
+
+	req, ok := s.bytecodeReqs[id]
+	if !ok || req.peer != peer.ID() {
+		// Request stale, perhaps the peer timed out but came through in the end
+		logger.Warn("Unexpected bytecode packet")
+		s.lock.Unlock()
+		return nil
+	}
+	delete(s.bytecodeReqs, id)
+	s.markIdle(req.peer)
+
+	// Clean up the request timeout timer, we'll see how to proceed further based
+	// on the actual delivered content
+	req.timeout.Stop()
+
+	// Response is valid, but check if peer is signalling that it does not have
+	// the requested data. For bytecode range queries that means the peer is not
+	// yet synced.
+	if len(bytecodes) == 0 {
+		logger.Debug("Peer rejected bytecode request")
+		s.nostate[req.peer] = struct{}{}
+		s.lock.Unlock()
+
+		// Signal this request as failed, and ready for rescheduling
+		s.scheduleRevertBytecodeRequest(req)
+		return nil
+	}
+	s.lock.Unlock()
+
+	// Cross reference the requested bytecodes with the response to find gaps
+	// that the serving node is missing
+	hasher := sha3.NewLegacyKeccak256()
+	hash := make([]byte, 32)
+
+	codes := make([][]byte, len(req.hashes))
+	for i, j := 0, 0; i < len(bytecodes); i++ {
+		// Find the next hash that we've been served, leaving misses with nils
+		hasher.Reset()
+		hasher.Write(bytecodes[i])
+		hasher.Sum(hash[:0])
+
+		for j < len(req.hashes) && !bytes.Equal(hash, req.hashes[j][:]) {
+			j++
+		}
+		if j < len(req.hashes) {
+			codes[j] = bytecodes[i]
+			j++
+			continue
+		}
+		// We've either ran out of hashes, or got unrequested data
+		logger.Warn("Unexpected bytecodes", "count", len(bytecodes)-i)
+		// Signal this request as failed, and ready for rescheduling
+		s.scheduleRevertBytecodeRequest(req)
+		return errors.New("unexpected bytecode")
+	}
+	// Response validated, send it to the scheduler for filling
+	response := &bytecodeResponse{
+		req:   req,
+		codes: codes,
+	}
+	select {
+	case s.bytecodeResps <- response:
+	case <-req.stale:
+	}
+	return nil
+}
+
+// OnStorage is a callback method to invoke when ranges of storage slots
+// are received from a remote peer.
+func (s *Syncer) OnStorage(peer SyncPeer, id uint64, hashes [][]common.Hash, slots [][][]byte, proof [][]byte) error {
+	// Gather some trace stats to aid in debugging issues
+	var (
+		hashCount int
+		slotCount int
+		size      common.StorageSize
+	)
+	for _, hashset := range hashes {
+		size += common.StorageSize(common.HashLength * len(hashset))
+		hashCount += len(hashset)
+	}
+	for _, slotset := range slots {
+		for _, slot := range slotset {
+			size += common.StorageSize(len(slot))
+		}
+		slotCount += len(slotset)
+	}
+	for _, node := range proof {
+		size += common.StorageSize(len(node))
+	}
+	logger := peer.Log().New("reqid", id)
+	logger.Trace("Delivering ranges of storage slots", "accounts", len(hashes), "hashes", hashCount, "slots", slotCount, "proofs", len(proof), "size", size)
+
+	// Whether or not the response is valid, we can mark the peer as idle and
+	// notify the scheduler to assign a new task. If the response is invalid,
+	// we'll drop the peer in a bit.
+	s.lock.Lock()
+	defer s.notify()
+
+	req, ok := s.storageReqs[id]
+	if !ok || req.peer != peer.ID() {
+		// Request stale, perhaps the peer timed out but came through in the end
+		logger.Warn("Unexpected storage ranges packet")
+		s.lock.Unlock()
+		return nil
+	}
+	delete(s.storageReqs, id)
+	s.markIdle(req.peer)
+
+	// Clean up the request timeout timer, we'll see how to proceed further based
+	// on the actual delivered content
+	req.timeout.Stop()
+
+	// Reject the response if the hash sets and slot sets don't match, or if the
+	// peer sent more data than requested.
+	if len(hashes) != len(slots) {
+		s.lock.Unlock()
+		s.scheduleRevertStorageRequest(req) // reschedule request
+		logger.Warn("Hash and slot set size mismatch", "hashset", len(hashes), "slotset", len(slots))
+		return errors.New("hash and slot set size mismatch")
+	}
+	if len(hashes) > len(req.accounts) {
+		s.lock.Unlock()
+		s.scheduleRevertStorageRequest(req) // reschedule request
+		logger.Warn("Hash set larger than requested", "hashset", len(hashes), "requested", len(req.accounts))
+		return errors.New("hash set larger than requested")
+	}
+	// Response is valid, but check if peer is signalling that it does not have
+	// the requested data. For storage range queries that means the state being
+	// retrieved was either already pruned remotely, or the peer is not yet
+	// synced to our head.
+	if len(hashes) == 0 {
+		logger.Debug("Peer rejected storage request")
+		s.nostate[req.peer] = struct{}{}
+		s.lock.Unlock()
+
+		// Signal this request as failed, and ready for rescheduling
+		s.scheduleRevertStorageRequest(req)
+		return nil
+	}
+	s.lock.Unlock()
+
+	// Reconstruct the partial tries from the response and verify them
+	var cont bool
+
+	for i := 0; i < len(hashes); i++ {
+		// Convert the keys and proofs into an internal format
+		keys := make([][]byte, len(hashes[i]))
+		for j, key := range hashes[i] {
+			keys[j] = common.CopyBytes(key[:])
+		}
+		var err error
+		if i < len(hashes)-1 || len(proof) == 0 {
+			// If no proof was attached, the storage range must be complete
+			err, _ = trie.VerifyRangeProof(req.roots[i], nil, nil, keys, slots[i], nil)
+		} else {
+			// A proof was attached, the response is only partial, check that the
+			// returned data is indeed part of the storage trie
+			nodes := make(light.NodeList, len(proof))
+			for j, node := range proof {
+				nodes[j] = node
+			}
+			proofdb := nodes.NodeSet()
+
+			var end []byte
+			if len(keys) > 0 {
+				end = keys[len(keys)-1]
+			}
+			err, cont = trie.VerifyRangeProof(req.roots[i], req.origin[:], end, keys, slots[i], proofdb)
+		}
+		if err != nil {
+			// Signal this request as failed, and ready for rescheduling
+			s.scheduleRevertStorageRequest(req)
+			logger.Warn("Storage slots failed proof", "err", err)
+			return err
+		}
+	}
+	// Partial tries reconstructed, send them to the scheduler for storage filling
+	response := &storageResponse{
+		req:    req,
+		hashes: hashes,
+		slots:  slots,
+		cont:   cont,
+	}
+	select {
+	case s.storageResps <- response:
+	case <-req.stale:
+	}
+	return nil
+}
+
+// OnTrieNodes is a callback method to invoke when a batch of trie nodes
+// are received from a remote peer.
+func (s *Syncer) OnTrieNodes(peer SyncPeer, id uint64, trienodes [][]byte) error {
+	var size common.StorageSize
+	for _, node := range trienodes {
+		size += common.StorageSize(len(node))
+	}
+	logger := peer.Log().New("reqid", id)
+	logger.Trace("Delivering set of healing trienodes", "trienodes", len(trienodes), "bytes", size)
+
+	// Whether or not the response is valid, we can mark the peer as idle and
+	// notify the scheduler to assign a new task. If the response is invalid,
+	// we'll drop the peer in a bit.
+	s.lock.Lock()
+	defer s.notify()
+
+	req, ok := s.trienodeReqs[id]
+	if !ok || req.peer != peer.ID() {
+		// Request stale, perhaps the peer timed out but came through in the end
+		logger.Warn("Unexpected trienode heal packet")
+		s.lock.Unlock()
+		return nil
+	}
+	delete(s.trienodeReqs, id)
+	s.markIdle(req.peer)
+
+	// Clean up the request timeout timer, we'll see how to proceed further based
+	// on the actual delivered content
+	req.timeout.Stop()
+
+	// Response is valid, but check if peer is signalling that it does not have
+	// the requested data. For trie node queries that means the state being
+	// retrieved was either already pruned remotely, or the peer is not yet
+	// synced to our head.
+	if len(trienodes) == 0 {
+		logger.Debug("Peer rejected trienode heal request")
+		s.nostate[req.peer] = struct{}{}
+		s.lock.Unlock()
+
+		// Signal this request as failed, and ready for rescheduling
+		s.scheduleRevertTrienodeHealRequest(req)
+		return nil
+	}
+	s.lock.Unlock()
+
+	// Cross reference the requested trienodes with the response to find gaps
+	// that the serving node is missing
+	hasher := sha3.NewLegacyKeccak256()
+	hash := make([]byte, 32)
+
+	nodes := make([][]byte, len(req.hashes))
+	for i, j := 0, 0; i < len(trienodes); i++ {
+		// Find the next hash that we've been served, leaving misses with nils
+		hasher.Reset()
+		hasher.Write(trienodes[i])
+		hasher.Sum(hash[:0])
+
+		for j < len(req.hashes) && !bytes.Equal(hash, req.hashes[j][:]) {
+			j++
+		}
+		if j < len(req.hashes) {
+			nodes[j] = trienodes[i]
+			j++
+			continue
+		}
+		// We've either ran out of hashes, or got unrequested data
+		logger.Warn("Unexpected healing trienodes", "count", len(trienodes)-i)
+		// Signal this request as failed, and ready for rescheduling
+		s.scheduleRevertTrienodeHealRequest(req)
+		return errors.New("unexpected healing trienode")
+	}
+	// Response validated, send it to the scheduler for filling
+	response := &trienodeHealResponse{
+		req:   req,
+		nodes: nodes,
+	}
+	select {
+	case s.trienodeResps <- response:
+	case <-req.stale:
+	}
+	return nil
+}
+
+// report calculates various status reports and provides it to the user.
+func (s *Syncer) report(force bool) {
+	if len(s.tasks) > 0 {
+		s.reportSyncProgress(force)
+		return
+	}
+	s.reportHealProgress(force)
+}
+
+// reportSyncProgress calculates various status reports and provides it to the user.
+func (s *Syncer) reportSyncProgress(force bool) {
+	// Don't report all the events, just occasionally
+	if !force && time.Since(s.logTime) < 8*time.Second {
+		return
+	}
+	s.logTime = time.Now()
+
+	// Estimate the progress from the untouched account hash space
+	left := new(big.Int)
+	for _, task := range s.tasks {
+		left.Add(left, new(big.Int).Sub(task.Last.Big(), task.Next.Big()))
+	}
+	total := new(big.Int).Exp(common.Big2, common.Big256, nil)
+	done := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Sub(total, left)), new(big.Float).SetInt(total))
+	percent, _ := done.Float64()
+
+	var (
+		accounts = fmt.Sprintf("%d@%v", s.accountSynced, s.accountBytes.TerminalString())
+		storage  = fmt.Sprintf("%d@%v", s.storageSynced, s.storageBytes.TerminalString())
+		bytecode = fmt.Sprintf("%d@%v", s.bytecodeSynced, s.bytecodeBytes.TerminalString())
+	)
+	log.Info("State sync in progress", "synced", fmt.Sprintf("%.2f%%", percent*100),
+		"accounts", accounts, "slots", storage, "codes", bytecode, "elapsed", common.PrettyDuration(time.Since(s.startTime)))
+}
+
+// reportHealProgress calculates various status reports and provides it to the user.
+func (s *Syncer) reportHealProgress(force bool) {
+	// Don't report all the events, just occasionally
+	if !force && time.Since(s.logTime) < 8*time.Second {
+		return
+	}
+	s.logTime = time.Now()
+
+	var (
+		trienode = fmt.Sprintf("%d@%v", s.trienodeHealSynced, s.trienodeHealBytes.TerminalString())
+		bytecode = fmt.Sprintf("%d@%v", s.bytecodeHealSynced, s.bytecodeHealBytes.TerminalString())
+	)
+	log.Info("State heal in progress", "nodes", trienode, "codes", bytecode,
+		"pending", s.healer.Pending(), "elapsed", common.PrettyDuration(time.Since(s.startTime)))
+}
+
+// bloomWriter is a database writer for the generated trie nodes, which also
+// tracks the written hashes in the sync bloom so the healer won't retrieve them
+// again.
+type bloomWriter struct {
+	batch ethdb.Batch     // Batch accumulating the trie nodes
+	bloom *trie.SyncBloom // Bloom filter tracking the trie nodes
+}
+
+// Put inserts the given trie node into the batch and its hash into the bloom.
+func (w *bloomWriter) Put(key []byte, value []byte) error {
+	w.bloom.Add(key)
+	return w.batch.Put(key, value)
+}
+
+// Delete removes the key from the batch.
+func (w *bloomWriter) Delete(key []byte) error {
+	return w.batch.Delete(key)
+}
+
+// incHash returns the next hash, in lexicographical order (a.k.a plus one).
+func incHash(h common.Hash) common.Hash {
+	return common.BigToHash(new(big.Int).Add(h.Big(), common.Big1))
+}
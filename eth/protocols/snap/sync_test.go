@@ -0,0 +1,328 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snap
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// counterContract stores the number of each block at its slot of that number.
+var counterContract = common.HexToAddress("0xc0de")
+
+// newSourceChain creates an archive chain with a state snapshot, holding plain
+// accounts, small contracts and a large contract, whose blocks each write a new
+// storage slot of a contract.
+func newSourceChain(t *testing.T, blocks int) (*core.BlockChain, []*types.Block) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		key, _  = crypto.GenerateKey()
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		alloc   = core.GenesisAlloc{
+			address:         {Balance: big.NewInt(params.Ether)},
+			counterContract: {Balance: new(big.Int), Code: []byte{byte(vm.NUMBER), byte(vm.NUMBER), byte(vm.SSTORE)}},
+		}
+	)
+	for i := 0; i < 500; i++ {
+		alloc[common.BigToAddress(big.NewInt(int64(0x1000+i)))] = core.GenesisAccount{Balance: big.NewInt(int64(i + 1))}
+	}
+	for i := 0; i < 50; i++ {
+		storage := make(map[common.Hash]common.Hash)
+		for j := 0; j <= i; j++ {
+			storage[common.BigToHash(big.NewInt(int64(j)))] = common.BigToHash(big.NewInt(int64(i + j + 1)))
+		}
+		alloc[common.BigToAddress(big.NewInt(int64(0x2000+i)))] = core.GenesisAccount{
+			Balance: new(big.Int),
+			Code:    []byte{byte(vm.PUSH1), byte(i), byte(vm.STOP)},
+			Storage: storage,
+		}
+	}
+	large := make(map[common.Hash]common.Hash)
+	for j := 0; j < 2000; j++ {
+		large[common.BigToHash(big.NewInt(int64(j)))] = common.BigToHash(big.NewInt(int64(j + 1)))
+	}
+	alloc[common.HexToAddress("0x3000")] = core.GenesisAccount{Balance: new(big.Int), Code: []byte{byte(vm.STOP)}, Storage: large}
+
+	gspec := &core.Genesis{Config: params.TestChainConfig, Alloc: alloc}
+	genesis := gspec.MustCommit(db)
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainID)
+
+	chainBlocks, _ := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, blocks, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(address), counterContract, new(big.Int), 100000, big.NewInt(1), nil), signer, key)
+		b.AddTx(tx)
+	})
+	cacheConfig := &core.CacheConfig{
+		TrieCleanLimit:    256,
+		TrieDirtyLimit:    256,
+		TrieDirtyDisabled: true,
+		TrieTimeLimit:     5 * time.Minute,
+		SnapshotLimit:     256,
+		SnapshotWait:      true,
+	}
+	chain, err := core.NewBlockChain(db, cacheConfig, params.TestChainConfig, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	if _, err := chain.InsertChain(chainBlocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	return chain, append([]*types.Block{genesis}, chainBlocks...)
+}
+
+// testPeer is an in-memory snap peer serving the state of a local chain to a
+// remote syncer.
+type testPeer struct {
+	id     string
+	chain  *core.BlockChain
+	remote *Syncer
+	limit  uint64 // Response size cap to force chunked retrievals (0 = none)
+	empty  bool   // Whether to refuse serving any state
+	logger log.Logger
+}
+
+func newTestPeer(id string, chain *core.BlockChain, remote *Syncer) *testPeer {
+	return &testPeer{id: id, chain: chain, remote: remote, logger: log.New("id", id)}
+}
+
+func (p *testPeer) ID() string      { return p.id }
+func (p *testPeer) Log() log.Logger { return p.logger }
+
+func (p *testPeer) cap(bytes uint64) uint64 {
+	if p.limit != 0 && bytes > p.limit {
+		return p.limit
+	}
+	return bytes
+}
+
+func (p *testPeer) RequestAccountRange(id uint64, root, origin, limit common.Hash, bytes uint64) error {
+	go func() {
+		if p.empty {
+			p.remote.OnAccounts(p, id, nil, nil, nil)
+			return
+		}
+		accounts, proof := ServiceGetAccountRangeQuery(p.chain, &GetAccountRangePacket{ID: id, Root: root, Origin: origin, Limit: limit, Bytes: p.cap(bytes)})
+		hashes, blobs, err := (&AccountRangePacket{ID: id, Accounts: accounts, Proof: proof}).Unpack()
+		if err != nil {
+			panic(err)
+		}
+		if err := p.remote.OnAccounts(p, id, hashes, blobs, proof); err != nil {
+			p.logger.Error("Account range delivery failed", "err", err)
+		}
+	}()
+	return nil
+}
+
+func (p *testPeer) RequestStorageRanges(id uint64, root common.Hash, accounts []common.Hash, origin, limit []byte, bytes uint64) error {
+	go func() {
+		if p.empty {
+			p.remote.OnStorage(p, id, nil, nil, nil)
+			return
+		}
+		slots, proof := ServiceGetStorageRangesQuery(p.chain, &GetStorageRangesPacket{ID: id, Root: root, Accounts: accounts, Origin: origin, Limit: limit, Bytes: p.cap(bytes)})
+		hashes, blobs := (&StorageRangesPacket{ID: id, Slots: slots, Proof: proof}).Unpack()
+		if err := p.remote.OnStorage(p, id, hashes, blobs, proof); err != nil {
+			p.logger.Error("Storage ranges delivery failed", "err", err)
+		}
+	}()
+	return nil
+}
+
+func (p *testPeer) RequestByteCodes(id uint64, hashes []common.Hash, bytes uint64) error {
+	go func() {
+		if p.empty {
+			p.remote.OnByteCodes(p, id, nil)
+			return
+		}
+		codes := ServiceGetByteCodesQuery(p.chain, &GetByteCodesPacket{ID: id, Hashes: hashes, Bytes: p.cap(bytes)})
+		if err := p.remote.OnByteCodes(p, id, codes); err != nil {
+			p.logger.Error("Bytecodes delivery failed", "err", err)
+		}
+	}()
+	return nil
+}
+
+func (p *testPeer) RequestTrieNodes(id uint64, root common.Hash, paths []TrieNodePathSet, bytes uint64) error {
+	go func() {
+		if p.empty {
+			p.remote.OnTrieNodes(p, id, nil)
+			return
+		}
+		nodes, err := ServiceGetTrieNodesQuery(p.chain, &GetTrieNodesPacket{ID: id, Root: root, Paths: paths, Bytes: p.cap(bytes)})
+		if err != nil {
+			panic(err)
+		}
+		if err := p.remote.OnTrieNodes(p, id, nodes); err != nil {
+			p.logger.Error("Trienodes delivery failed", "err", err)
+		}
+	}()
+	return nil
+}
+
+// newTestSyncer creates a snap syncer on top of an empty database.
+func newTestSyncer() (ethdb.Database, *Syncer) {
+	db := rawdb.NewMemoryDatabase()
+	return db, NewSyncer(db, trie.NewSyncBloom(1, db))
+}
+
+// runSync registers the given peers and syncs the state of the given root.
+func runSync(t *testing.T, syncer *Syncer, root common.Hash, peers ...*testPeer) {
+	for _, peer := range peers {
+		if err := syncer.Register(peer); err != nil {
+			t.Fatalf("failed to register peer %s: %v", peer.id, err)
+		}
+	}
+	done := make(chan error, 1)
+	cancel := make(chan struct{})
+	go func() { done <- syncer.Sync(root, cancel) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("sync failed: %v", err)
+		}
+	case <-time.After(time.Minute):
+		close(cancel)
+		t.Fatalf("sync timed out")
+	}
+	for _, peer := range peers {
+		syncer.Unregister(peer.id)
+	}
+}
+
+// checkState iterates the entire state of the given root, verifying that all the
+// trie nodes, storage tries and contract codes are present in the database.
+func checkState(t *testing.T, db ethdb.Database, root common.Hash) {
+	triedb := trie.NewDatabase(db)
+	accTrie, err := trie.New(root, triedb)
+	if err != nil {
+		t.Fatalf("account trie missing: %v", err)
+	}
+	var accounts, slots int
+	it := accTrie.NodeIterator(nil)
+	for it.Next(true) {
+		if !it.Leaf() {
+			continue
+		}
+		accounts++
+
+		var acc state.Account
+		if err := rlp.DecodeBytes(it.LeafBlob(), &acc); err != nil {
+			t.Fatalf("invalid account %x: %v", it.LeafKey(), err)
+		}
+		if common.BytesToHash(acc.CodeHash) != emptyCode {
+			if code := rawdb.ReadCode(db, common.BytesToHash(acc.CodeHash)); len(code) == 0 {
+				t.Errorf("code of account %x missing", it.LeafKey())
+			}
+		}
+		if acc.Root == emptyRoot {
+			continue
+		}
+		stTrie, err := trie.New(acc.Root, triedb)
+		if err != nil {
+			t.Fatalf("storage trie of account %x missing: %v", it.LeafKey(), err)
+		}
+		stIt := stTrie.NodeIterator(nil)
+		for stIt.Next(true) {
+			if stIt.Leaf() {
+				slots++
+			}
+		}
+		if err := stIt.Error(); err != nil {
+			t.Fatalf("storage trie of account %x incomplete: %v", it.LeafKey(), err)
+		}
+	}
+	if err := it.Error(); err != nil {
+		t.Fatalf("account trie incomplete: %v", err)
+	}
+	if accounts == 0 || slots == 0 {
+		t.Fatalf("state empty: %d accounts, %d slots", accounts, slots)
+	}
+}
+
+// Tests that the state can be synced from multiple peers.
+func TestSync(t *testing.T) {
+	chain, blocks := newSourceChain(t, 10)
+	defer chain.Stop()
+
+	db, syncer := newTestSyncer()
+	root := blocks[len(blocks)-1].Root()
+	runSync(t, syncer, root, newTestPeer("a", chain, syncer), newTestPeer("b", chain, syncer))
+	checkState(t, db, root)
+}
+
+// Tests that the state can be synced with small responses, chunking up both the
+// account ranges and the large storage tries.
+func TestSyncChunked(t *testing.T) {
+	chain, blocks := newSourceChain(t, 10)
+	defer chain.Stop()
+
+	db, syncer := newTestSyncer()
+	root := blocks[len(blocks)-1].Root()
+
+	peers := make([]*testPeer, 3)
+	for i := range peers {
+		peers[i] = newTestPeer(fmt.Sprintf("peer-%d", i), chain, syncer)
+		peers[i].limit = 2000
+	}
+	runSync(t, syncer, root, peers...)
+	checkState(t, db, root)
+}
+
+// Tests that peers refusing to serve state are skipped.
+func TestSyncStatelessPeer(t *testing.T) {
+	chain, blocks := newSourceChain(t, 10)
+	defer chain.Stop()
+
+	db, syncer := newTestSyncer()
+	root := blocks[len(blocks)-1].Root()
+
+	empty := newTestPeer("empty", chain, syncer)
+	empty.empty = true
+	runSync(t, syncer, root, empty, newTestPeer("full", chain, syncer))
+	checkState(t, db, root)
+}
+
+// Tests that syncing to a newer root after a completed sync heals the state
+// differences.
+func TestSyncHeal(t *testing.T) {
+	chain, blocks := newSourceChain(t, 10)
+	defer chain.Stop()
+
+	db, syncer := newTestSyncer()
+	runSync(t, syncer, blocks[5].Root(), newTestPeer("a", chain, syncer))
+	checkState(t, db, blocks[5].Root())
+
+	root := blocks[len(blocks)-1].Root()
+	runSync(t, syncer, root, newTestPeer("b", chain, syncer))
+	checkState(t, db, root)
+}
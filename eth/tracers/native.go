@@ -0,0 +1,67 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/uint256"
+)
+
+// ResultTracer is a transaction tracer assembling a JSON result, which can be
+// interrupted mid-execution. Both the JavaScript and the native tracers are
+// ResultTracers.
+type ResultTracer interface {
+	vm.Tracer
+
+	// GetResult returns the assembled trace, or any accumulated error.
+	GetResult() (json.RawMessage, error)
+
+	// Stop terminates the tracing at the first opportune moment.
+	Stop(err error)
+}
+
+// native contains the built in Go tracers by name. They produce the same
+// results as their JavaScript counterparts, at a fraction of the cost.
+var native = map[string]func() ResultTracer{
+	"callTracer":     newCallTracer,
+	"prestateTracer": newPrestateTracer,
+}
+
+// NewNative creates the native Go tracer of the given name, if one exists.
+func NewNative(name string) (ResultTracer, bool) {
+	if ctor, ok := native[name]; ok {
+		return ctor(), true
+	}
+	return nil, false
+}
+
+// memorySlice returns a copy of the given memory range, or nil if it's out of
+// bounds, mirroring the memory access of the JavaScript tracers.
+func memorySlice(memory *vm.Memory, offset, size *uint256.Int) []byte {
+	if size.IsZero() {
+		return []byte{}
+	}
+	end := new(uint256.Int).Add(offset, size)
+	if !offset.IsUint64() || !end.IsUint64() || end.Lt(offset) || uint64(memory.Len()) < end.Uint64() {
+		log.Warn("Tracer accessed out of bound memory", "available", memory.Len(), "offset", offset, "size", size)
+		return nil
+	}
+	return memory.GetCopy(int64(offset.Uint64()), int64(size.Uint64()))
+}
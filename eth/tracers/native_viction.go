@@ -0,0 +1,83 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Frame types of the Viction specific state transitions reported by the tracers.
+const (
+	SignFrame    = "SIGN"    // Block signing transaction, applied without EVM execution
+	TomoXFrame   = "TOMOX"   // TomoX transaction, included without EVM execution
+	SponsorFrame = "SPONSOR" // Gas fee paid by the VRC25 contract instead of the sender
+)
+
+// VictionTracer is a tracer able to report the Viction state transitions which
+// don't go through the EVM, so traces agree with the receipts of the chain.
+type VictionTracer interface {
+	// CaptureSystemTx reports a transaction applied without EVM execution. It is
+	// called after the transaction was applied to the state.
+	CaptureSystemTx(db vm.StateDB, typ string, from, to common.Address, input []byte, gas uint64)
+
+	// CaptureSponsoredFee reports the gas fee of the traced transaction paid by
+	// the VRC25 contract on behalf of the sender.
+	CaptureSponsoredFee(payer, coinbase common.Address, fee *big.Int)
+}
+
+// CaptureSystemTx implements VictionTracer, reporting the transaction as a single
+// frame of the given type which used no gas.
+func (t *callTracer) CaptureSystemTx(db vm.StateDB, typ string, from, to common.Address, input []byte, gas uint64) {
+	var used uint64
+	t.ctx = callFrame{
+		Type:    typ,
+		From:    from,
+		To:      &to,
+		Value:   (*hexutil.Big)(new(big.Int)),
+		Gas:     (*hexutil.Uint64)(&gas),
+		GasUsed: (*hexutil.Uint64)(&used),
+		Input:   (*hexutil.Bytes)(&input),
+	}
+}
+
+// CaptureSponsoredFee implements VictionTracer, reporting the fee payment as a
+// transfer from the VRC25 contract to the block's coinbase.
+func (t *callTracer) CaptureSponsoredFee(payer, coinbase common.Address, fee *big.Int) {
+	root := t.callstack[0]
+	root.Calls = append(root.Calls, &callFrame{
+		Type:  SponsorFrame,
+		From:  payer,
+		To:    &coinbase,
+		Value: (*hexutil.Big)(new(big.Int).Set(fee)),
+	})
+}
+
+// CaptureSystemTx implements VictionTracer. The only account touched by such a
+// transaction is the sender, whose nonce bump is reverted when assembling the
+// result, same as for any other transaction.
+func (t *prestateTracer) CaptureSystemTx(db vm.StateDB, typ string, from, to common.Address, input []byte, gas uint64) {
+	t.db = db
+	t.from, t.to, t.value = from, to, new(big.Int)
+}
+
+// CaptureSponsoredFee implements VictionTracer. The fee is paid out of the gas
+// bought before execution, which the prestate doesn't account for either.
+func (t *prestateTracer) CaptureSponsoredFee(payer, coinbase common.Address, fee *big.Int) {}
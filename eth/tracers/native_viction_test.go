@@ -0,0 +1,120 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+)
+
+// Tests that the native tracers report transactions applied without EVM
+// execution as a synthetic frame.
+func TestNativeSystemTx(t *testing.T) {
+	var (
+		from   = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		signer = common.HexToAddress("0x0000000000000000000000000000000000000089")
+		input  = common.FromHex("0xe341eaa40000000000000000000000000000000000000000000000000000000000000001")
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetBalance(from, big.NewInt(1000))
+	statedb.SetNonce(from, 8) // Nonce after the transaction was applied
+
+	call, _ := NewNative("callTracer")
+	call.(VictionTracer).CaptureSystemTx(statedb, SignFrame, from, signer, input, 200000)
+
+	res, err := call.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve call trace: %v", err)
+	}
+	want := map[string]interface{}{
+		"type":    "SIGN",
+		"from":    "0x1000000000000000000000000000000000000001",
+		"to":      "0x0000000000000000000000000000000000000089",
+		"value":   "0x0",
+		"gas":     "0x30d40",
+		"gasUsed": "0x0",
+		"input":   "0xe341eaa40000000000000000000000000000000000000000000000000000000000000001",
+	}
+	if have := decodeTrace(t, res); !reflect.DeepEqual(have, want) {
+		t.Errorf("call trace mismatch: have %v, want %v", have, want)
+	}
+	prestate, _ := NewNative("prestateTracer")
+	prestate.(VictionTracer).CaptureSystemTx(statedb, SignFrame, from, signer, input, 200000)
+
+	if res, err = prestate.GetResult(); err != nil {
+		t.Fatalf("failed to retrieve prestate: %v", err)
+	}
+	want = map[string]interface{}{
+		"0x1000000000000000000000000000000000000001": map[string]interface{}{
+			"balance": "0x3e8",
+			"nonce":   float64(7),
+			"code":    "0x",
+			"storage": map[string]interface{}{},
+		},
+	}
+	if have := decodeTrace(t, res); !reflect.DeepEqual(have, want) {
+		t.Errorf("prestate mismatch: have %v, want %v", have, want)
+	}
+}
+
+// Tests that the native call tracer reports the gas fee paid by the VRC25
+// contract as a transfer to the coinbase.
+func TestNativeSponsoredFee(t *testing.T) {
+	var (
+		from     = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		token    = common.HexToAddress("0x2000000000000000000000000000000000000002")
+		payer    = common.HexToAddress("0x8c0faeb5c6bed2129b8674f262fd45c4e9468bee")
+		coinbase = common.HexToAddress("0x3000000000000000000000000000000000000003")
+	)
+	tracer, _ := NewNative("callTracer")
+	tracer.CaptureStart(from, token, false, nil, 50000, new(big.Int))
+	tracer.CaptureEnd(nil, 21000, 0, nil)
+	tracer.(VictionTracer).CaptureSponsoredFee(payer, coinbase, big.NewInt(5250000000000000))
+
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve call trace: %v", err)
+	}
+	calls, _ := decodeTrace(t, res).(map[string]interface{})["calls"].([]interface{})
+	if len(calls) != 1 {
+		t.Fatalf("sponsored fee frame count mismatch: have %d, want 1", len(calls))
+	}
+	want := map[string]interface{}{
+		"type":  "SPONSOR",
+		"from":  "0x8c0faeb5c6bed2129b8674f262fd45c4e9468bee",
+		"to":    "0x3000000000000000000000000000000000000003",
+		"value": "0x12a6d8e1122000",
+	}
+	if !reflect.DeepEqual(calls[0], want) {
+		t.Errorf("sponsored fee frame mismatch: have %v, want %v", calls[0], want)
+	}
+}
+
+// decodeTrace unmarshals a tracer result into generic JSON values.
+func decodeTrace(t *testing.T, res json.RawMessage) interface{} {
+	var trace interface{}
+	if err := json.Unmarshal(res, &trace); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	return trace
+}
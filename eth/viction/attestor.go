@@ -0,0 +1,135 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package viction
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+	"math/rand"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/posv"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ErrInsufficientAttestors is returned if a checkpoint block records less attestors than validators.
+var ErrInsufficientAttestors = errors.New("attestor list is shorter than validator list")
+
+// CalcAttestors returns the attestor index of each validator for the next epoch. The indexes
+// are shuffled using the random numbers committed by validators to the randomizer contract,
+// whose secrets are set from the RandomizerCommitNthBlock and decrypted with the openings
+// revealed from the RandomizerRevealNthBlock of the epoch.
+func CalcAttestors(vicConfig *params.VictionConfig, statedb *state.StateDB, validators []common.Address) []int64 {
+	randomizes := make([]int64, 0, len(validators))
+	for _, validator := range validators {
+		secrets := statedb.VicGetRandomizeSecrets(vicConfig.RandomizerContract, validator)
+		opening := statedb.VicGetRandomizeOpening(vicConfig.RandomizerContract, validator)
+		randomizes = append(randomizes, DecryptRandomize(secrets, opening))
+	}
+	return GenerateAttestors(randomizes, len(validators))
+}
+
+// CalcCreatorAttestorPairs maps each validator recorded in the checkpoint header to the validator
+// assigned to attest its blocks. After TIPRandomize, the pairs are shifted along the epoch so that
+// a creator is not attested by the same validator for the whole epoch. The shift is also returned.
+func CalcCreatorAttestorPairs(config *params.ChainConfig, checkpointHeader, header *types.Header) (map[common.Address]common.Address, uint64, error) {
+	pairs := make(map[common.Address]common.Address)
+	validators := posv.ExtractValidatorsFromCheckpointHeader(checkpointHeader)
+	attestors, err := posv.DecodeAttestorsFromHeader(checkpointHeader.NewAttestors)
+	if err != nil {
+		return nil, 0, err
+	}
+	validatorCount := uint64(len(validators))
+	if uint64(len(attestors)) < validatorCount {
+		return nil, 0, ErrInsufficientAttestors
+	}
+	if validatorCount == 0 {
+		return pairs, 0, nil
+	}
+	shift := uint64(0)
+	if config.IsTIPRandomize(header.Number) {
+		shift = ((header.Number.Uint64() % config.Posv.Epoch) / validatorCount) % validatorCount
+	}
+	for i, creator := range validators {
+		attestorIndex := (uint64(attestors[i]%int64(validatorCount)) + shift) % validatorCount
+		pairs[creator] = validators[attestorIndex]
+	}
+	return pairs, shift, nil
+}
+
+// GenerateAttestors shuffles the indexes of validators with a random source seeded by the sum of
+// the random numbers of all validators.
+func GenerateAttestors(randomizes []int64, validatorCount int) []int64 {
+	total := int64(0)
+	for _, random := range randomizes {
+		total += random
+	}
+	random := rand.New(rand.NewSource(total))
+
+	indexes := make([]int64, validatorCount)
+	for i := range indexes {
+		indexes[i] = int64(i)
+	}
+	attestors := make([]int64, validatorCount)
+	for i := len(indexes) - 1; i >= 0; i-- {
+		bound := len(indexes) - 1
+		if bound <= 1 {
+			bound = 1
+		}
+		j := random.Intn(bound)
+		picked := indexes[j]
+		indexes[j] = indexes[i]
+		indexes[i] = picked
+		indexes = append(indexes[:i], indexes[i+1:]...)
+		attestors[i] = picked
+	}
+	return attestors
+}
+
+// DecryptRandomize returns the random number committed by a validator, which is the last of its
+// secrets that decrypts into a number with the revealed opening as key.
+func DecryptRandomize(secrets []common.Hash, opening common.Hash) int64 {
+	var random int64
+	for _, secret := range secrets {
+		decrypted := decryptSecret(opening.Bytes(), string(bytes.TrimLeft(secret[:], "\x00")))
+		if number, err := strconv.Atoi(decrypted); err == nil {
+			random = int64(number)
+		}
+	}
+	return random
+}
+
+// decryptSecret decrypts the base64 encoded AES-CFB cipher text prefixed by its IV.
+func decryptSecret(key []byte, cipherText string) string {
+	data, _ := base64.URLEncoding.DecodeString(cipherText)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return ""
+	}
+	if len(data) < aes.BlockSize {
+		return ""
+	}
+	iv, data := data[:aes.BlockSize], data[aes.BlockSize:]
+	cipher.NewCFBDecrypter(block, iv).XORKeyStream(data, data)
+	return string(data)
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package viction
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/posv"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var testRandomizerContract = common.HexToAddress("0x0000000000000000000000000000000000000090")
+
+// encryptSecret encrypts the text the same way validators commit their secrets.
+func encryptSecret(t *testing.T, key []byte, text string) common.Hash {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("failed to create cipher: %v", err)
+	}
+	data := make([]byte, aes.BlockSize+len(text))
+	iv := data[:aes.BlockSize]
+	if _, err := rand.Read(iv); err != nil {
+		t.Fatalf("failed to generate iv: %v", err)
+	}
+	cipher.NewCFBEncrypter(block, iv).XORKeyStream(data[aes.BlockSize:], []byte(text))
+	return common.BytesToHash([]byte(base64.URLEncoding.EncodeToString(data)))
+}
+
+// setRandomize writes the secrets and opening of a validator into the randomizer contract storage.
+func setRandomize(statedb *state.StateDB, validator common.Address, secrets []common.Hash, opening common.Hash) {
+	secretsSlot := state.StorageLocationOfMappingElement(state.StorageLocationFromSlot(0), validator.Hash().Bytes())
+	statedb.SetState(testRandomizerContract, secretsSlot.Hash(), common.BigToHash(big.NewInt(int64(len(secrets)))))
+	for i, secret := range secrets {
+		elemSlot := state.StorageLocationOfDynamicArrayElement(secretsSlot, uint64(i), 256)
+		statedb.SetState(testRandomizerContract, elemSlot.Hash(), secret)
+	}
+	openingSlot := state.StorageLocationOfMappingElement(state.StorageLocationFromSlot(1), validator.Hash().Bytes())
+	statedb.SetState(testRandomizerContract, openingSlot.Hash(), opening)
+}
+
+func TestDecryptRandomize(t *testing.T) {
+	opening := common.HexToHash("0x6162636465666768696a6b6c6d6e6f707172737475767778797a313233343536")
+	tests := []struct {
+		secrets []common.Hash
+		want    int64
+	}{
+		{nil, 0},
+		{[]common.Hash{encryptSecret(t, opening.Bytes(), "42")}, 42},
+		// The last valid secret wins
+		{[]common.Hash{encryptSecret(t, opening.Bytes(), "42"), encryptSecret(t, opening.Bytes(), "7")}, 7},
+		// Secrets which do not decrypt into a number are ignored
+		{[]common.Hash{encryptSecret(t, opening.Bytes(), "42"), encryptSecret(t, opening.Bytes(), "x")}, 42},
+		{[]common.Hash{common.HexToHash("0x1234")}, 0},
+	}
+	for i, tt := range tests {
+		if have := DecryptRandomize(tt.secrets, opening); have != tt.want {
+			t.Errorf("test %d: random mismatch: have %d, want %d", i, have, tt.want)
+		}
+	}
+}
+
+func TestGenerateAttestors(t *testing.T) {
+	for count := 0; count < 20; count++ {
+		randomizes := make([]int64, count)
+		for i := range randomizes {
+			randomizes[i] = int64(i * 1000)
+		}
+		attestors := GenerateAttestors(randomizes, count)
+		if len(attestors) != count {
+			t.Fatalf("count %d: attestor count mismatch: have %d", count, len(attestors))
+		}
+		// Every validator index must appear exactly once
+		seen := make(map[int64]bool)
+		for _, attestor := range attestors {
+			if attestor < 0 || attestor >= int64(count) || seen[attestor] {
+				t.Fatalf("count %d: invalid attestor list %v", count, attestors)
+			}
+			seen[attestor] = true
+		}
+		// Shuffling must be deterministic
+		again := GenerateAttestors(randomizes, count)
+		for i := range attestors {
+			if attestors[i] != again[i] {
+				t.Fatalf("count %d: non-deterministic attestors: %v != %v", count, attestors, again)
+			}
+		}
+	}
+}
+
+func TestCalcCreatorAttestorPairs(t *testing.T) {
+	var (
+		addr1 = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		addr2 = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		addr3 = common.HexToAddress("0x3333333333333333333333333333333333333333")
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	opening := common.HexToHash("0x6162636465666768696a6b6c6d6e6f707172737475767778797a313233343536")
+	for i, validator := range []common.Address{addr1, addr2, addr3} {
+		setRandomize(statedb, validator, []common.Hash{encryptSecret(t, opening.Bytes(), big.NewInt(int64(i+1)).String())}, opening)
+	}
+	vicConfig := &params.VictionConfig{RandomizerContract: testRandomizerContract}
+	validators := []common.Address{addr1, addr2, addr3}
+	attestors := CalcAttestors(vicConfig, statedb, validators)
+	if want := GenerateAttestors([]int64{1, 2, 3}, 3); len(attestors) != len(want) || attestors[0] != want[0] || attestors[1] != want[1] || attestors[2] != want[2] {
+		t.Fatalf("attestors mismatch: have %v, want %v", attestors, want)
+	}
+
+	extra := make([]byte, posv.ExtraVanity)
+	for _, validator := range validators {
+		extra = append(extra, validator.Bytes()...)
+	}
+	checkpoint := &types.Header{
+		Number:       big.NewInt(900),
+		Extra:        append(extra, make([]byte, posv.ExtraSeal)...),
+		NewAttestors: posv.EncodeAttestorsForHeader(attestors),
+	}
+	config := &params.ChainConfig{Posv: &params.PosvConfig{Epoch: 900}, TIPRandomizeBlock: big.NewInt(2000)}
+
+	// Before TIPRandomize, pairs stay the same for the whole epoch
+	pairs, shift, err := CalcCreatorAttestorPairs(config, checkpoint, &types.Header{Number: big.NewInt(905)})
+	if err != nil {
+		t.Fatalf("failed to calculate pairs: %v", err)
+	}
+	if shift != 0 {
+		t.Errorf("shift mismatch: have %d, want 0", shift)
+	}
+	for i, validator := range validators {
+		if want := validators[attestors[i]]; pairs[validator] != want {
+			t.Errorf("pair of %x mismatch: have %x, want %x", validator, pairs[validator], want)
+		}
+	}
+	// After TIPRandomize, pairs are shifted along the epoch
+	config.TIPRandomizeBlock = big.NewInt(0)
+	pairs, shift, err = CalcCreatorAttestorPairs(config, checkpoint, &types.Header{Number: big.NewInt(904)})
+	if err != nil {
+		t.Fatalf("failed to calculate pairs: %v", err)
+	}
+	if shift != 1 {
+		t.Errorf("shift mismatch: have %d, want 1", shift)
+	}
+	for i, validator := range validators {
+		if want := validators[(attestors[i]+1)%3]; pairs[validator] != want {
+			t.Errorf("shifted pair of %x mismatch: have %x, want %x", validator, pairs[validator], want)
+		}
+	}
+	// Checkpoints without enough attestors are rejected
+	checkpoint.NewAttestors = posv.EncodeAttestorsForHeader(attestors[:2])
+	if _, _, err := CalcCreatorAttestorPairs(config, checkpoint, &types.Header{Number: big.NewInt(904)}); err != ErrInsufficientAttestors {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrInsufficientAttestors)
+	}
+}
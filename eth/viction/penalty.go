@@ -0,0 +1,192 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package viction
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/posv"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// authorFn recovers the creator of a block from its header.
+type authorFn func(header *types.Header) (common.Address, error)
+
+// candidatesFn returns the validator candidates of the epoch starting at the
+// checkpoint whose penalties are calculated.
+type candidatesFn func() ([]common.Address, error)
+
+// CalcPenalties returns the list of validators penalized at the given checkpoint header.
+// After TIPSigning, validators of the finished epoch that created less than
+// ValidatorMinBlockPerEpochCount blocks are penalized, together with validators that
+// were penalized PenaltyEpochCount epochs ago and haven't signed any block in the last
+// PenaltyComebackBlockCount blocks. Before TIPSigning, validators that haven't signed any
+// block of the finished epoch are penalized. Only validators still candidates at the
+// gap block come back penalized.
+func CalcPenalties(c *posv.Posv, config *params.ChainConfig, posvConfig *params.PosvConfig, vicConfig *params.VictionConfig,
+	header *types.Header, chain consensus.ChainReader, logger log.Logger,
+) ([]common.Address, error) {
+	candidates := func() ([]common.Address, error) {
+		return c.GetValidators(vicConfig, chain.GetHeaderByNumber(header.Number.Uint64()-posvConfig.Gap), chain)
+	}
+	return calcPenalties(c.Author, candidates, config, posvConfig, vicConfig, header, chain, logger)
+}
+
+func calcPenalties(author authorFn, candidates candidatesFn, config *params.ChainConfig, posvConfig *params.PosvConfig, vicConfig *params.VictionConfig,
+	header *types.Header, chain consensus.ChainReader, logger log.Logger,
+) ([]common.Address, error) {
+	blockNumber := header.Number.Uint64()
+	if blockNumber < posvConfig.Epoch {
+		return []common.Address{}, nil
+	}
+
+	// Collect headers of the finished epoch from the newest to the previous checkpoint
+	headers := make([]*types.Header, 0, posvConfig.Epoch)
+	parentHash, parentNumber := header.ParentHash, blockNumber-1
+	for i := uint64(0); i < posvConfig.Epoch; i++ {
+		h := chain.GetHeader(parentHash, parentNumber)
+		if h == nil {
+			return nil, consensus.ErrUnknownAncestor
+		}
+		headers = append(headers, h)
+		parentHash, parentNumber = h.ParentHash, parentNumber-1
+	}
+	checkpoint := headers[len(headers)-1]
+	validators := posv.ExtractValidatorsFromCheckpointHeader(checkpoint)
+
+	if !config.IsTIPSigning(header.Number) {
+		return calcPenaltiesBySigning(config, vicConfig, headers, validators, chain), nil
+	}
+
+	// Count blocks created by each validator in the finished epoch, which the
+	// previous checkpoint doesn't belong to
+	createdBlocks := make(map[common.Address]uint64)
+	for _, h := range headers[:len(headers)-1] {
+		// The genesis block carries no seal
+		if h.Number.Sign() == 0 {
+			continue
+		}
+		creator, err := author(h)
+		if err != nil {
+			return nil, err
+		}
+		createdBlocks[creator]++
+	}
+	penalties := []common.Address{}
+	for _, validator := range validators {
+		if createdBlocks[validator] < vicConfig.ValidatorMinBlockPerEpochCount {
+			logger.Debug("Penalize validator for not creating enough blocks", "number", blockNumber, "validator", validator.Hex(), "created", createdBlocks[validator])
+			penalties = append(penalties, validator)
+		}
+	}
+
+	// Keep penalizing validators that were penalized PenaltyEpochCount epochs ago,
+	// unless they have signed blocks again in the recent comeback window.
+	comebackLength := (vicConfig.PenaltyEpochCount + 1) * posvConfig.Epoch
+	if blockNumber > comebackLength {
+		comebackHeader := chain.GetHeaderByNumber(blockNumber - comebackLength)
+		if comebackHeader == nil {
+			return nil, consensus.ErrUnknownAncestor
+		}
+		// Only candidates not penalized already can come back penalized
+		var comebacks []common.Address
+		if penalized := posv.DecodePenaltiesFromHeader(comebackHeader.Penalties); len(penalized) > 0 {
+			current, err := candidates()
+			if err != nil {
+				return nil, err
+			}
+			for _, validator := range penalized {
+				if common.IndexOf(current, validator) != -1 && common.IndexOf(penalties, validator) == -1 && common.IndexOf(comebacks, validator) == -1 {
+					comebacks = append(comebacks, validator)
+				}
+			}
+		}
+
+		comebackCount := vicConfig.PenaltyComebackBlockCount
+		if comebackCount > uint64(len(headers)) {
+			comebackCount = uint64(len(headers))
+		}
+		signableBlocks := make(map[common.Hash]bool)
+		for i := int(comebackCount) - 1; i >= 0 && len(comebacks) > 0; i-- {
+			h := headers[i]
+			if vicConfig.ValidatorSignInterval > 0 && h.Number.Uint64()%vicConfig.ValidatorSignInterval == 0 {
+				signableBlocks[h.Hash()] = true
+			}
+			block := chain.GetBlock(h.Hash(), h.Number.Uint64())
+			if block == nil {
+				continue
+			}
+			for _, signer := range blockSigners(config, vicConfig, block, signableBlocks) {
+				if idx := common.IndexOf(comebacks, signer); idx != -1 {
+					comebacks = append(comebacks[:idx], comebacks[idx+1:]...)
+				}
+			}
+		}
+		penalties = append(penalties, comebacks...)
+	}
+	return penalties, nil
+}
+
+// calcPenaltiesBySigning returns validators that haven't signed any of the blocks
+// required to be signed in the finished epoch.
+func calcPenaltiesBySigning(config *params.ChainConfig, vicConfig *params.VictionConfig, headers []*types.Header, validators []common.Address, chain consensus.ChainReader) []common.Address {
+	signableBlocks := make(map[common.Hash]bool)
+	for _, h := range headers {
+		if !config.IsTIP2019(h.Number) || (vicConfig.ValidatorSignInterval > 0 && h.Number.Uint64()%vicConfig.ValidatorSignInterval == 0) {
+			signableBlocks[h.Hash()] = true
+		}
+	}
+	penalties := append([]common.Address{}, validators...)
+	for _, h := range headers {
+		if len(penalties) == 0 {
+			break
+		}
+		block := chain.GetBlock(h.Hash(), h.Number.Uint64())
+		if block == nil {
+			continue
+		}
+		for _, signer := range blockSigners(config, vicConfig, block, signableBlocks) {
+			if idx := common.IndexOf(penalties, signer); idx != -1 {
+				penalties = append(penalties[:idx], penalties[idx+1:]...)
+			}
+		}
+	}
+	return penalties
+}
+
+// blockSigners returns senders of signing transactions in the block which sign one of the given blocks.
+func blockSigners(config *params.ChainConfig, vicConfig *params.VictionConfig, block *types.Block, signedBlocks map[common.Hash]bool) []common.Address {
+	var signers []common.Address
+	signer := types.MakeSigner(config, block.Number())
+	for _, tx := range block.Transactions() {
+		if !IsSigningTransaction(tx, vicConfig.ValidatorBlockSignContract) {
+			continue
+		}
+		txData := tx.Data()
+		if !signedBlocks[common.BytesToHash(txData[len(txData)-common.HashLength:])] {
+			continue
+		}
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			continue
+		}
+		signers = append(signers, from)
+	}
+	return signers
+}
@@ -0,0 +1,295 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package viction
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/posv"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var testBlockSignContract = common.HexToAddress("0x0000000000000000000000000000000000000089")
+
+// testChainReader implements consensus.ChainReader over a generated chain.
+type testChainReader struct {
+	config *params.ChainConfig
+	blocks []*types.Block
+	hashes map[common.Hash]*types.Block
+}
+
+func newTestChainReader(config *params.ChainConfig, genesis *types.Block, blocks []*types.Block) *testChainReader {
+	cr := &testChainReader{
+		config: config,
+		blocks: append([]*types.Block{genesis}, blocks...),
+		hashes: make(map[common.Hash]*types.Block),
+	}
+	for _, block := range cr.blocks {
+		cr.hashes[block.Hash()] = block
+	}
+	return cr
+}
+
+func (cr *testChainReader) Config() *params.ChainConfig { return cr.config }
+func (cr *testChainReader) CurrentHeader() *types.Header {
+	return cr.blocks[len(cr.blocks)-1].Header()
+}
+func (cr *testChainReader) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if block := cr.GetBlock(hash, number); block != nil {
+		return block.Header()
+	}
+	return nil
+}
+func (cr *testChainReader) GetHeaderByNumber(number uint64) *types.Header {
+	if number < uint64(len(cr.blocks)) {
+		return cr.blocks[number].Header()
+	}
+	return nil
+}
+func (cr *testChainReader) GetHeaderByHash(hash common.Hash) *types.Header {
+	if block, ok := cr.hashes[hash]; ok {
+		return block.Header()
+	}
+	return nil
+}
+func (cr *testChainReader) GetBlock(hash common.Hash, number uint64) *types.Block {
+	if block, ok := cr.hashes[hash]; ok && block.NumberU64() == number {
+		return block
+	}
+	return nil
+}
+
+// coinbaseAuthor treats the coinbase of generated blocks as their creator.
+func coinbaseAuthor(header *types.Header) (common.Address, error) {
+	return header.Coinbase, nil
+}
+
+// testSign describes a signing transaction of a validator for a block.
+type testSign struct {
+	validator int
+	block     uint64
+}
+
+func checkpointExtra(validators []common.Address) []byte {
+	extra := make([]byte, posv.ExtraVanity)
+	for _, validator := range validators {
+		extra = append(extra, validator.Bytes()...)
+	}
+	return append(extra, make([]byte, posv.ExtraSeal)...)
+}
+
+func signTx(t *testing.T, config *params.ChainConfig, key *ecdsa.PrivateKey, number uint64, hash common.Hash) *types.Transaction {
+	data := common.Hex2Bytes("e341eaa4")
+	data = append(data, common.BigToHash(new(big.Int).SetUint64(number)).Bytes()...)
+	data = append(data, hash.Bytes()...)
+	tx, err := types.SignTx(types.NewTransaction(0, testBlockSignContract, common.Big0, 200000, common.Big1, data), types.NewEIP155Signer(config.ChainID), key)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	return tx
+}
+
+func TestCalcPenalties(t *testing.T) {
+	var keys []*ecdsa.PrivateKey
+	var validators []common.Address
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, key)
+		validators = append(validators, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	roundRobin := func(number uint64) int { return int(number % 3) }
+
+	tests := []struct {
+		name       string
+		tipSigning *big.Int
+		minBlocks  uint64
+		blocks     int                     // Number of generated blocks, the last one is the checkpoint to check
+		creator    func(number uint64) int // Index of the validator creating each block
+		signs      map[uint64][]testSign   // Signing transactions included in each block
+		extras     map[uint64][]int        // Validators recorded in checkpoint blocks
+		penalties  map[uint64][]int        // Penalties recorded in checkpoint blocks
+		candidates []int                   // Candidates at the gap block, all validators if nil
+		want       []int
+	}{
+		{
+			name:    "all validators created blocks",
+			blocks:  10,
+			creator: roundRobin,
+			want:    []int{},
+		},
+		{
+			name:    "validator missed all its blocks",
+			blocks:  10,
+			creator: func(number uint64) int { return int(number % 2) },
+			want:    []int{2},
+		},
+		{
+			name:      "validator created less than the minimum",
+			minBlocks: 3,
+			blocks:    10,
+			creator: func(number uint64) int {
+				if number <= 2 {
+					return 2
+				}
+				return int(number % 2)
+			},
+			want: []int{2},
+		},
+		{
+			name:      "validator reaching the minimum only with the previous checkpoint",
+			minBlocks: 3,
+			blocks:    20,
+			creator: func(number uint64) int {
+				if number == 10 || number == 13 || number == 16 {
+					return 2
+				}
+				return int(number % 2)
+			},
+			want: []int{2},
+		},
+		{
+			name:      "penalized validator without comeback signing",
+			blocks:    30,
+			creator:   func(number uint64) int { return int(number % 2) },
+			extras:    map[uint64][]int{10: {0, 1}, 20: {0, 1}},
+			penalties: map[uint64][]int{10: {2}},
+			want:      []int{2},
+		},
+		{
+			name:      "penalized validator signed in comeback window",
+			blocks:    30,
+			creator:   func(number uint64) int { return int(number % 2) },
+			signs:     map[uint64][]testSign{27: {{validator: 2, block: 26}}},
+			extras:    map[uint64][]int{10: {0, 1}, 20: {0, 1}},
+			penalties: map[uint64][]int{10: {2}},
+			want:      []int{},
+		},
+		{
+			name:      "penalized validator signed before comeback window",
+			blocks:    30,
+			creator:   func(number uint64) int { return int(number % 2) },
+			signs:     map[uint64][]testSign{27: {{validator: 2, block: 24}}},
+			extras:    map[uint64][]int{10: {0, 1}, 20: {0, 1}},
+			penalties: map[uint64][]int{10: {2}},
+			want:      []int{2},
+		},
+		{
+			name:       "penalized validator no longer a candidate",
+			blocks:     30,
+			creator:    func(number uint64) int { return int(number % 2) },
+			extras:     map[uint64][]int{10: {0, 1}, 20: {0, 1}},
+			penalties:  map[uint64][]int{10: {2}},
+			candidates: []int{0, 1},
+			want:       []int{},
+		},
+		{
+			name:      "penalized validator penalized again",
+			blocks:    30,
+			creator:   func(number uint64) int { return int(number % 2) },
+			extras:    map[uint64][]int{10: {0, 1}},
+			penalties: map[uint64][]int{10: {2}},
+			want:      []int{2},
+		},
+		{
+			name:       "validator without signing before TIPSigning",
+			tipSigning: big.NewInt(1000),
+			blocks:     10,
+			creator:    roundRobin,
+			signs:      map[uint64][]testSign{3: {{validator: 0, block: 2}, {validator: 1, block: 2}}},
+			want:       []int{2},
+		},
+	}
+	for _, tt := range tests {
+		posvConfig := &params.PosvConfig{Period: 2, Epoch: 10, Gap: 5}
+		config := *params.TestChainConfig
+		config.Posv = posvConfig
+		config.TIP2019Block, config.TIPSigningBlock = big.NewInt(0), big.NewInt(0)
+		if tt.tipSigning != nil {
+			config.TIP2019Block, config.TIPSigningBlock = tt.tipSigning, tt.tipSigning
+		}
+		vicConfig := &params.VictionConfig{
+			PenaltyComebackBlockCount:      5,
+			PenaltyEpochCount:              1,
+			ValidatorBlockSignContract:     testBlockSignContract,
+			ValidatorMinBlockPerEpochCount: 1,
+			ValidatorSignInterval:          2,
+		}
+		if tt.minBlocks > 0 {
+			vicConfig.ValidatorMinBlockPerEpochCount = tt.minBlocks
+		}
+		db := rawdb.NewMemoryDatabase()
+		genesis := (&core.Genesis{Config: &config, ExtraData: checkpointExtra(validators)}).MustCommit(db)
+		blocks, _ := core.GenerateChain(&config, genesis, ethash.NewFaker(), db, tt.blocks, func(i int, b *core.BlockGen) {
+			number := b.Number().Uint64()
+			b.SetCoinbase(validators[tt.creator(number)])
+			if number%posvConfig.Epoch == 0 {
+				checkpointValidators := validators
+				if indexes, ok := tt.extras[number]; ok {
+					checkpointValidators = nil
+					for _, idx := range indexes {
+						checkpointValidators = append(checkpointValidators, validators[idx])
+					}
+				}
+				b.SetExtra(checkpointExtra(checkpointValidators))
+
+				var penalties []common.Address
+				for _, idx := range tt.penalties[number] {
+					penalties = append(penalties, validators[idx])
+				}
+				b.SetPenalties(posv.EncodePenaltiesForHeader(penalties))
+			}
+			for _, sign := range tt.signs[number] {
+				signed := b.PrevBlock(int(sign.block) - 1)
+				b.AddUncheckedTx(signTx(t, &config, keys[sign.validator], sign.block, signed.Hash()))
+			}
+		})
+		chain := newTestChainReader(&config, genesis, blocks)
+		header := blocks[len(blocks)-1].Header()
+
+		candidates := func() ([]common.Address, error) {
+			if tt.candidates == nil {
+				return validators, nil
+			}
+			var candidates []common.Address
+			for _, idx := range tt.candidates {
+				candidates = append(candidates, validators[idx])
+			}
+			return candidates, nil
+		}
+		penalties, err := calcPenalties(coinbaseAuthor, candidates, &config, posvConfig, vicConfig, header, chain, log.Root())
+		if err != nil {
+			t.Fatalf("%s: failed to calculate penalties: %v", tt.name, err)
+		}
+		if len(penalties) != len(tt.want) {
+			t.Fatalf("%s: penalty count mismatch: have %d, want %d", tt.name, len(penalties), len(tt.want))
+		}
+		for i, idx := range tt.want {
+			if penalties[i] != validators[idx] {
+				t.Errorf("%s: penalty %d mismatch: have %x, want %x", tt.name, i, penalties[i], validators[idx])
+			}
+		}
+	}
+}
@@ -1,11 +1,31 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
 package viction
 
 import (
+	"fmt"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/posv"
+	"github.com/ethereum/go-ethereum/contracts/validator/contract"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
@@ -13,7 +33,7 @@ import (
 )
 
 func CalcDefaultRewardPerBlock(rewardPerEpoch *big.Int, number uint64, blockPerYear uint64) *big.Int {
-	// vicConfig is passed for future extensibility, currently using hard-coded values
+	// Legacy schedule, used unless VictionConfig.RewardSchedule is set
 	// Stop reward from 8th year onwards
 	if blockPerYear*8 <= number {
 		return big.NewInt(0)
@@ -27,7 +47,8 @@ func CalcDefaultRewardPerBlock(rewardPerEpoch *big.Int, number uint64, blockPerY
 	return new(big.Int).Set(rewardPerEpoch)
 }
 
-// Return amount of reward per block of Saigon hard fork based on current block number
+// Return amount of reward per block of Saigon hard fork based on current block number,
+// used unless VictionConfig.SaigonRewardSchedule is set
 func CalcSaigonRewardPerBlock(rewardPerEpoch *big.Int, saigonBlock *big.Int, number uint64, blockPerYear uint64) *big.Int {
 	numberBig := new(big.Int).SetUint64(number)
 	yearsFromHardfork := new(big.Int).Div(new(big.Int).Sub(numberBig, saigonBlock), new(big.Int).SetUint64(blockPerYear))
@@ -40,6 +61,73 @@ func CalcSaigonRewardPerBlock(rewardPerEpoch *big.Int, saigonBlock *big.Int, num
 	return new(big.Int).Div(rewardPerEpoch, rewardHalving)
 }
 
+// CalcScheduledReward returns the reward per epoch divided by the divisor of the first
+// segment of the schedule containing the block number, or zero if there is none.
+func CalcScheduledReward(rewardPerEpoch *big.Int, schedule []params.RewardScheduleSegment, number uint64) *big.Int {
+	for _, segment := range schedule {
+		if segment.Contains(number) && segment.Divisor > 0 {
+			return new(big.Int).Div(rewardPerEpoch, new(big.Int).SetUint64(segment.Divisor))
+		}
+	}
+	return big.NewInt(0)
+}
+
+// CalcEpochReward calculates the rewards of the validators and their stakeholders for
+// the epoch ending at the checkpoint header, statedb being the state of the checkpoint
+// block before the rewards are credited.
+func CalcEpochReward(c *posv.Posv, config *params.ChainConfig, posvConfig *params.PosvConfig, vicConfig *params.VictionConfig,
+	header *types.Header, chain consensus.ChainReader, statedb *state.StateDB, logger log.Logger,
+) (*posv.EpochReward, error) {
+	epochRewards := &posv.EpochReward{}
+	blockNumber := header.Number.Uint64()
+
+	// Skip block 900 (1*epoch); first reward at block 1800 (2*epoch)
+	if blockNumber <= posvConfig.Epoch {
+		return epochRewards, nil
+	}
+
+	// Get initial reward, following the legacy halvings unless a schedule is configured
+	initialRewardPerEpoch := (*big.Int)(vicConfig.RewardPerEpoch)
+	var totalReward *big.Int
+	if len(vicConfig.RewardSchedule) > 0 {
+		totalReward = CalcScheduledReward(initialRewardPerEpoch, vicConfig.RewardSchedule, blockNumber)
+	} else {
+		totalReward = CalcDefaultRewardPerBlock(initialRewardPerEpoch, blockNumber, posvConfig.BlocksPerYear())
+	}
+
+	// Get additional reward for Saigon upgrade
+	if config.IsSaigon(header.Number) && vicConfig.SaigonRewardPerEpoch != nil {
+		saigonRewardPerEpoch := (*big.Int)(vicConfig.SaigonRewardPerEpoch)
+		var saigonReward *big.Int
+		if len(vicConfig.SaigonRewardSchedule) > 0 {
+			saigonReward = CalcScheduledReward(saigonRewardPerEpoch, vicConfig.SaigonRewardSchedule, blockNumber)
+		} else {
+			saigonReward = CalcSaigonRewardPerBlock(saigonRewardPerEpoch, config.SaigonBlock, blockNumber, posvConfig.BlocksPerYear())
+		}
+		totalReward = new(big.Int).Add(totalReward, saigonReward)
+	}
+
+	// Calculate rewards for validators and stakeholders
+	validatorRewards, err := CalcRewardsForValidators(c, config, posvConfig, vicConfig, header, totalReward, chain, logger)
+	if err != nil {
+		return nil, err
+	}
+	epochRewards.ValidatorRewards = validatorRewards
+
+	stakeholderRewards, err := CalcRewardsForStakeholders(c, config, posvConfig, vicConfig, header, validatorRewards, statedb, logger)
+	if err != nil {
+		return nil, err
+	}
+	// Credit the remainder of the division of the reward by signs according to the dust policy
+	CreditRewardDust(vicConfig, stakeholderRewards, CalcSignRewardDust(totalReward, validatorRewards))
+	epochRewards.StakholderRewards = stakeholderRewards
+
+	if err := CheckRewardInvariant(totalReward, stakeholderRewards); err != nil {
+		return epochRewards, err
+	}
+	return epochRewards, nil
+}
+
 func CalcRewardsForValidators(
 	c *posv.Posv, config *params.ChainConfig, posvConfig *params.PosvConfig, vicConfig *params.VictionConfig,
 	header *types.Header, rewardPerEpoch *big.Int, chain consensus.ChainReader, logger log.Logger,
@@ -143,6 +231,13 @@ func CalcRewardsForStakeholders(c *posv.Posv, config *params.ChainConfig, posvCo
 	rewardVoterPercent := vicConfig.RewardVoterPercent
 	rewardFoundationPercent := vicConfig.RewardFoundationPercent
 
+	// Owners, voters and caps are read through the validator contract views
+	validatorContract, err := contract.NewValidatorCaller(vicConfig.ValidatorContract, backends.NewStateBackend(config, header, statedb))
+	if err != nil {
+		return nil, err
+	}
+	opts := new(bind.CallOpts)
+
 	addBalance := func(mapping map[common.Address]*big.Int, addr common.Address, amount *big.Int) {
 		if mapping[addr] == nil {
 			mapping[addr] = amount
@@ -156,17 +251,23 @@ func CalcRewardsForStakeholders(c *posv.Posv, config *params.ChainConfig, posvCo
 			continue
 		}
 
-		// 		validatorRewardTotal := new(big.Int).Set(vr.Reward)
 		distributedTotal := new(big.Int)
 
-		owner, _ := statedb.VicGetValidatorInfo(vicConfig.ValidatorContract, validator)
+		owner, err := validatorContract.GetCandidateOwner(opts, validator)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get owner of validator %x: %v", validator, err)
+		}
 		rewardForOwner := new(big.Int).Mul(vr.Reward, new(big.Int).SetUint64(rewardValidatorPercent))
 		rewardForOwner.Div(rewardForOwner, common.Big100)
 		addBalance(stakeholderRewards, owner, rewardForOwner)
 		distributedTotal.Add(distributedTotal, rewardForOwner)
 
-		voters := statedb.VicGetValidatorVoters(vicConfig.ValidatorContract, validator)
+		voters, err := validatorContract.GetVoters(opts, validator)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get voters of validator %x: %v", validator, err)
+		}
 		voterRewardDistributed := new(big.Int)
+		votersRewarded := false
 		if len(voters) > 0 {
 			totalVoterReward := new(big.Int).Mul(vr.Reward, new(big.Int).SetUint64(rewardVoterPercent))
 			totalVoterReward.Div(totalVoterReward, common.Big100)
@@ -178,12 +279,16 @@ func CalcRewardsForStakeholders(c *posv.Posv, config *params.ChainConfig, posvCo
 				if _, ok := voterCaps[voteAddr]; ok && tip2019Block != nil && tip2019Block.Uint64() <= blockNumber {
 					continue
 				}
-				voterCap := statedb.VicGetValidatorVoterCap(vicConfig.ValidatorContract, validator, voteAddr)
+				voterCap, err := validatorContract.GetVoterCap(opts, validator, voteAddr)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get cap of voter %x: %v", voteAddr, err)
+				}
 				totalCap.Add(totalCap, voterCap)
 				voterCaps[voteAddr] = voterCap
 			}
 
 			if totalCap.Cmp(common.Big0) > 0 {
+				votersRewarded = true
 				for addr, voteCap := range voterCaps {
 					if voteCap == nil || voteCap.Sign() <= 0 {
 						continue
@@ -197,20 +302,70 @@ func CalcRewardsForStakeholders(c *posv.Posv, config *params.ChainConfig, posvCo
 		}
 		distributedTotal.Add(distributedTotal, voterRewardDistributed)
 
+		sharePercent := rewardValidatorPercent
+		if votersRewarded {
+			sharePercent += rewardVoterPercent
+		}
 		if vicConfig.RewardFoundationAddress != (common.Address{}) && rewardFoundationPercent > 0 {
 			rewardForFoundation := new(big.Int).Mul(vr.Reward, new(big.Int).SetUint64(rewardFoundationPercent))
 			rewardForFoundation.Div(rewardForFoundation, common.Big100)
 			addBalance(stakeholderRewards, vicConfig.RewardFoundationAddress, rewardForFoundation)
 			distributedTotal.Add(distributedTotal, rewardForFoundation)
+			sharePercent += rewardFoundationPercent
 		}
 
-		// if distributedTotal.Cmp(validatorRewardTotal) != 0 {
-		// 	missing := new(big.Int).Sub(validatorRewardTotal, distributedTotal)
-		// 	if missing.Cmp(big.NewInt(100)) > 0 {
-		// 		logger.Warn("CalcRewardsForStakeholders: significant reward distribution mismatch", "validator", validator.Hex(), "totalReward", validatorRewardTotal.String(), "distributed", distributedTotal.String(), "missing", missing.String())
-		// 	}
-		// }
+		// Credit the wei lost by rounding the shares down according to the dust policy
+		dust := new(big.Int).Mul(vr.Reward, new(big.Int).SetUint64(sharePercent))
+		dust.Div(dust, common.Big100)
+		dust.Sub(dust, distributedTotal)
+		CreditRewardDust(vicConfig, stakeholderRewards, dust)
 	}
 
 	return stakeholderRewards, nil
 }
+
+// CalcSignRewardDust returns the wei of the epoch reward left undistributed when dividing
+// it by the number of signs. Nothing is considered dust if no sign was rewarded at all.
+func CalcSignRewardDust(rewardPerEpoch *big.Int, validatorRewards map[common.Address]*posv.ValidatorReward) *big.Int {
+	distributed := new(big.Int)
+	for _, vr := range validatorRewards {
+		if vr != nil && vr.Reward != nil {
+			distributed.Add(distributed, vr.Reward)
+		}
+	}
+	if distributed.Sign() == 0 {
+		return distributed
+	}
+	return distributed.Sub(rewardPerEpoch, distributed)
+}
+
+// CreditRewardDust credits the reward dust to the foundation if the dust policy asks so,
+// otherwise the dust is not credited to anyone.
+func CreditRewardDust(vicConfig *params.VictionConfig, stakeholderRewards map[common.Address]*big.Int, dust *big.Int) {
+	if vicConfig.RewardDustPolicy != params.RewardDustFoundation || vicConfig.RewardFoundationAddress == (common.Address{}) {
+		return
+	}
+	if dust == nil || dust.Sign() <= 0 {
+		return
+	}
+	if reward := stakeholderRewards[vicConfig.RewardFoundationAddress]; reward != nil {
+		reward.Add(reward, dust)
+	} else {
+		stakeholderRewards[vicConfig.RewardFoundationAddress] = new(big.Int).Set(dust)
+	}
+}
+
+// CheckRewardInvariant ensures the rewards credited to stakeholders do not exceed the
+// reward of the epoch.
+func CheckRewardInvariant(rewardPerEpoch *big.Int, stakeholderRewards map[common.Address]*big.Int) error {
+	credited := new(big.Int)
+	for _, reward := range stakeholderRewards {
+		if reward != nil {
+			credited.Add(credited, reward)
+		}
+	}
+	if credited.Cmp(rewardPerEpoch) > 0 {
+		return fmt.Errorf("%w: credited %v, epoch reward %v", posv.ErrRewardInvariant, credited, rewardPerEpoch)
+	}
+	return nil
+}
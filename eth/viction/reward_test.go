@@ -0,0 +1,282 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package viction
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/posv"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	testFoundation = common.HexToAddress("0x0000000000000000000000000000000000000068")
+
+	// testValidatorCode is the code of the validator contract deployed on mainnet, which
+	// rewards are read through.
+	testValidatorCode = core.DefaultVictionGenesisBlock().Alloc[testValidatorContract].Code
+)
+
+// setValidator writes the owner, the voters and their caps of a validator into the
+// validator contract storage, following the layout of the contract.
+func setValidator(statedb *state.StateDB, validator, owner common.Address, voters []common.Address, caps []*big.Int) {
+	statedb.SetCode(testValidatorContract, testValidatorCode)
+
+	structSlot := state.StorageLocationOfMappingElement(state.StorageLocationFromSlot(1), validator.Hash().Bytes())
+	statedb.SetState(testValidatorContract, structSlot.Hash(), owner.Hash())
+
+	votersSlot := state.StorageLocationOfMappingElement(state.StorageLocationFromSlot(2), validator.Hash().Bytes())
+	statedb.SetState(testValidatorContract, votersSlot.Hash(), common.BigToHash(big.NewInt(int64(len(voters)))))
+	for i, voter := range voters {
+		elemSlot := state.StorageLocationOfDynamicArrayElement(votersSlot, uint64(i), 160)
+		statedb.SetState(testValidatorContract, elemSlot.Hash(), voter.Hash())
+
+		capsSlot := state.StorageLocationOfStructElement(structSlot, common.Big2)
+		capSlot := state.StorageLocationOfMappingElement(capsSlot, voter.Hash().Bytes())
+		statedb.SetState(testValidatorContract, capSlot.Hash(), common.BigToHash(caps[i]))
+	}
+}
+
+func sumRewards(rewards map[common.Address]*big.Int) *big.Int {
+	sum := new(big.Int)
+	for _, reward := range rewards {
+		sum.Add(sum, reward)
+	}
+	return sum
+}
+
+func TestCalcRewardsForStakeholdersDust(t *testing.T) {
+	var (
+		validator = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		owner     = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		voter1    = common.HexToAddress("0x3333333333333333333333333333333333333333")
+		voter2    = common.HexToAddress("0x4444444444444444444444444444444444444444")
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	setValidator(statedb, validator, owner, []common.Address{voter1, voter2}, []*big.Int{big.NewInt(1), big.NewInt(2)})
+
+	config := &params.ChainConfig{TIP2019Block: big.NewInt(0)}
+	header := &types.Header{Number: big.NewInt(1800)}
+	validatorRewards := map[common.Address]*posv.ValidatorReward{
+		validator: {Sign: 1, Reward: big.NewInt(1001)},
+	}
+	tests := []struct {
+		policy     string
+		foundation *big.Int
+	}{
+		// Owner gets 400, voters 166 and 333, the foundation 100 and 2 wei are lost
+		{params.RewardDustBurn, big.NewInt(100)},
+		// Lost wei are credited to the foundation
+		{params.RewardDustFoundation, big.NewInt(102)},
+	}
+	for i, tt := range tests {
+		vicConfig := &params.VictionConfig{
+			RewardDustPolicy:        tt.policy,
+			RewardFoundationAddress: testFoundation,
+			RewardFoundationPercent: 10,
+			RewardValidatorPercent:  40,
+			RewardVoterPercent:      50,
+			ValidatorContract:       testValidatorContract,
+		}
+		rewards, err := CalcRewardsForStakeholders(nil, config, nil, vicConfig, header, validatorRewards, statedb, log.Root())
+		if err != nil {
+			t.Fatalf("test %d: failed to calculate rewards: %v", i, err)
+		}
+		want := map[common.Address]*big.Int{
+			owner:          big.NewInt(400),
+			voter1:         big.NewInt(166),
+			voter2:         big.NewInt(333),
+			testFoundation: tt.foundation,
+		}
+		if len(rewards) != len(want) {
+			t.Fatalf("test %d: reward count mismatch: have %d, want %d", i, len(rewards), len(want))
+		}
+		for addr, reward := range want {
+			if rewards[addr] == nil || rewards[addr].Cmp(reward) != 0 {
+				t.Errorf("test %d: reward of %x mismatch: have %v, want %v", i, addr, rewards[addr], reward)
+			}
+		}
+	}
+}
+
+func TestCalcScheduledReward(t *testing.T) {
+	var (
+		reward       = big.NewInt(1000)
+		blockPerYear = uint64(100)
+		saigonBlock  = uint64(250)
+	)
+	// Schedules equivalent to the legacy halvings must give the same rewards
+	legacy := []params.RewardScheduleSegment{
+		{Start: 0, End: 2 * blockPerYear, Divisor: 1},
+		{Start: 2 * blockPerYear, End: 5 * blockPerYear, Divisor: 2},
+		{Start: 5 * blockPerYear, End: 8 * blockPerYear, Divisor: 4},
+	}
+	var saigon []params.RewardScheduleSegment
+	for cycle := uint64(0); cycle < 4; cycle++ {
+		saigon = append(saigon, params.RewardScheduleSegment{
+			Start:   saigonBlock + cycle*4*blockPerYear,
+			End:     saigonBlock + (cycle+1)*4*blockPerYear,
+			Divisor: 1 << cycle,
+		})
+	}
+	for number := uint64(0); number < 25*blockPerYear; number += 10 {
+		if have, want := CalcScheduledReward(reward, legacy, number), CalcDefaultRewardPerBlock(reward, number, blockPerYear); have.Cmp(want) != 0 {
+			t.Fatalf("block %d: reward mismatch: have %v, want %v", number, have, want)
+		}
+		if number < saigonBlock {
+			continue
+		}
+		if have, want := CalcScheduledReward(reward, saigon, number), CalcSaigonRewardPerBlock(reward, new(big.Int).SetUint64(saigonBlock), number, blockPerYear); have.Cmp(want) != 0 {
+			t.Fatalf("block %d: saigon reward mismatch: have %v, want %v", number, have, want)
+		}
+	}
+	// Blocks outside of any segment are not rewarded, open segments never end
+	custom := []params.RewardScheduleSegment{{Start: 100, End: 200, Divisor: 3}, {Start: 300, Divisor: 10}}
+	for number, want := range map[uint64]int64{0: 0, 100: 333, 199: 333, 200: 0, 300: 100, 1000000: 100} {
+		if have := CalcScheduledReward(reward, custom, number); have.Cmp(big.NewInt(want)) != 0 {
+			t.Errorf("block %d: reward mismatch: have %v, want %d", number, have, want)
+		}
+	}
+}
+
+func TestCalcSignRewardDust(t *testing.T) {
+	rewards := map[common.Address]*posv.ValidatorReward{
+		common.HexToAddress("0x01"): {Sign: 2, Reward: big.NewInt(666)},
+		common.HexToAddress("0x02"): {Sign: 1, Reward: big.NewInt(333)},
+	}
+	if dust := CalcSignRewardDust(big.NewInt(1000), rewards); dust.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("dust mismatch: have %v, want 1", dust)
+	}
+	// An epoch without signs has no dust, the reward is simply not distributed
+	if dust := CalcSignRewardDust(big.NewInt(1000), nil); dust.Sign() != 0 {
+		t.Errorf("dust mismatch: have %v, want 0", dust)
+	}
+}
+
+func TestCheckRewardInvariant(t *testing.T) {
+	rewards := map[common.Address]*big.Int{
+		common.HexToAddress("0x01"): big.NewInt(600),
+		common.HexToAddress("0x02"): big.NewInt(400),
+	}
+	if err := CheckRewardInvariant(big.NewInt(1000), rewards); err != nil {
+		t.Errorf("unexpected invariant error: %v", err)
+	}
+	if err := CheckRewardInvariant(big.NewInt(999), rewards); !errors.Is(err, posv.ErrRewardInvariant) {
+		t.Errorf("error mismatch: have %v, want %v", err, posv.ErrRewardInvariant)
+	}
+}
+
+// FuzzCalcRewardsForStakeholders checks over random voter caps that the credited rewards
+// never exceed the epoch reward, and that the foundation dust policy credits all of it.
+func FuzzCalcRewardsForStakeholders(f *testing.F) {
+	f.Add(int64(0), uint8(3), uint64(250000000000000000))
+	f.Add(int64(1), uint8(0), uint64(1))
+	f.Add(int64(2), uint8(7), uint64(999))
+	f.Add(int64(3), uint8(150), uint64(1000000000000000000))
+	f.Fuzz(func(t *testing.T, seed int64, voterCount uint8, rewardPerEpoch uint64) {
+		rnd := rand.New(rand.NewSource(seed))
+
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		validatorRewards := make(map[common.Address]*posv.ValidatorReward)
+		signs := uint64(0)
+		for i := 0; i < 1+rnd.Intn(4); i++ {
+			validator := common.BigToAddress(big.NewInt(int64(0x1000 + i)))
+			owner := common.BigToAddress(big.NewInt(int64(0x2000 + i)))
+
+			var (
+				voters []common.Address
+				caps   []*big.Int
+			)
+			for j := 0; j < int(voterCount%16); j++ {
+				// Some voters vote several times, some have resigned
+				voters = append(voters, common.BigToAddress(big.NewInt(int64(0x3000+rnd.Intn(8)))))
+				caps = append(caps, new(big.Int).Rand(rnd, new(big.Int).Exp(big.NewInt(10), big.NewInt(24), nil)))
+			}
+			setValidator(statedb, validator, owner, voters, caps)
+
+			sign := uint64(rnd.Intn(100))
+			validatorRewards[validator] = &posv.ValidatorReward{Sign: sign}
+			signs += sign
+		}
+		totalReward := new(big.Int).SetUint64(rewardPerEpoch)
+		for _, vr := range validatorRewards {
+			vr.Reward = new(big.Int)
+			if signs > 0 {
+				vr.Reward.Mul(new(big.Int).Div(totalReward, new(big.Int).SetUint64(signs)), new(big.Int).SetUint64(vr.Sign))
+			}
+		}
+		config := &params.ChainConfig{TIP2019Block: big.NewInt(int64(rnd.Intn(2) * 3600))}
+		header := &types.Header{Number: big.NewInt(1800)}
+
+		for _, policy := range []string{params.RewardDustBurn, params.RewardDustFoundation} {
+			vicConfig := &params.VictionConfig{
+				RewardDustPolicy:        policy,
+				RewardFoundationAddress: testFoundation,
+				RewardFoundationPercent: 10,
+				RewardValidatorPercent:  40,
+				RewardVoterPercent:      50,
+				ValidatorContract:       testValidatorContract,
+			}
+			rewards, err := CalcRewardsForStakeholders(nil, config, nil, vicConfig, header, validatorRewards, statedb, log.Root())
+			if err != nil {
+				t.Fatalf("failed to calculate rewards: %v", err)
+			}
+			CreditRewardDust(vicConfig, rewards, CalcSignRewardDust(totalReward, validatorRewards))
+
+			if err := CheckRewardInvariant(totalReward, rewards); err != nil {
+				t.Fatalf("policy %q: %v", policy, err)
+			}
+			// With voters rewarded for every validator, the foundation policy leaves no dust
+			distributed := new(big.Int)
+			for _, vr := range validatorRewards {
+				distributed.Add(distributed, vr.Reward)
+			}
+			if policy == params.RewardDustFoundation && distributed.Sign() > 0 && !hasZeroCapValidator(statedb, validatorRewards) {
+				if credited := sumRewards(rewards); credited.Cmp(totalReward) != 0 {
+					t.Fatalf("credited rewards mismatch: have %v, want %v", credited, totalReward)
+				}
+			}
+		}
+	})
+}
+
+// hasZeroCapValidator reports whether a rewarded validator has no voter cap at all,
+// in which case the voter share is not distributed and not considered dust.
+func hasZeroCapValidator(statedb *state.StateDB, validatorRewards map[common.Address]*posv.ValidatorReward) bool {
+	for validator, vr := range validatorRewards {
+		if vr.Reward.Sign() == 0 {
+			continue
+		}
+		total := new(big.Int)
+		for _, voter := range statedb.VicGetValidatorVoters(testValidatorContract, validator) {
+			total.Add(total, statedb.VicGetValidatorVoterCap(testValidatorContract, validator, voter))
+		}
+		if total.Sign() == 0 {
+			return true
+		}
+	}
+	return false
+}
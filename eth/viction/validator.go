@@ -0,0 +1,67 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package viction
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/posv"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// GetMasternodes returns all active candidates registered in the validator contract
+// together with their caps, ranked by cap in descending order. Candidates with the
+// same cap are ordered by address to keep the result deterministic.
+func GetMasternodes(vicConfig *params.VictionConfig, statedb *state.StateDB) []posv.Masternode {
+	candidates := statedb.VicGetCandidates(vicConfig.ValidatorContract)
+	masternodes := make([]posv.Masternode, 0, len(candidates))
+	seen := make(map[common.Address]bool)
+	for _, candidate := range candidates {
+		// Resigned candidates are deleted from the array, leaving an empty address behind
+		if candidate == (common.Address{}) || seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+
+		_, candidateCap := statedb.VicGetValidatorInfo(vicConfig.ValidatorContract, candidate)
+		masternodes = append(masternodes, posv.Masternode{Address: candidate, Stake: candidateCap})
+	}
+	sort.SliceStable(masternodes, func(i, j int) bool {
+		if cmp := masternodes[i].Stake.Cmp(masternodes[j].Stake); cmp != 0 {
+			return cmp > 0
+		}
+		return bytes.Compare(masternodes[i].Address[:], masternodes[j].Address[:]) < 0
+	})
+	return masternodes
+}
+
+// GetValidators returns the addresses of the top ValidatorMaxCount candidates
+// ranked by cap, which are eligible to seal blocks in the next epoch.
+func GetValidators(vicConfig *params.VictionConfig, statedb *state.StateDB) []common.Address {
+	masternodes := GetMasternodes(vicConfig, statedb)
+	if vicConfig.ValidatorMaxCount > 0 && uint64(len(masternodes)) > vicConfig.ValidatorMaxCount {
+		masternodes = masternodes[:vicConfig.ValidatorMaxCount]
+	}
+	validators := make([]common.Address, len(masternodes))
+	for i, masternode := range masternodes {
+		validators[i] = masternode.Address
+	}
+	return validators
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package viction
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var testValidatorContract = common.HexToAddress("0x0000000000000000000000000000000000000088")
+
+// setCandidates writes the candidates array and the owner and cap of each candidate
+// into the validator contract storage, following the layout of the contract.
+func setCandidates(statedb *state.StateDB, candidates []common.Address, caps []*big.Int) {
+	candidatesSlot := state.StorageLocationFromSlot(3)
+	statedb.SetState(testValidatorContract, candidatesSlot.Hash(), common.BigToHash(big.NewInt(int64(len(candidates)))))
+	for i, candidate := range candidates {
+		elemSlot := state.StorageLocationOfDynamicArrayElement(candidatesSlot, uint64(i), 160)
+		statedb.SetState(testValidatorContract, elemSlot.Hash(), candidate.Hash())
+		if candidate == (common.Address{}) {
+			continue
+		}
+		structSlot := state.StorageLocationOfMappingElement(state.StorageLocationFromSlot(1), candidate.Hash().Bytes())
+		statedb.SetState(testValidatorContract, structSlot.Hash(), candidate.Hash())
+		capSlot := state.StorageLocationOfStructElement(structSlot, common.Big1)
+		statedb.SetState(testValidatorContract, capSlot.Hash(), common.BigToHash(caps[i]))
+	}
+}
+
+func TestGetValidators(t *testing.T) {
+	var (
+		addr1 = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		addr2 = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		addr3 = common.HexToAddress("0x3333333333333333333333333333333333333333")
+		addr4 = common.HexToAddress("0x4444444444444444444444444444444444444444")
+	)
+	tests := []struct {
+		candidates []common.Address
+		caps       []*big.Int
+		maxCount   uint64
+		want       []common.Address
+	}{
+		// Candidates are ranked by cap
+		{
+			candidates: []common.Address{addr1, addr2, addr3},
+			caps:       []*big.Int{big.NewInt(10), big.NewInt(30), big.NewInt(20)},
+			maxCount:   150,
+			want:       []common.Address{addr2, addr3, addr1},
+		},
+		// Equal caps are ordered by address
+		{
+			candidates: []common.Address{addr3, addr1, addr2},
+			caps:       []*big.Int{big.NewInt(10), big.NewInt(10), big.NewInt(20)},
+			maxCount:   150,
+			want:       []common.Address{addr2, addr1, addr3},
+		},
+		// Resigned candidates are skipped
+		{
+			candidates: []common.Address{addr1, {}, addr3},
+			caps:       []*big.Int{big.NewInt(10), nil, big.NewInt(20)},
+			maxCount:   150,
+			want:       []common.Address{addr3, addr1},
+		},
+		// List is truncated to the maximum number of validators
+		{
+			candidates: []common.Address{addr1, addr2, addr3, addr4},
+			caps:       []*big.Int{big.NewInt(40), big.NewInt(30), big.NewInt(20), big.NewInt(10)},
+			maxCount:   2,
+			want:       []common.Address{addr1, addr2},
+		},
+	}
+	for i, tt := range tests {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		setCandidates(statedb, tt.candidates, tt.caps)
+
+		vicConfig := &params.VictionConfig{ValidatorContract: testValidatorContract, ValidatorMaxCount: tt.maxCount}
+		validators := GetValidators(vicConfig, statedb)
+		if len(validators) != len(tt.want) {
+			t.Fatalf("test %d: validator count mismatch: have %d, want %d", i, len(validators), len(tt.want))
+		}
+		for j := range validators {
+			if validators[j] != tt.want[j] {
+				t.Errorf("test %d: validator %d mismatch: have %x, want %x", i, j, validators[j], tt.want[j])
+			}
+		}
+	}
+}
@@ -1,7 +1,24 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
 package viction
 
 import (
 	"bytes"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -10,6 +27,9 @@ import (
 // signMethodSelector is the 4-byte function selector for sign(uint256,bytes32).
 var signMethodSelector = common.Hex2Bytes("e341eaa4")
 
+// signTransactionGas is the gas limit of sign transactions created by validators.
+const signTransactionGas = 200000
+
 // IsSigningTransaction returns true if the transaction is a block-signer
 // registration transaction to the BlockSigner contract.
 // blockSignAddr is the ValidatorBlockSignContract address from chain config.
@@ -33,3 +53,13 @@ func IsSigningTransaction(tx *types.Transaction, blockSignAddr common.Address) b
 	}
 	return true
 }
+
+// CreateSignTransaction creates an unsigned transaction calling sign(uint256,bytes32) on the
+// BlockSigner contract to record that the sender has verified the given block.
+func CreateSignTransaction(nonce uint64, blockSignAddr common.Address, blockNumber *big.Int, blockHash common.Hash) *types.Transaction {
+	data := make([]byte, 0, 68)
+	data = append(data, signMethodSelector...)
+	data = append(data, common.LeftPadBytes(blockNumber.Bytes(), common.HashLength)...)
+	data = append(data, blockHash.Bytes()...)
+	return types.NewTransaction(nonce, blockSignAddr, common.Big0, signTransactionGas, common.Big0, data)
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package viction
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCreateSignTransaction(t *testing.T) {
+	var (
+		number = big.NewInt(1800)
+		hash   = common.HexToHash("0xb10e2d527612073b26eecdfd717e6a320cf44b4afac2b0732d9fcbe2b7fa0cf6")
+	)
+	tx := CreateSignTransaction(7, testBlockSignContract, number, hash)
+	if !IsSigningTransaction(tx, testBlockSignContract) {
+		t.Fatalf("created transaction is not a signing transaction")
+	}
+	if IsSigningTransaction(tx, common.HexToAddress("0x01")) {
+		t.Fatalf("signing transaction accepted for another contract")
+	}
+	if tx.Nonce() != 7 {
+		t.Errorf("nonce mismatch: have %d, want 7", tx.Nonce())
+	}
+	data := tx.Data()
+	if have := new(big.Int).SetBytes(data[4:36]); have.Cmp(number) != 0 {
+		t.Errorf("block number mismatch: have %v, want %v", have, number)
+	}
+	if have := data[36:]; !bytes.Equal(have, hash.Bytes()) {
+		t.Errorf("block hash mismatch: have %x, want %x", have, hash)
+	}
+}
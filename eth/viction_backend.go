@@ -13,19 +13,38 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 )
 
-// [TO-DO] PosvGetAttestors returns attestors encoded as bytes for the header.
+// PosvGetAttestors returns attestor indexes of the validators introduced by the given checkpoint header,
+// derived from the randomizer contract at the state of its parent.
 func (s *Ethereum) PosvGetAttestors(vicConfig params.VictionConfig, header *types.Header, validators []common.Address) ([]int64, error) {
-	return nil, nil
+	parent := s.blockchain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	statedb, err := s.blockchain.StateAt(parent.Root)
+	if err != nil {
+		return nil, err
+	}
+	return viction.CalcAttestors(&vicConfig, statedb, validators), nil
 }
 
-// [TO-DO] PosvGetBlockSignData returns block sign transactions for the given header.
+// PosvGetBlockSignData returns block sign transactions included in the block of the given header.
 func (s *Ethereum) PosvGetBlockSignData(config *params.ChainConfig, vicConfig *params.VictionConfig, header *types.Header, chain consensus.ChainReader) []types.Transaction {
-	return []types.Transaction{}
+	signTxs := []types.Transaction{}
+	block := chain.GetBlock(header.Hash(), header.Number.Uint64())
+	if block == nil {
+		return signTxs
+	}
+	for _, tx := range block.Transactions() {
+		if viction.IsSigningTransaction(tx, vicConfig.ValidatorBlockSignContract) {
+			signTxs = append(signTxs, *tx)
+		}
+	}
+	return signTxs
 }
 
-// [TO-DO] PosvGetCreatorAttestorPairs returns creator-attestor pairs for double validation.
+// PosvGetCreatorAttestorPairs returns creator-attestor pairs for double validation.
 func (s *Ethereum) PosvGetCreatorAttestorPairs(c *posv.Posv, config *params.ChainConfig, header, checkpointHeader *types.Header) (map[common.Address]common.Address, uint64, error) {
-	return make(map[common.Address]common.Address), 0, nil
+	return viction.CalcCreatorAttestorPairs(config, checkpointHeader, header)
 }
 
 // PosvGetEpochReward calculates and distributes reward at checkpoint block.
@@ -33,39 +52,7 @@ func (s *Ethereum) PosvGetEpochReward(c *posv.Posv, config *params.ChainConfig,
 	header *types.Header,
 	chain consensus.ChainReader, statedb *state.StateDB, logger log.Logger,
 ) (*posv.EpochReward, error) {
-	epochRewards := &posv.EpochReward{}
-	blockNumber := header.Number.Uint64()
-
-	// Skip block 900 (1*epoch); first reward at block 1800 (2*epoch)
-	if blockNumber <= posvConfig.Epoch {
-		return epochRewards, nil
-	}
-
-	// Get initial reward
-	initialRewardPerEpoch := (*big.Int)(vicConfig.RewardPerEpoch)
-	totalReward := viction.CalcDefaultRewardPerBlock(initialRewardPerEpoch, blockNumber, posvConfig.BlocksPerYear())
-
-	// Get additional reward for Saigon upgrade
-	if config.IsSaigon(header.Number) && vicConfig.SaigonRewardPerEpoch != nil {
-		saigonRewardPerEpoch := (*big.Int)(vicConfig.SaigonRewardPerEpoch)
-		saigonReward := viction.CalcSaigonRewardPerBlock(saigonRewardPerEpoch, config.SaigonBlock, blockNumber, posvConfig.BlocksPerYear())
-		totalReward = new(big.Int).Add(totalReward, saigonReward)
-	}
-
-	// Calculate rewards for validators and stakeholders
-	validatorRewards, err := viction.CalcRewardsForValidators(c, config, posvConfig, vicConfig, header, totalReward, chain, logger)
-	if err != nil {
-		return nil, err
-	}
-	epochRewards.ValidatorRewards = validatorRewards
-
-	stakeholderRewards, err := viction.CalcRewardsForStakeholders(c, config, posvConfig, vicConfig, header, validatorRewards, statedb, logger)
-	if err != nil {
-		return nil, err
-	}
-	epochRewards.StakholderRewards = stakeholderRewards
-
-	return epochRewards, nil
+	return viction.CalcEpochReward(c, config, posvConfig, vicConfig, header, chain, statedb, logger)
 }
 
 // PosvAddBalanceRewards applies epoch rewards to the state by adding balances to all stakeholders.
@@ -98,12 +85,25 @@ func (s *Ethereum) PosvDistributeEpochRewards(header *types.Header, state *state
 	return nil
 }
 
-// [TO-DO] PosvGetPenalties returns list of penalized validators.
+// PosvGetPenalties returns list of validators penalized at the given checkpoint block.
 func (s *Ethereum) PosvGetPenalties(c *posv.Posv, config *params.ChainConfig, posvConfig *params.PosvConfig, vicConfig *params.VictionConfig, header *types.Header, chain consensus.ChainReader) ([]common.Address, error) {
-	return []common.Address{}, nil
+	return viction.CalcPenalties(c, config, posvConfig, vicConfig, header, chain, log.Root())
+}
+
+// PosvGetState returns the state at the given header.
+func (s *Ethereum) PosvGetState(header *types.Header) (*state.StateDB, error) {
+	return s.blockchain.StateAt(header.Root)
 }
 
-// [TO-DO] PosvGetValidators returns list of eligible validators from the state.
+// PosvGetValidators returns list of eligible validators from the validator contract
+// at the state of the given header, which is expected to be a gap block.
 func (s *Ethereum) PosvGetValidators(vicConfig *params.VictionConfig, header *types.Header, chain consensus.ChainReader) ([]common.Address, error) {
-	return nil, nil
+	if header == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	statedb, err := s.blockchain.StateAt(header.Root)
+	if err != nil {
+		return nil, err
+	}
+	return viction.GetValidators(vicConfig, statedb), nil
 }
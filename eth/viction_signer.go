@@ -0,0 +1,201 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/posv"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/viction"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// signerChainHeadChanSize is the size of channel listening to ChainHeadEvent.
+const signerChainHeadChanSize = 10
+
+// blockSigner emits a sign transaction for every ValidatorSignInterval-th block of the
+// canonical chain using the etherbase account, as long as it is a validator of the epoch.
+//
+// The signer keeps track of the nonce of its last transaction, so that a sign transaction
+// is never emitted with a nonce already taken by a transaction it created before, even if
+// the pool has not yet reflected it (e.g. it was replaced by a user transaction).
+type blockSigner struct {
+	eth *Ethereum
+
+	account common.Address // Account the tracked nonce belongs to
+	nonce   uint64         // Next nonce to use for the account
+	head    *types.Header  // Last processed chain head
+
+	chainHeadCh  chan core.ChainHeadEvent
+	chainHeadSub event.Subscription
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newBlockSigner(eth *Ethereum) *blockSigner {
+	return &blockSigner{
+		eth:         eth,
+		chainHeadCh: make(chan core.ChainHeadEvent, signerChainHeadChanSize),
+		quit:        make(chan struct{}),
+	}
+}
+
+// start subscribes to chain head events and starts emitting sign transactions.
+func (s *blockSigner) start() {
+	s.head = s.eth.blockchain.CurrentBlock().Header()
+	s.chainHeadSub = s.eth.blockchain.SubscribeChainHeadEvent(s.chainHeadCh)
+
+	s.wg.Add(1)
+	go s.loop()
+}
+
+// stop terminates the signer and waits for the emission loop to return.
+func (s *blockSigner) stop() {
+	s.chainHeadSub.Unsubscribe()
+	close(s.quit)
+	s.wg.Wait()
+}
+
+func (s *blockSigner) loop() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case ev := <-s.chainHeadCh:
+			s.handleHead(ev.Block)
+		case <-s.chainHeadSub.Err():
+			return
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// handleHead signs every block due to be signed between the previous and the new head.
+// If the chain was reorganised, the blocks of the new canonical chain are signed from
+// the common ancestor of the two heads on, as the ones signed before were dropped.
+func (s *blockSigner) handleHead(head *types.Block) {
+	var from uint64
+	if ancestor := canonicalAncestor(s.eth.blockchain, s.head); ancestor != nil {
+		from = ancestor.Number.Uint64() + 1
+	} else {
+		from = head.NumberU64()
+	}
+	to := head.NumberU64()
+	s.head = head.Header()
+
+	// Blocks imported while syncing are not worth signing anymore
+	if !s.eth.Synced() || from > to {
+		return
+	}
+	etherbase, err := s.eth.Etherbase()
+	if err != nil {
+		return
+	}
+	config := s.eth.blockchain.Config()
+	vicConfig := config.Viction
+	if !s.isValidator(etherbase, head.Header()) {
+		return
+	}
+	for number := from; number <= to; number++ {
+		block := s.eth.blockchain.GetBlockByNumber(number)
+		if block == nil {
+			continue
+		}
+		if config.IsTIP2019(block.Number()) && number%vicConfig.ValidatorSignInterval != 0 {
+			continue
+		}
+		if err := s.sign(etherbase, block); err != nil {
+			log.Warn("Failed to emit block sign transaction", "number", number, "hash", block.Hash(), "err", err)
+		}
+	}
+}
+
+// canonicalAncestor returns the most recent ancestor of the header, itself included,
+// which is part of the canonical chain, or nil if the header is unknown.
+func canonicalAncestor(chain consensus.ChainHeaderReader, header *types.Header) *types.Header {
+	for header != nil {
+		if canon := chain.GetHeaderByNumber(header.Number.Uint64()); canon != nil && canon.Hash() == header.Hash() {
+			return header
+		}
+		if header.Number.Sign() == 0 {
+			return nil
+		}
+		header = chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	}
+	return nil
+}
+
+// isValidator checks whether the account is recorded as a validator in the checkpoint
+// block of the epoch the given header belongs to.
+func (s *blockSigner) isValidator(account common.Address, header *types.Header) bool {
+	checkpoint := posv.GetCheckpointHeader(s.eth.blockchain.Config().Posv, header, s.eth.blockchain)
+	if checkpoint == nil {
+		return false
+	}
+	for _, validator := range posv.ExtractValidatorsFromCheckpointHeader(checkpoint) {
+		if validator == account {
+			return true
+		}
+	}
+	return false
+}
+
+// sign creates a sign transaction for the block, signs it with the account and injects
+// it into the local transaction pool.
+func (s *blockSigner) sign(account common.Address, block *types.Block) error {
+	wallet, err := s.eth.accountManager.Find(accounts.Account{Address: account})
+	if err != nil {
+		return err
+	}
+	config := s.eth.blockchain.Config()
+
+	nonce := s.nextNonce(account)
+	tx := viction.CreateSignTransaction(nonce, config.Viction.ValidatorBlockSignContract, block.Number(), block.Hash())
+	signed, err := wallet.SignTx(accounts.Account{Address: account}, tx, config.ChainID)
+	if err != nil {
+		return err
+	}
+	if err := s.eth.txPool.AddLocal(signed); err != nil {
+		// The tracked nonce is stale if the chain moved past it, drop it
+		if errors.Is(err, core.ErrNonceTooLow) {
+			s.account = common.Address{}
+		}
+		return err
+	}
+	s.nonce = nonce + 1
+	log.Debug("Emitted block sign transaction", "number", block.Number(), "hash", block.Hash(), "nonce", nonce)
+	return nil
+}
+
+// nextNonce returns the nonce for the next sign transaction of the account, which is
+// the highest of the pending nonce in the pool and the nonce tracked by the signer.
+func (s *blockSigner) nextNonce(account common.Address) uint64 {
+	poolNonce := s.eth.txPool.Nonce(account)
+	if s.account != account || s.nonce < poolNonce {
+		s.account, s.nonce = account, poolNonce
+	}
+	return s.nonce
+}
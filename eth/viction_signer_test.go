@@ -0,0 +1,296 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"crypto/ecdsa"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/posv"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the signer resumes from the common ancestor of its last processed head
+// and the new canonical chain, even if the reorg didn't increase the chain height.
+func TestCanonicalAncestor(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		gspec   = &core.Genesis{Config: params.TestChainConfig}
+		genesis = gspec.MustCommit(db)
+		engine  = ethash.NewFaker()
+	)
+	chain, err := core.NewBlockChain(db, nil, gspec.Config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, engine, db, 10, nil)
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	head := chain.CurrentBlock().Header()
+	if ancestor := canonicalAncestor(chain, head); ancestor == nil || ancestor.Hash() != head.Hash() {
+		t.Fatalf("canonical head ancestor mismatch: have %v, want %v", ancestor, head.Number)
+	}
+	// Reorg to a sidechain of the same height, forking off block 6 with a heavier tail
+	fork, _ := core.GenerateChain(gspec.Config, blocks[5], engine, db, 4, func(i int, gen *core.BlockGen) {
+		gen.SetCoinbase(common.Address{0x01})
+		gen.OffsetTime(-9)
+	})
+	if _, err := chain.InsertChain(fork); err != nil {
+		t.Fatalf("failed to insert fork: %v", err)
+	}
+	if chain.CurrentBlock().Hash() != fork[len(fork)-1].Hash() {
+		t.Fatalf("fork did not become canonical")
+	}
+	if ancestor := canonicalAncestor(chain, head); ancestor == nil || ancestor.Hash() != blocks[5].Hash() {
+		t.Fatalf("reorged head ancestor mismatch: have %v, want %v", ancestor, blocks[5].Number())
+	}
+}
+
+// signerTester is a validator node emitting block sign transactions, backed by
+// a blockchain the tests feed chain head events into by inserting blocks.
+type signerTester struct {
+	eth     *Ethereum
+	db      ethdb.Database
+	genesis *types.Block
+	key     *ecdsa.PrivateKey
+	account common.Address
+
+	txsCh chan core.NewTxsEvent
+}
+
+// newSignerTester creates a chain whose genesis checkpoint lists the validator
+// account and starts a block signer on it.
+func newSignerTester(t *testing.T) *signerTester {
+	dir, err := ioutil.TempDir("", "signer-test")
+	if err != nil {
+		t.Fatalf("failed to create keystore dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	key, _ := crypto.GenerateKey()
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(key, "")
+	if err != nil {
+		t.Fatalf("failed to import key: %v", err)
+	}
+	if err := ks.Unlock(account, ""); err != nil {
+		t.Fatalf("failed to unlock account: %v", err)
+	}
+	config := *params.AllEthashProtocolChanges
+	config.TIP2019Block = big.NewInt(0)
+	config.Posv = &params.PosvConfig{Period: 2, Epoch: 30, Gap: 5}
+	config.Viction = &params.VictionConfig{
+		ValidatorSignInterval:      3,
+		ValidatorBlockSignContract: common.HexToAddress("0x0000000000000000000000000000000000000089"),
+	}
+	extra := make([]byte, posv.ExtraVanity+common.AddressLength+posv.ExtraSeal)
+	copy(extra[posv.ExtraVanity:], account.Address.Bytes())
+
+	db := rawdb.NewMemoryDatabase()
+	gspec := &core.Genesis{
+		Config:    &config,
+		ExtraData: extra,
+		Alloc:     core.GenesisAlloc{account.Address: {Balance: big.NewInt(params.Ether)}},
+	}
+	genesis := gspec.MustCommit(db)
+
+	chain, err := core.NewBlockChain(db, nil, &config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	t.Cleanup(chain.Stop)
+
+	poolConfig := core.DefaultTxPoolConfig
+	poolConfig.Journal = ""
+	pool := core.NewTxPool(poolConfig, &config, chain)
+	t.Cleanup(pool.Stop)
+
+	st := &signerTester{
+		eth: &Ethereum{
+			blockchain:      chain,
+			txPool:          pool,
+			accountManager:  accounts.NewManager(&accounts.Config{InsecureUnlockAllowed: true}, ks),
+			etherbase:       account.Address,
+			protocolManager: &ProtocolManager{acceptTxs: 1},
+		},
+		db:      db,
+		genesis: genesis,
+		key:     key,
+		account: account.Address,
+		txsCh:   make(chan core.NewTxsEvent, 16),
+	}
+	sub := pool.SubscribeNewTxsEvent(st.txsCh)
+	t.Cleanup(sub.Unsubscribe)
+
+	signer := newBlockSigner(st.eth)
+	signer.start()
+	t.Cleanup(signer.stop)
+
+	return st
+}
+
+// generate creates a chain of n blocks on top of the parent.
+func (st *signerTester) generate(parent *types.Block, n int, gen func(int, *core.BlockGen)) []*types.Block {
+	blocks, _ := core.GenerateChain(st.eth.blockchain.Config(), parent, ethash.NewFaker(), st.db, n, gen)
+	return blocks
+}
+
+// insert imports the blocks, emitting a chain head event for the last one.
+func (st *signerTester) insert(t *testing.T, blocks []*types.Block) {
+	t.Helper()
+	if _, err := st.eth.blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+}
+
+// transact adds a transaction of the validator account into the pool.
+func (st *signerTester) transact(t *testing.T, nonce uint64, gasPrice int64) {
+	t.Helper()
+	signer := types.NewEIP155Signer(st.eth.blockchain.Config().ChainID)
+	tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{0x01}, big.NewInt(1), params.TxGas, big.NewInt(gasPrice), nil), signer, st.key)
+	if err := st.eth.txPool.AddLocal(tx); err != nil {
+		t.Fatalf("failed to add transaction %d: %v", nonce, err)
+	}
+}
+
+// signedBlock is a block a sign transaction was emitted for.
+type signedBlock struct {
+	number uint64
+	hash   common.Hash
+	nonce  uint64
+}
+
+// expect waits for sign transactions for the given blocks, in order, and checks
+// that no others were emitted.
+func (st *signerTester) expect(t *testing.T, want []signedBlock) {
+	t.Helper()
+
+	contract := st.eth.blockchain.Config().Viction.ValidatorBlockSignContract
+	timeout := time.After(5 * time.Second)
+
+	var have []signedBlock
+	for done := false; !done; {
+		select {
+		case ev := <-st.txsCh:
+			for _, tx := range ev.Txs {
+				if tx.To() == nil || *tx.To() != contract {
+					continue
+				}
+				have = append(have, signedBlock{
+					number: new(big.Int).SetBytes(tx.Data()[4:36]).Uint64(),
+					hash:   common.BytesToHash(tx.Data()[36:68]),
+					nonce:  tx.Nonce(),
+				})
+			}
+		case <-time.After(100 * time.Millisecond):
+			// Quiet for a while, done if everything arrived
+			done = len(have) >= len(want)
+		case <-timeout:
+			done = true
+		}
+	}
+	if len(have) != len(want) {
+		t.Fatalf("sign transactions mismatch: have %v, want %v", have, want)
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			t.Errorf("sign transaction %d mismatch: have %v, want %v", i, have[i], want[i])
+		}
+	}
+}
+
+// Tests that a validator signs every ValidatorSignInterval-th block of the chain
+// it imports, with consecutive nonces.
+func TestBlockSignerInterval(t *testing.T) {
+	st := newSignerTester(t)
+	blocks := st.generate(st.genesis, 7, nil)
+
+	st.insert(t, blocks[:2])
+	st.expect(t, nil)
+
+	st.insert(t, blocks[2:])
+	st.expect(t, []signedBlock{
+		{3, blocks[2].Hash(), 0},
+		{6, blocks[5].Hash(), 1},
+	})
+}
+
+// Tests that sign transactions take the nonce after the pending transactions the
+// validator account sent itself, and never reuse the nonce of a sign transaction
+// a user transaction replaced.
+func TestBlockSignerNonce(t *testing.T) {
+	st := newSignerTester(t)
+	blocks := st.generate(st.genesis, 9, nil)
+
+	// A pending user transaction is ahead of the signer
+	st.transact(t, 0, 1)
+	st.insert(t, blocks[:3])
+	st.expect(t, []signedBlock{{3, blocks[2].Hash(), 1}})
+
+	// The user replaces the sign transaction, which is not emitted again
+	st.transact(t, 1, 2)
+	st.insert(t, blocks[3:6])
+	st.expect(t, []signedBlock{{6, blocks[5].Hash(), 2}})
+
+	// More user transactions move the signer past them
+	st.transact(t, 3, 1)
+	st.transact(t, 4, 1)
+	st.insert(t, blocks[6:])
+	st.expect(t, []signedBlock{{9, blocks[8].Hash(), 5}})
+}
+
+// Tests that after a reorg the validator signs the blocks of the new canonical
+// chain from the common ancestor on, with fresh nonces.
+func TestBlockSignerReorg(t *testing.T) {
+	st := newSignerTester(t)
+	blocks := st.generate(st.genesis, 7, nil)
+
+	st.insert(t, blocks)
+	st.expect(t, []signedBlock{
+		{3, blocks[2].Hash(), 0},
+		{6, blocks[5].Hash(), 1},
+	})
+	// Reorg to a longer sidechain forking off block 4
+	fork := st.generate(blocks[3], 5, func(i int, gen *core.BlockGen) {
+		gen.SetCoinbase(common.Address{0x01})
+	})
+	st.insert(t, fork)
+	if st.eth.blockchain.CurrentBlock().Hash() != fork[len(fork)-1].Hash() {
+		t.Fatalf("fork did not become canonical")
+	}
+	st.expect(t, []signedBlock{
+		{6, fork[1].Hash(), 2},
+		{9, fork[4].Hash(), 3},
+	})
+}
@@ -108,6 +108,14 @@ const schema string = `
         # this transaction. If the transaction has not yet been mined, this field
         # will be null.
         cumulativeGasUsed: Long
+        # Payer is the account the gas of this transaction was bought from: the
+        # VRC25 contract if it sponsored the transaction, the sender otherwise. If
+        # the transaction has not yet been mined, this field will be null.
+        payer(block: Long): Account
+        # SponsoredFee is the fee paid by the VRC25 contract on behalf of the
+        # sender. If the transaction was not sponsored, or it has not yet been
+        # mined, this field will be null.
+        sponsoredFee: BigInt
         # CreatedContract is the account that was created by a contract creation
         # transaction. If the transaction was not a contract creation transaction,
         # or it has not yet been mined, this field will be null.
@@ -1066,10 +1066,12 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs, bl
 // while replaying a transaction in debug mode as well as transaction
 // execution status, the amount of gas used and the return value
 type ExecutionResult struct {
-	Gas         uint64         `json:"gas"`
-	Failed      bool           `json:"failed"`
-	ReturnValue string         `json:"returnValue"`
-	StructLogs  []StructLogRes `json:"structLogs"`
+	Gas          uint64          `json:"gas"`
+	Failed       bool            `json:"failed"`
+	ReturnValue  string          `json:"returnValue"`
+	StructLogs   []StructLogRes  `json:"structLogs"`
+	Payer        *common.Address `json:"payer,omitempty"`        // VRC25 contract, if it paid the gas
+	SponsoredFee *hexutil.Big    `json:"sponsoredFee,omitempty"` // Fee paid by the VRC25 contract
 }
 
 // StructLogRes stores a structured log emitted by the EVM while replaying a
@@ -1450,6 +1452,13 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	if receipt.ContractAddress != (common.Address{}) {
 		fields["contractAddress"] = receipt.ContractAddress
 	}
+	// Report the VRC25 contract as payer if it sponsored the gas of the transaction
+	fields["payer"] = from
+	fields["sponsoredFee"] = nil
+	if receipt.Sponsored() {
+		fields["payer"] = receipt.Payer
+		fields["sponsoredFee"] = (*hexutil.Big)(receipt.SponsoredFee)
+	}
 	return fields, nil
 }
 
@@ -1560,6 +1569,21 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 	if err := checkTxFee(tx.GasPrice(), tx.Gas(), b.RPCTxFeeCap()); err != nil {
 		return common.Hash{}, err
 	}
+	// Reject transactions involving blacklisted addresses before handing them to the pool
+	if config := b.ChainConfig(); config.Viction != nil {
+		state, head, err := b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+		if state == nil || err != nil {
+			return common.Hash{}, err
+		}
+		from, err := types.Sender(types.MakeSigner(config, head.Number), tx)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		next := new(big.Int).Add(head.Number, common.Big1)
+		if err := core.ValidateBlacklist(core.NewBlacklistProvider(config), next, state, from, tx.To()); err != nil {
+			return common.Hash{}, err
+		}
+	}
 	if err := b.SendTx(ctx, tx); err != nil {
 		return common.Hash{}, err
 	}
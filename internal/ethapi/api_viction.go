@@ -0,0 +1,96 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vrc25"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// VRC25FeeResult describes who pays the fee of a transaction on a Viction chain, and
+// what it costs the VRC25 sponsor and the sender.
+type VRC25FeeResult struct {
+	Gas         hexutil.Uint64 `json:"gas"`         // Gas limit the sponsorship is checked against
+	Sponsored   bool           `json:"sponsored"`   // Whether the VRC25 contract pays the gas
+	Payer       common.Address `json:"payer"`       // Account the gas is bought from
+	Fee         *hexutil.Big   `json:"fee"`         // Cost of the gas limit at the VRC25 gas price
+	FeeCapacity *hexutil.Big   `json:"feeCapacity"` // Remaining fee capacity of the recipient token
+	MinFee      *hexutil.Big   `json:"minFee"`      // Minimum fee of the recipient token
+	TokenFee    *hexutil.Big   `json:"tokenFee"`    // Tokens charged to the sender by PayFeeWithVRC25
+}
+
+// EstimateVRC25Fee estimates the gas of the given transaction against the current
+// pending block, and reports whether its recipient token sponsors it through the
+// VRC25 contract. If a gas limit is specified it is used instead of the estimate,
+// since sponsorship is granted on the full gas limit of a transaction.
+func (s *PublicBlockChainAPI) EstimateVRC25Fee(ctx context.Context, args CallArgs, blockNrOrHash *rpc.BlockNumberOrHash) (*VRC25FeeResult, error) {
+	config := s.b.ChainConfig()
+	if config.Viction == nil {
+		return nil, errors.New("VRC25 fees are only available on Viction chains")
+	}
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	if args.From == nil {
+		args.From = new(common.Address)
+	}
+	gas := args.Gas
+	if gas == nil {
+		estimate, err := DoEstimateGas(ctx, s.b, args, bNrOrHash, s.b.RPCGasCap())
+		if err != nil {
+			return nil, err
+		}
+		gas = &estimate
+	}
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, bNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	fee := new(big.Int).SetUint64(uint64(*gas))
+	if price := config.Viction.VRC25GasPrice; price != nil {
+		fee.Mul(fee, (*big.Int)(price))
+	}
+	result := &VRC25FeeResult{
+		Gas:         *gas,
+		Payer:       *args.From,
+		Fee:         (*hexutil.Big)(fee),
+		FeeCapacity: new(hexutil.Big),
+		MinFee:      new(hexutil.Big),
+		TokenFee:    new(hexutil.Big),
+	}
+	if args.To == nil {
+		return result, state.Error()
+	}
+	// Mirror the sponsorship check done when the gas is bought
+	capacity := vrc25.NewRegistry(state, config.Viction.VRC25Contract).Capacity(*args.To)
+	if capacity.Cmp(fee) >= 0 {
+		result.Sponsored = true
+		result.Payer = config.Viction.VRC25Contract
+	}
+	result.FeeCapacity = (*hexutil.Big)(capacity)
+	token := vrc25.NewToken(state, *args.To)
+	result.MinFee = (*hexutil.Big)(token.MinFee())
+	result.TokenFee = (*hexutil.Big)(token.Fee(*args.From))
+	return result, state.Error()
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vrc25"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// vrc25Backend is a Backend serving a single state, enough to estimate and call
+// transactions against it.
+type vrc25Backend struct {
+	Backend
+	config *params.ChainConfig
+	state  *state.StateDB
+	header *types.Header
+}
+
+func (b *vrc25Backend) ChainConfig() *params.ChainConfig { return b.config }
+func (b *vrc25Backend) RPCGasCap() uint64                { return 0 }
+
+func (b *vrc25Backend) BlockByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Block, error) {
+	return types.NewBlockWithHeader(b.header), nil
+}
+
+func (b *vrc25Backend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	return b.state.Copy(), b.header, nil
+}
+
+func (b *vrc25Backend) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header) (*vm.EVM, func() error, error) {
+	context := core.NewEVMBlockContext(header, nil, &header.Coinbase)
+	return vm.NewEVM(context, core.NewEVMTxContext(msg), state, b.config, vm.Config{}), func() error { return nil }, nil
+}
+
+// Tests that the VRC25 fee estimate reports the sponsor of a transaction, and the
+// tokens charged to its sender.
+func TestEstimateVRC25Fee(t *testing.T) {
+	var (
+		contract  = common.HexToAddress("0x8c0faeb5c6bed2129b8674f262fd45c4e9468bee")
+		sponsored = common.HexToAddress("0x2000000000000000000000000000000000000001")
+		plain     = common.HexToAddress("0x2000000000000000000000000000000000000002")
+		issuer    = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		sender    = common.HexToAddress("0x3000000000000000000000000000000000000001")
+		gasPrice  = big.NewInt(250000000)
+	)
+	config := *params.AllEthashProtocolChanges
+	config.Viction = &params.VictionConfig{
+		VRC25Contract: contract,
+		VRC25GasPrice: (*math.Decimal256)(gasPrice),
+	}
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetBalance(contract, big.NewInt(params.Ether))
+
+	// Sponsor a token with enough capacity for a transfer, charging a minimum fee
+	// larger than the balance of the sender
+	vrc25.NewRegistry(statedb, contract).SetCapacity(sponsored, new(big.Int).Mul(big.NewInt(50000), gasPrice))
+	statedb.SetState(sponsored, state.GetStorageKeyForSlot(1), common.BigToHash(big.NewInt(100)))
+	statedb.SetState(sponsored, state.GetStorageKeyForSlot(2), issuer.Hash())
+	vrc25.NewToken(statedb, sponsored).SetBalance(sender, big.NewInt(30))
+
+	api := NewPublicBlockChainAPI(&vrc25Backend{
+		config: &config,
+		state:  statedb,
+		header: &types.Header{Number: big.NewInt(1), GasLimit: 8000000, Difficulty: big.NewInt(1)},
+	})
+	tests := []struct {
+		to        *common.Address
+		gas       *hexutil.Uint64
+		wantGas   uint64
+		sponsored bool
+		capacity  int64
+		minFee    int64
+		tokenFee  int64
+	}{
+		// Estimated transfer within the capacity of the token
+		{to: &sponsored, wantGas: params.TxGas, sponsored: true, capacity: 50000 * 250000000, minFee: 100, tokenFee: 30},
+		// Explicit gas limit within the capacity of the token
+		{to: &sponsored, gas: newUint64(50000), wantGas: 50000, sponsored: true, capacity: 50000 * 250000000, minFee: 100, tokenFee: 30},
+		// Explicit gas limit beyond the capacity of the token
+		{to: &sponsored, gas: newUint64(50001), wantGas: 50001, capacity: 50000 * 250000000, minFee: 100, tokenFee: 30},
+		// Recipient not registered in the VRC25 contract
+		{to: &plain, wantGas: params.TxGas},
+		// Contract creation without a recipient token
+		{gas: newUint64(60000), wantGas: 60000},
+	}
+	for i, tt := range tests {
+		from := sender
+		res, err := api.EstimateVRC25Fee(context.Background(), CallArgs{From: &from, To: tt.to, Gas: tt.gas}, nil)
+		if err != nil {
+			t.Fatalf("test %d: failed to estimate fee: %v", i, err)
+		}
+		payer := sender
+		if tt.sponsored {
+			payer = contract
+		}
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(tt.wantGas), gasPrice)
+		switch {
+		case uint64(res.Gas) != tt.wantGas:
+			t.Errorf("test %d: gas mismatch: have %d, want %d", i, res.Gas, tt.wantGas)
+		case res.Sponsored != tt.sponsored || res.Payer != payer:
+			t.Errorf("test %d: sponsorship mismatch: have %v %x, want %v %x", i, res.Sponsored, res.Payer, tt.sponsored, payer)
+		case res.Fee.ToInt().Cmp(fee) != 0:
+			t.Errorf("test %d: fee mismatch: have %v, want %v", i, res.Fee, fee)
+		case res.FeeCapacity.ToInt().Int64() != tt.capacity:
+			t.Errorf("test %d: fee capacity mismatch: have %v, want %d", i, res.FeeCapacity, tt.capacity)
+		case res.MinFee.ToInt().Int64() != tt.minFee || res.TokenFee.ToInt().Int64() != tt.tokenFee:
+			t.Errorf("test %d: token fee mismatch: have %v/%v, want %d/%d", i, res.TokenFee, res.MinFee, tt.tokenFee, tt.minFee)
+		}
+	}
+}
+
+func newUint64(n uint64) *hexutil.Uint64 {
+	return (*hexutil.Uint64)(&n)
+}
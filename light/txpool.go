@@ -69,6 +69,9 @@ type TxPool struct {
 	clearIdx     uint64                               // earliest block nr that can contain mined tx info
 
 	istanbul bool // Fork indicator whether we are in the istanbul stage.
+
+	blacklist     core.BlacklistProvider // Source of the addresses not allowed to transact
+	pendingNumber *big.Int               // Number of the next block, against which the blacklist is checked
 }
 
 // TxRelayBackend provides an interface to the mechanism that forwards transacions
@@ -91,6 +94,7 @@ func NewTxPool(config *params.ChainConfig, chain *LightChain, relay TxRelayBacke
 	pool := &TxPool{
 		config:      config,
 		signer:      types.NewEIP155Signer(config.ChainID),
+		blacklist:   core.NewBlacklistProvider(config),
 		nonce:       make(map[common.Address]uint64),
 		pending:     make(map[common.Hash]*types.Transaction),
 		mined:       make(map[common.Hash][]*types.Transaction),
@@ -314,6 +318,7 @@ func (pool *TxPool) setNewHead(head *types.Header) {
 	// Update fork indicator by next pending block number
 	next := new(big.Int).Add(head.Number, big.NewInt(1))
 	pool.istanbul = pool.config.IsIstanbul(next)
+	pool.pendingNumber = next
 }
 
 // Stop stops the light transaction pool
@@ -354,8 +359,15 @@ func (pool *TxPool) validateTx(ctx context.Context, tx *types.Transaction) error
 	if from, err = types.Sender(pool.signer, tx); err != nil {
 		return core.ErrInvalidSender
 	}
-	// Last but not least check for nonce errors
 	currentState := pool.currentState(ctx)
+
+	// Reject transactions from or to blacklisted addresses
+	if pool.pendingNumber != nil {
+		if err := core.ValidateBlacklist(pool.blacklist, pool.pendingNumber, currentState, from, tx.To()); err != nil {
+			return err
+		}
+	}
+	// Last but not least check for nonce errors
 	if n := currentState.GetNonce(from); n > tx.Nonce() {
 		return core.ErrNonceTooLow
 	}
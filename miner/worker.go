@@ -128,6 +128,7 @@ type worker struct {
 	engine      consensus.Engine
 	eth         Backend
 	chain       *core.BlockChain
+	blacklist   core.BlacklistProvider
 
 	// Feeds
 	pendingLogsFeed event.Feed
@@ -195,6 +196,7 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 		eth:                eth,
 		mux:                mux,
 		chain:              eth.BlockChain(),
+		blacklist:          core.NewBlacklistProvider(chainConfig),
 		isLocalBlock:       isLocalBlock,
 		localUncles:        make(map[common.Hash]*types.Block),
 		remoteUncles:       make(map[common.Hash]*types.Block),
@@ -789,6 +791,14 @@ func (w *worker) commitTransactions(txs *types.TransactionsByPriceAndNonce, coin
 			txs.Pop()
 			continue
 		}
+		// Never include transactions involving blacklisted addresses, even if they made it
+		// into the pool before the blacklist was enforced.
+		if err := core.ValidateBlacklist(w.blacklist, w.current.header.Number, w.current.state, from, tx.To()); err != nil {
+			log.Trace("Ignoring blacklisted transaction", "hash", tx.Hash(), "sender", from, "recipient", tx.To())
+
+			txs.Pop()
+			continue
+		}
 		// Start executing the transaction
 		w.current.state.Prepare(tx.Hash(), common.Hash{}, w.current.tcount)
 
@@ -905,9 +915,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 	}
 	// Create the current work task and check any fork transitions needed
 	env := w.current
-	if w.chainConfig.DAOForkSupport && w.chainConfig.DAOForkBlock != nil && w.chainConfig.DAOForkBlock.Cmp(header.Number) == 0 {
-		misc.ApplyDAOHardFork(env.state)
-	}
+	misc.ApplyTransitions(w.chainConfig, env.state, header.Number)
 	// Accumulate the uncles for the current block
 	uncles := make([]*types.Header, 0, 2)
 	commitUncles := func(blocks map[common.Hash]*types.Block) {
@@ -17,6 +17,7 @@
 package miner
 
 import (
+	"errors"
 	"math/big"
 	"math/rand"
 	"sync/atomic"
@@ -28,13 +29,16 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/posv"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -501,3 +505,111 @@ func testAdjustInterval(t *testing.T, chainConfig *params.ChainConfig, engine co
 		t.Error("interval reset timeout")
 	}
 }
+
+// Tests that transactions involving blacklisted addresses are neither accepted by the
+// pool nor included by the miner once the blacklist is enforced.
+func TestBlacklistedTransactionNotMined(t *testing.T) {
+	config := *params.TestChainConfig
+	config.Posv = &params.PosvConfig{Epoch: 900}
+	config.Viction = &params.VictionConfig{VRC25GasPrice: params.VictionChainConfig.Viction.VRC25GasPrice}
+	config.TIPBlacklistBlock = big.NewInt(0)
+
+	engine := ethash.NewFaker()
+	defer engine.Close()
+	b := newTestWorkerBackend(t, &config, engine, rawdb.NewMemoryDatabase(), 0)
+	defer b.chain.Stop()
+	defer b.txPool.Stop()
+
+	blacklisted := common.HexToAddress("0x5248bfb72fd4f234e062d3e9bb76f08643004fcd")
+	tx, _ := types.SignTx(types.NewTransaction(0, blacklisted, big.NewInt(1000), params.TxGas, nil, nil), types.HomesteadSigner{}, testBankKey)
+	if err := b.txPool.AddLocal(tx); !errors.Is(err, core.ErrBlacklistedAddress) {
+		t.Fatalf("pool error mismatch: have %v, want %v", err, core.ErrBlacklistedAddress)
+	}
+	// Even if the transaction bypassed the pool, it must not be mined
+	w := &worker{chainConfig: &config, chain: b.chain, blacklist: core.NewBlacklistProvider(&config)}
+	parent := b.chain.CurrentBlock()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number(), common.Big1),
+		GasLimit:   parent.GasLimit(),
+		Time:       parent.Time() + 1,
+		Difficulty: big.NewInt(1),
+	}
+	if err := w.makeCurrent(parent, header); err != nil {
+		t.Fatalf("failed to prepare block: %v", err)
+	}
+	txs := types.NewTransactionsByPriceAndNonce(w.current.signer, map[common.Address]types.Transactions{testBankAddress: {tx}})
+	w.commitTransactions(txs, testBankAddress, nil)
+	if len(w.current.txs) != 0 {
+		t.Fatalf("blacklisted transaction mined: %v", w.current.txs)
+	}
+	// Transactions to other addresses are still mined
+	valid, _ := types.SignTx(types.NewTransaction(0, testUserAddress, big.NewInt(1000), params.TxGas, nil, nil), types.HomesteadSigner{}, testBankKey)
+	txs = types.NewTransactionsByPriceAndNonce(w.current.signer, map[common.Address]types.Transactions{testBankAddress: {valid}})
+	w.commitTransactions(txs, testBankAddress, nil)
+	if len(w.current.txs) != 1 || w.current.txs[0].Hash() != valid.Hash() {
+		t.Fatalf("valid transaction not mined: %v", w.current.txs)
+	}
+}
+
+// invariantBackend is a PoSV backend computing epoch rewards which break their
+// invariant.
+type invariantBackend struct {
+	posv.PosvBackend
+}
+
+func (invariantBackend) PosvGetEpochReward(c *posv.Posv, config *params.ChainConfig, posvConfig *params.PosvConfig, vicConfig *params.VictionConfig,
+	header *types.Header, chain consensus.ChainReader, state *state.StateDB, logger log.Logger) (*posv.EpochReward, error) {
+	return &posv.EpochReward{}, posv.ErrRewardInvariant
+}
+
+func (invariantBackend) PosvDistributeEpochRewards(header *types.Header, state *state.StateDB, epochReward *posv.EpochReward) error {
+	return nil
+}
+
+// Tests that a miner in strict rewards mode doesn't submit a checkpoint whose rewards
+// break their invariant for sealing, since it would reject the block on import.
+func TestStrictRewardsCheckpointNotSealed(t *testing.T) {
+	config := *params.TestChainConfig
+	config.Posv = &params.PosvConfig{Epoch: 1}
+	config.Viction = &params.VictionConfig{VRC25GasPrice: params.VictionChainConfig.Viction.VRC25GasPrice}
+
+	db := rawdb.NewMemoryDatabase()
+	b := newTestWorkerBackend(t, &config, ethash.NewFaker(), db, 0)
+	defer b.chain.Stop()
+	defer b.txPool.Stop()
+
+	for _, strict := range []bool{false, true} {
+		engine := posv.New(config.Posv, db)
+		engine.SetBackend(invariantBackend{})
+		engine.SetStrictRewards(strict)
+
+		w := &worker{chainConfig: &config, chain: b.chain, engine: engine, taskCh: make(chan *task, 1), unconfirmed: newUnconfirmedBlocks(b.chain, miningLogAtDepth)}
+		atomic.StoreInt32(&w.running, 1)
+
+		parent := b.chain.CurrentBlock()
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     big.NewInt(2),
+			GasLimit:   parent.GasLimit(),
+			Extra:      make([]byte, posv.ExtraVanity+posv.ExtraSeal),
+		}
+		if err := w.makeCurrent(parent, header); err != nil {
+			t.Fatalf("failed to prepare block: %v", err)
+		}
+		err := w.commit(nil, nil, false, time.Now())
+		select {
+		case task := <-w.taskCh:
+			if strict {
+				t.Errorf("strict mode: checkpoint #%d submitted for sealing", task.block.Number())
+			}
+		default:
+			if !strict {
+				t.Errorf("lenient mode: checkpoint not submitted for sealing: %v", err)
+			}
+		}
+		if strict && !errors.Is(err, posv.ErrRewardInvariant) {
+			t.Errorf("strict mode: commit error mismatch: have %v, want %v", err, posv.ErrRewardInvariant)
+		}
+	}
+}
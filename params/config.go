@@ -657,6 +657,9 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
+	if c.Viction != nil {
+		return c.Viction.CheckRewardSchedules()
+	}
 	return nil
 }
 
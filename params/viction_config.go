@@ -1,6 +1,7 @@
 package params
 
 import (
+	"fmt"
 	"math/big"
 	"strings"
 
@@ -8,9 +9,55 @@ import (
 	"github.com/ethereum/go-ethereum/common/math"
 )
 
+// Policies for the wei of epoch rewards left undistributed by integer divisions.
+const (
+	RewardDustBurn       = ""           // Dust is credited to no one, as on the legacy chain
+	RewardDustFoundation = "foundation" // Dust is credited to RewardFoundationAddress
+)
+
+// RewardScheduleSegment divides the reward per epoch by Divisor for the checkpoints from
+// block Start (included) to block End (excluded). A zero End never ends the segment.
+type RewardScheduleSegment struct {
+	Start   uint64 `json:"start"`
+	End     uint64 `json:"end,omitempty"`
+	Divisor uint64 `json:"divisor"`
+}
+
+// Contains reports whether the block number belongs to the segment.
+func (s *RewardScheduleSegment) Contains(number uint64) bool {
+	return s.Start <= number && (s.End == 0 || number < s.End)
+}
+
+// BlacklistEntry blacklists an address from block Start (included) to block End
+// (excluded). A zero End never lifts the blacklisting.
+type BlacklistEntry struct {
+	Address common.Address `json:"address"`
+	Start   uint64         `json:"start"`
+	End     uint64         `json:"end,omitempty"`
+}
+
+// Contains reports whether the address is blacklisted at the block number.
+func (e *BlacklistEntry) Contains(number uint64) bool {
+	return e.Start <= number && (e.End == 0 || number < e.End)
+}
+
+// BypassBalanceEntry overrides the balance of an address before its transactions
+// are applied in block Block.
+type BypassBalanceEntry struct {
+	Address common.Address   `json:"address"`
+	Block   uint64           `json:"block"`
+	Balance *math.Decimal256 `json:"balance"`
+}
+
 type VictionConfig struct {
 	AtlasVRC25MinCap *math.Decimal256 `json:"atlasVRC25MinCap,omitempty"`
 
+	Blacklist              []BlacklistEntry `json:"blacklist,omitempty"`
+	BlacklistContract      common.Address   `json:"blacklistContract,omitempty"`      // Contract holding a mapping(address => bool) at slot 0
+	BlacklistContractBlock uint64           `json:"blacklistContractBlock,omitempty"` // Block from which BlacklistContract is read
+
+	BypassBalances []BypassBalanceEntry `json:"bypassBalances,omitempty"` // Overridden balances on top of the legacy ones
+
 	LendingContract            common.Address   `json:"lendingContract,omitempty"`
 	LendingInterestAmount      *math.Decimal256 `json:"lendingInterestAmount,omitempty"`
 	LendingLiquidateTradeBlock uint64           `json:"lendingLiquidateTradeBlock,omitempty"`
@@ -23,11 +70,13 @@ type VictionConfig struct {
 	RandomizerFinaleNthBlock uint64         `json:"randomizerFinaleNthBlock,omitempty"`
 	RandomizerRevealNthBlock uint64         `json:"randomizerRevealNthBlock,omitempty"`
 
-	RewardFoundationAddress common.Address   `json:"rewardFoundationAddress,omitempty"`
-	RewardFoundationPercent uint64           `json:"rewardFoundationPercent,omitempty"`
-	RewardPerEpoch          *math.Decimal256 `json:"rewardPerEpoch,omitempty"`
-	RewardValidatorPercent  uint64           `json:"rewardValidatorPercent,omitempty"`
-	RewardVoterPercent      uint64           `json:"rewardVoterPercent,omitempty"`
+	RewardDustPolicy        string                  `json:"rewardDustPolicy,omitempty"`
+	RewardFoundationAddress common.Address          `json:"rewardFoundationAddress,omitempty"`
+	RewardFoundationPercent uint64                  `json:"rewardFoundationPercent,omitempty"`
+	RewardPerEpoch          *math.Decimal256        `json:"rewardPerEpoch,omitempty"`
+	RewardSchedule          []RewardScheduleSegment `json:"rewardSchedule,omitempty"` // Legacy halvings if empty
+	RewardValidatorPercent  uint64                  `json:"rewardValidatorPercent,omitempty"`
+	RewardVoterPercent      uint64                  `json:"rewardVoterPercent,omitempty"`
 
 	RelayerCancelFee        *math.Decimal256 `json:"relayerCancelFee,omitempty"`
 	RelayerContract         common.Address   `json:"relayerContract,omitempty"`
@@ -38,17 +87,19 @@ type VictionConfig struct {
 
 	TRC21GasPrice *math.Decimal256 `json:"trc21GasPrice,omitempty"`
 
-	SaigonFundAddress    common.Address   `json:"saigonFundAddress,omitempty"`
-	SaigonFundAmount     *math.Decimal256 `json:"saigonFundAmount,omitempty"`
-	SaigonFundInterval   uint64           `json:"saigonFundInterval,omitempty"`
-	SaigonFundRepeat     uint64           `json:"saigonFundRepeat,omitempty"`
-	SaigonRewardPerEpoch *math.Decimal256 `json:"saigonRewardPerEpoch,omitempty"`
+	SaigonFundAddress    common.Address          `json:"saigonFundAddress,omitempty"`
+	SaigonFundAmount     *math.Decimal256        `json:"saigonFundAmount,omitempty"`
+	SaigonFundInterval   uint64                  `json:"saigonFundInterval,omitempty"`
+	SaigonFundRepeat     uint64                  `json:"saigonFundRepeat,omitempty"`
+	SaigonRewardPerEpoch *math.Decimal256        `json:"saigonRewardPerEpoch,omitempty"`
+	SaigonRewardSchedule []RewardScheduleSegment `json:"saigonRewardSchedule,omitempty"` // Legacy halvings if empty
 
 	TomoXBaseCancelFee *math.Decimal256 `json:"tomoxBaseCancelFee,omitempty"`
 	TomoXBaseFee       *math.Decimal256 `json:"tomoxBaseFee,omitempty"`
 	TomoXBasePrice     *math.Decimal256 `json:"tomoxBasePrice,omitempty"`
 	TomoXBaseRecall    *math.Decimal256 `json:"tomoxBaseRecall,omitempty"`
 	TomoXContract      common.Address   `json:"tomoxContract,omitempty"`
+	TomoXReceiverEnd   uint64           `json:"tomoxReceiverEnd,omitempty"` // First block no longer accepting TomoX transactions, never if zero
 	TomoXTopupDenom    uint64           `json:"tomoxTopupDenom,omitempty"`
 	TomoXTopupNumer    uint64           `json:"tomoxTopupNumer,omitempty"`
 
@@ -62,6 +113,40 @@ type VictionConfig struct {
 	VRC25Contract common.Address   `json:"vrc25Contract,omitempty"`
 }
 
+// IsTomoXReceiver reports whether blocks at the given number accept the TomoX and
+// TomoX lending transactions, from the TIPTomoX fork up to Viction.TomoXReceiverEnd.
+func (c *ChainConfig) IsTomoXReceiver(num *big.Int) bool {
+	if c.Viction == nil || !c.IsTIPTomoX(num) {
+		return false
+	}
+	return c.Viction.TomoXReceiverEnd == 0 || num.Cmp(new(big.Int).SetUint64(c.Viction.TomoXReceiverEnd)) < 0
+}
+
+// CheckRewardSchedules ensures the segments of the reward schedules are ordered, do not
+// overlap and have a non-zero divisor.
+func (c *VictionConfig) CheckRewardSchedules() error {
+	for name, schedule := range map[string][]RewardScheduleSegment{
+		"rewardSchedule":       c.RewardSchedule,
+		"saigonRewardSchedule": c.SaigonRewardSchedule,
+	} {
+		for i, segment := range schedule {
+			if segment.Divisor == 0 {
+				return fmt.Errorf("invalid %s: segment %d has a zero divisor", name, i)
+			}
+			if segment.End != 0 && segment.End <= segment.Start {
+				return fmt.Errorf("invalid %s: segment %d ends at %d before starting at %d", name, i, segment.End, segment.Start)
+			}
+			if i > 0 {
+				prev := schedule[i-1]
+				if prev.End == 0 || prev.End > segment.Start {
+					return fmt.Errorf("invalid %s: segment %d starting at %d overlaps segment %d", name, i, segment.Start, i-1)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 var blacklists = map[common.Address]bool{
 	common.HexToAddress("0x5248bfb72fd4f234e062d3e9bb76f08643004fcd"): true,
 	common.HexToAddress("0x5ac26105b35ea8935be382863a70281ec7a985e9"): true,
@@ -258,7 +343,14 @@ var victionBypassBlocks = map[uint64]string{
 	9147459: "0xe187cf86c2274b1f16e8225a7da9a75aba4f1f5f",
 }
 
+// GetVictionBypassBalance returns the balance the address is given before its
+// transactions in the block are applied, from the configured or legacy overrides.
 func (c *VictionConfig) GetVictionBypassBalance(blockNum uint64, addr common.Address) *big.Int {
+	for _, entry := range c.BypassBalances {
+		if entry.Block == blockNum && entry.Address == addr && entry.Balance != nil {
+			return new(big.Int).Set((*big.Int)(entry.Balance))
+		}
+	}
 	if bypassAddrHex, ok := victionBypassBlocks[blockNum]; ok {
 		if strings.EqualFold(bypassAddrHex, addr.Hex()) {
 			if balanceStr, ok := victionBypassBalances[bypassAddrHex]; ok {
@@ -0,0 +1,41 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import "testing"
+
+func TestCheckRewardSchedules(t *testing.T) {
+	tests := []struct {
+		schedule []RewardScheduleSegment
+		valid    bool
+	}{
+		{nil, true},
+		{[]RewardScheduleSegment{{Start: 0, Divisor: 1}}, true},
+		{[]RewardScheduleSegment{{Start: 0, End: 100, Divisor: 1}, {Start: 100, End: 200, Divisor: 2}, {Start: 300, Divisor: 4}}, true},
+		{[]RewardScheduleSegment{{Start: 0, Divisor: 0}}, false},
+		{[]RewardScheduleSegment{{Start: 100, End: 100, Divisor: 1}}, false},
+		{[]RewardScheduleSegment{{Start: 0, End: 200, Divisor: 1}, {Start: 100, Divisor: 2}}, false},
+		{[]RewardScheduleSegment{{Start: 0, Divisor: 1}, {Start: 100, Divisor: 2}}, false},
+	}
+	for i, tt := range tests {
+		for _, config := range []*VictionConfig{{RewardSchedule: tt.schedule}, {SaigonRewardSchedule: tt.schedule}} {
+			if err := config.CheckRewardSchedules(); (err == nil) != tt.valid {
+				t.Errorf("test %d: validity mismatch: have %v, want valid %v", i, err, tt.valid)
+			}
+		}
+	}
+}
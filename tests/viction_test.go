@@ -0,0 +1,218 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tests
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Command line flags to export the Viction chain tests from a synced node.
+var (
+	victionExportDir     = flag.String("viction.export", "", "Chain data directory of a synced Viction node to export the chain tests from")
+	victionExportNetwork = flag.String("viction.network", "viction", "Network of the node the chain tests are exported from")
+)
+
+// victionTestDir holds the chain tests exported from the Viction networks, one
+// directory per network.
+var victionTestDir = filepath.Join(".", "viction")
+
+func TestVictionChain(t *testing.T) {
+	t.Parallel()
+
+	for _, network := range []string{"viction", "victest"} {
+		network := network
+		t.Run(network, func(t *testing.T) {
+			dir := filepath.Join(victionTestDir, network)
+			if _, err := os.Stat(dir); err != nil {
+				fmt.Fprintf(os.Stderr, "can't find chain tests in %s, export them with -viction.export\n", dir)
+				t.Skip("missing chain tests")
+			}
+			// Every fork of an exported network must be covered by a chain test,
+			// missing ones would otherwise pass unnoticed
+			config, err := chainTestConfig(network)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, blocks := range victionForkRanges(config) {
+				if _, err := os.Stat(filepath.Join(dir, name+".json")); err != nil {
+					t.Errorf("no chain test of blocks %d..%d around the %s fork, export it with -viction.export", blocks[0], blocks[1], name)
+				}
+			}
+			if t.Failed() {
+				return
+			}
+			ct := new(testMatcher)
+			ct.walk(t, dir, func(t *testing.T, name string, test *ChainTest) {
+				if err := ct.checkFailure(t, name, test.Run()); err != nil {
+					t.Error(err)
+				}
+			})
+		})
+	}
+}
+
+// victionForkRanges returns the block ranges around each fork of the network the
+// chain tests are exported for, by name.
+func victionForkRanges(config *params.ChainConfig) map[string][2]uint64 {
+	forks := map[string]*big.Int{
+		"homestead":         config.HomesteadBlock,
+		"eip150":            config.EIP150Block,
+		"eip155":            config.EIP155Block,
+		"byzantium":         config.ByzantiumBlock,
+		"tip2019":           config.TIP2019Block,
+		"tipSigning":        config.TIPSigningBlock,
+		"tipRandomize":      config.TIPRandomizeBlock,
+		"tipBlacklist":      config.TIPBlacklistBlock,
+		"tipTRC21Fee":       config.TIPTRC21FeeBlock,
+		"tipFixSignerCheck": config.TIPFixSignerCheckBlock,
+		"tipTomoX":          config.TIPTomoXBlock,
+		"tipTomoXLending":   config.TIPTomoXLendingBlock,
+		"saigon":            config.SaigonBlock,
+		"atlas":             config.AtlasBlock,
+	}
+	if config.Posv != nil {
+		// The first checkpoint crediting epoch rewards
+		forks["epochReward"] = new(big.Int).SetUint64(2 * config.Posv.Epoch)
+	}
+	ranges := make(map[string][2]uint64)
+	for name, number := range forks {
+		if number == nil || number.Sign() == 0 {
+			continue
+		}
+		first := number.Uint64()
+		if first > 1 {
+			first--
+		}
+		ranges[name] = [2]uint64{first, number.Uint64() + 1}
+	}
+	return ranges
+}
+
+// TestVictionChainExport exports the chain tests around each fork block from the
+// chain data of a synced node, when requested with the viction.export flag.
+func TestVictionChainExport(t *testing.T) {
+	if *victionExportDir == "" {
+		t.Skip("no chain data to export from")
+	}
+	config, err := chainTestConfig(*victionExportNetwork)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := rawdb.NewLevelDBDatabaseWithFreezer(*victionExportDir, 256, 256, filepath.Join(*victionExportDir, "ancient"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	dir := filepath.Join(victionTestDir, *victionExportNetwork)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, blocks := range victionForkRanges(config) {
+		test, err := MakeChainTest(db, *victionExportNetwork, blocks[0], blocks[1])
+		if err != nil {
+			t.Fatalf("%s: failed to export blocks %d..%d: %v", name, blocks[0], blocks[1], err)
+		}
+		out, err := json.MarshalIndent(map[string]*ChainTest{name: test}, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name+".json"), out, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Tests that a chain test recorded from a chain reproduces it from the witness
+// alone, and fails on a tampered state root or an incomplete witness.
+func TestChainTestRoundtrip(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		config  = Forks["Byzantium"]
+		db      = rawdb.NewMemoryDatabase()
+		gspec   = &core.Genesis{
+			Config: config,
+			Alloc:  core.GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(config.ChainID)
+	)
+	blocks, _ := core.GenerateChain(config, genesis, ethash.NewFaker(), db, 6, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(address), common.Address{byte(i + 1)}, big.NewInt(1000), params.TxGas, big.NewInt(1), nil), signer, key)
+		b.AddTx(tx)
+	})
+	chain, _ := core.NewBlockChain(db, &core.CacheConfig{TrieDirtyDisabled: true}, config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	chain.Stop()
+
+	test, err := MakeChainTest(db, "Byzantium", 3, 5)
+	if err != nil {
+		t.Fatalf("failed to make chain test: %v", err)
+	}
+	enc, err := json.Marshal(test)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reload := func() *ChainTest {
+		test := new(ChainTest)
+		if err := json.Unmarshal(enc, test); err != nil {
+			t.Fatal(err)
+		}
+		return test
+	}
+	if err := reload().Run(); err != nil {
+		t.Fatalf("chain test failed: %v", err)
+	}
+	if head := chain.CurrentBlock().NumberU64(); head != 6 {
+		t.Fatalf("recording modified the source chain: head #%d", head)
+	}
+	tampered := reload()
+	tampered.json.Roots[math.HexOrDecimal64(4)] = common.Hash{0x01}
+	if err := tampered.Run(); err == nil {
+		t.Error("chain test with tampered root succeeded")
+	}
+	incomplete := reload()
+	for key := range incomplete.json.Pre {
+		if len(key) == 2+2*common.HashLength && key != hexutil.Encode(blocks[1].Root().Bytes()) {
+			delete(incomplete.json.Pre, key) // Drop the trie nodes below the parent state root
+		}
+	}
+	if err := incomplete.Run(); err == nil {
+		t.Error("chain test with incomplete witness succeeded")
+	}
+}
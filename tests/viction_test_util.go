@@ -0,0 +1,498 @@
+// Copyright 2026 The Vic-geth Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/posv"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/viction"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// A ChainTest checks that importing a range of blocks exported from a live network
+// reproduces the state roots the network agreed on.
+//
+// Instead of the full state of the parent block, the test carries the witness of
+// the import: every database entry the import of the range read, recorded while
+// importing it on top of a synced node with MakeChainTest. Ranges can therefore
+// start anywhere in the chain, around the fork blocks deep in the history.
+type ChainTest struct {
+	json ctJSON
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (t *ChainTest) UnmarshalJSON(in []byte) error {
+	return json.Unmarshal(in, &t.json)
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (t *ChainTest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&t.json)
+}
+
+type ctJSON struct {
+	Network  string                                           `json:"network"`  // viction, victest or one of the Forks
+	Pre      map[string]hexutil.Bytes                         `json:"pre"`      // Key-value entries read by the import
+	Ancients map[string]map[math.HexOrDecimal64]hexutil.Bytes `json:"ancients"` // Ancient entries read by the import
+	Blocks   []hexutil.Bytes                                  `json:"blocks"`   // RLP of the blocks in the range
+	Roots    map[math.HexOrDecimal64]common.Hash              `json:"roots"`    // Expected state roots by block number
+}
+
+// chainTestCache is the cache configuration the chain tests are imported with.
+// Snapshots are disabled as they need the entire state of the parent block, and
+// the states are written as they are imported, so that stopping the chain does not
+// look for the blocks before the range.
+var chainTestCache = &core.CacheConfig{
+	TrieCleanLimit:    256,
+	TrieDirtyDisabled: true,
+}
+
+// chainTestConfig returns the chain configuration of the network.
+func chainTestConfig(network string) (*params.ChainConfig, error) {
+	switch network {
+	case "viction":
+		return params.VictionChainConfig, nil
+	case "victest":
+		return params.VictestChainConfig, nil
+	}
+	if config, ok := Forks[network]; ok {
+		return config, nil
+	}
+	return nil, UnsupportedForkError{network}
+}
+
+// newChainTestEngine creates the consensus engine of the network, whose backend
+// must be pointed to the chain once it is created.
+func newChainTestEngine(config *params.ChainConfig, db ethdb.Database) (consensus.Engine, *chainTestBackend) {
+	if config.Posv == nil {
+		return ethash.NewFaker(), new(chainTestBackend)
+	}
+	backend := new(chainTestBackend)
+	engine := posv.New(config.Posv, db)
+	engine.SetBackend(backend)
+	return engine, backend
+}
+
+func (t *ChainTest) Run() error {
+	config, err := chainTestConfig(t.json.Network)
+	if err != nil {
+		return err
+	}
+	db := &witnessDB{Database: rawdb.NewMemoryDatabase(), ancients: t.json.Ancients}
+	for key, value := range t.json.Pre {
+		k, err := hexutil.Decode(key)
+		if err != nil {
+			return fmt.Errorf("invalid pre key %q: %v", key, err)
+		}
+		db.Put(k, value)
+	}
+	engine, backend := newChainTestEngine(config, db)
+	chain, err := core.NewBlockChain(db, chainTestCache, config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer chain.Stop()
+	backend.chain = chain
+
+	blocks := make(types.Blocks, len(t.json.Blocks))
+	for i, enc := range t.json.Blocks {
+		blocks[i] = new(types.Block)
+		if err := rlp.DecodeBytes(enc, blocks[i]); err != nil {
+			return fmt.Errorf("block %d RLP decoding failed: %v", i, err)
+		}
+	}
+	if len(blocks) == 0 {
+		return errors.New("no blocks to import")
+	}
+	if head := chain.CurrentBlock(); head.Hash() != blocks[0].ParentHash() {
+		return fmt.Errorf("witness head mismatch: have #%d [%x], want [%x]", head.NumberU64(), head.Hash().Bytes()[:4], blocks[0].ParentHash().Bytes()[:4])
+	}
+	if n, err := chain.InsertChain(blocks); err != nil {
+		return fmt.Errorf("block #%v insertion into chain failed: %v", blocks[n].Number(), err)
+	}
+	if head := chain.CurrentBlock(); head.Hash() != blocks[len(blocks)-1].Hash() {
+		return fmt.Errorf("last block hash validation mismatch: want: %x, have: %x", blocks[len(blocks)-1].Hash(), head.Hash())
+	}
+	for number, root := range t.json.Roots {
+		header := chain.GetHeaderByNumber(uint64(number))
+		if header == nil {
+			return fmt.Errorf("block #%d not imported", number)
+		}
+		if header.Root != root {
+			return fmt.Errorf("state root mismatch at #%d: want: %x, have: %x", number, root, header.Root)
+		}
+		if !chain.HasState(root) {
+			return fmt.Errorf("state of #%d missing after import", number)
+		}
+	}
+	return nil
+}
+
+// MakeChainTest imports the canonical blocks first..last of the chain stored in db
+// on top of their parent, and records the chain test of the range from the entries
+// the import read. The state of the parent must be available, which takes an archive
+// node for the ranges deep in the history. The database is not modified.
+func MakeChainTest(db ethdb.Database, network string, first, last uint64) (*ChainTest, error) {
+	config, err := chainTestConfig(network)
+	if err != nil {
+		return nil, err
+	}
+	if first == 0 || last < first {
+		return nil, fmt.Errorf("invalid block range %d..%d", first, last)
+	}
+	parent := rawdb.ReadHeader(db, rawdb.ReadCanonicalHash(db, first-1), first-1)
+	if parent == nil {
+		return nil, fmt.Errorf("block #%d not found", first-1)
+	}
+	if _, err := state.New(parent.Root, state.NewDatabase(db), nil); err != nil {
+		return nil, fmt.Errorf("state of block #%d missing: %v", first-1, err)
+	}
+	var blocks types.Blocks
+	for number := first; number <= last; number++ {
+		block := rawdb.ReadBlock(db, rawdb.ReadCanonicalHash(db, number), number)
+		if block == nil {
+			return nil, fmt.Errorf("block #%d not found", number)
+		}
+		blocks = append(blocks, block)
+	}
+	// Rewind the head to the parent, as if the range was never imported
+	rec := newRecordingDB(db)
+	rawdb.WriteHeadHeaderHash(recordWriter{rec}, parent.Hash())
+	rawdb.WriteHeadFastBlockHash(recordWriter{rec}, parent.Hash())
+	rawdb.WriteHeadBlockHash(recordWriter{rec}, parent.Hash())
+
+	engine, backend := newChainTestEngine(config, rec)
+	chain, err := core.NewBlockChain(rec, chainTestCache, config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	backend.chain = chain
+	if n, err := chain.InsertChain(blocks); err != nil {
+		chain.Stop()
+		return nil, fmt.Errorf("block #%v insertion into chain failed: %v", blocks[n].Number(), err)
+	}
+	chain.Stop()
+
+	test := &ChainTest{json: ctJSON{
+		Network:  network,
+		Pre:      make(map[string]hexutil.Bytes, len(rec.reads)),
+		Ancients: make(map[string]map[math.HexOrDecimal64]hexutil.Bytes),
+		Roots:    make(map[math.HexOrDecimal64]common.Hash),
+	}}
+	for key, value := range rec.reads {
+		test.json.Pre[hexutil.Encode([]byte(key))] = value
+	}
+	for kind, items := range rec.ancients {
+		test.json.Ancients[kind] = make(map[math.HexOrDecimal64]hexutil.Bytes, len(items))
+		for number, item := range items {
+			test.json.Ancients[kind][math.HexOrDecimal64(number)] = item
+		}
+	}
+	for _, block := range blocks {
+		enc, err := rlp.EncodeToBytes(block)
+		if err != nil {
+			return nil, err
+		}
+		test.json.Blocks = append(test.json.Blocks, enc)
+		test.json.Roots[math.HexOrDecimal64(block.NumberU64())] = block.Root()
+	}
+	return test, nil
+}
+
+// errNotSupported is returned by the chain test databases for the operations they
+// have no use for.
+var errNotSupported = errors.New("not supported")
+
+// witnessDB is the database a chain test is imported into, serving the recorded
+// ancient entries besides the key-value ones.
+type witnessDB struct {
+	ethdb.Database
+	ancients map[string]map[math.HexOrDecimal64]hexutil.Bytes
+}
+
+// HasAncient implements ethdb.AncientReader, checking the recorded entries.
+func (db *witnessDB) HasAncient(kind string, number uint64) (bool, error) {
+	_, ok := db.ancients[kind][math.HexOrDecimal64(number)]
+	return ok, nil
+}
+
+// Ancient implements ethdb.AncientReader, returning a recorded entry.
+func (db *witnessDB) Ancient(kind string, number uint64) ([]byte, error) {
+	if item, ok := db.ancients[kind][math.HexOrDecimal64(number)]; ok {
+		return item, nil
+	}
+	return nil, errNotSupported
+}
+
+// recordingDB is a database layered over a source database, which records the
+// entries read from the source and keeps the writes in memory.
+type recordingDB struct {
+	source  ethdb.Database
+	overlay ethdb.KeyValueStore
+	deleted map[string]struct{}
+
+	reads    map[string][]byte
+	ancients map[string]map[uint64][]byte
+	lock     sync.Mutex
+}
+
+func newRecordingDB(source ethdb.Database) *recordingDB {
+	return &recordingDB{
+		source:   source,
+		overlay:  memorydb.New(),
+		deleted:  make(map[string]struct{}),
+		reads:    make(map[string][]byte),
+		ancients: make(map[string]map[uint64][]byte),
+	}
+}
+
+// Has implements ethdb.KeyValueReader.
+func (db *recordingDB) Has(key []byte) (bool, error) {
+	value, err := db.Get(key)
+	return err == nil && value != nil, nil
+}
+
+// Get implements ethdb.KeyValueReader, recording the entries read from the source.
+func (db *recordingDB) Get(key []byte) ([]byte, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if value, err := db.overlay.Get(key); err == nil {
+		return value, nil
+	}
+	if _, ok := db.deleted[string(key)]; ok {
+		return nil, errors.New("not found")
+	}
+	value, err := db.source.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	db.reads[string(key)] = common.CopyBytes(value)
+	return value, nil
+}
+
+// Put implements ethdb.KeyValueWriter, keeping the entry in memory.
+func (db *recordingDB) Put(key []byte, value []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	delete(db.deleted, string(key))
+	return db.overlay.Put(key, value)
+}
+
+// Delete implements ethdb.KeyValueWriter, hiding the entry of the source.
+func (db *recordingDB) Delete(key []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.deleted[string(key)] = struct{}{}
+	return db.overlay.Delete(key)
+}
+
+// NewBatch implements ethdb.Batcher, replaying the batch into the overlay.
+func (db *recordingDB) NewBatch() ethdb.Batch {
+	return &recordingBatch{Batch: memorydb.New().NewBatch(), db: db}
+}
+
+// NewIterator implements ethdb.Iteratee, recording the entries iterated over in the
+// source. Entries written to the overlay are not visible to the iterator.
+func (db *recordingDB) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
+	return &recordingIterator{Iterator: db.source.NewIterator(prefix, start), db: db}
+}
+
+// HasAncient implements ethdb.AncientReader.
+func (db *recordingDB) HasAncient(kind string, number uint64) (bool, error) {
+	item, err := db.Ancient(kind, number)
+	return err == nil && item != nil, nil
+}
+
+// Ancient implements ethdb.AncientReader, recording the entries read from the source.
+func (db *recordingDB) Ancient(kind string, number uint64) ([]byte, error) {
+	item, err := db.source.Ancient(kind, number)
+	if err != nil {
+		return nil, err
+	}
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.ancients[kind] == nil {
+		db.ancients[kind] = make(map[uint64][]byte)
+	}
+	db.ancients[kind][number] = common.CopyBytes(item)
+	return item, nil
+}
+
+// Ancients implements ethdb.AncientReader. The number of ancient items is hidden,
+// as the chain would otherwise truncate them to the rewound head.
+func (db *recordingDB) Ancients() (uint64, error) { return 0, errNotSupported }
+
+// AncientSize implements ethdb.AncientReader.
+func (db *recordingDB) AncientSize(kind string) (uint64, error) { return 0, errNotSupported }
+
+// AppendAncient implements ethdb.AncientWriter.
+func (db *recordingDB) AppendAncient(number uint64, hash, header, body, receipt, td []byte) error {
+	return errNotSupported
+}
+
+// TruncateAncients implements ethdb.AncientWriter.
+func (db *recordingDB) TruncateAncients(n uint64) error { return errNotSupported }
+
+// Sync implements ethdb.AncientWriter.
+func (db *recordingDB) Sync() error { return errNotSupported }
+
+// Stat implements ethdb.Stater.
+func (db *recordingDB) Stat(property string) (string, error) { return db.overlay.Stat(property) }
+
+// Compact implements ethdb.Compacter.
+func (db *recordingDB) Compact(start []byte, limit []byte) error { return nil }
+
+// Close implements io.Closer, leaving the source open.
+func (db *recordingDB) Close() error { return nil }
+
+// recordWriter writes entries into a recording database as if they had been read
+// from its source.
+type recordWriter struct {
+	db *recordingDB
+}
+
+func (w recordWriter) Put(key []byte, value []byte) error {
+	w.db.lock.Lock()
+	w.db.reads[string(key)] = common.CopyBytes(value)
+	w.db.lock.Unlock()
+	return w.db.Put(key, value)
+}
+
+func (w recordWriter) Delete(key []byte) error { return errNotSupported }
+
+// recordingBatch is a batch of writes to a recording database.
+type recordingBatch struct {
+	ethdb.Batch
+	db *recordingDB
+}
+
+// Write implements ethdb.Batch, flushing the batch into the overlay.
+func (b *recordingBatch) Write() error {
+	return b.Batch.Replay(b.db)
+}
+
+// recordingIterator is an iterator over the source of a recording database.
+type recordingIterator struct {
+	ethdb.Iterator
+	db *recordingDB
+}
+
+// Next implements ethdb.Iterator, recording the entry moved to.
+func (it *recordingIterator) Next() bool {
+	if !it.Iterator.Next() {
+		return false
+	}
+	it.db.lock.Lock()
+	it.db.reads[string(it.Key())] = common.CopyBytes(it.Value())
+	it.db.lock.Unlock()
+	return true
+}
+
+// chainTestBackend is the PoSV backend of the chain tests, reading the chain being
+// imported like the one of a full node.
+type chainTestBackend struct {
+	chain *core.BlockChain
+}
+
+func (b *chainTestBackend) PosvGetAttestors(vicConfig params.VictionConfig, header *types.Header, validators []common.Address) ([]int64, error) {
+	parent := b.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	statedb, err := b.chain.StateAt(parent.Root)
+	if err != nil {
+		return nil, err
+	}
+	return viction.CalcAttestors(&vicConfig, statedb, validators), nil
+}
+
+func (b *chainTestBackend) PosvGetBlockSignData(config *params.ChainConfig, vicConfig *params.VictionConfig, header *types.Header, chain consensus.ChainReader) []types.Transaction {
+	signTxs := []types.Transaction{}
+	block := chain.GetBlock(header.Hash(), header.Number.Uint64())
+	if block == nil {
+		return signTxs
+	}
+	for _, tx := range block.Transactions() {
+		if viction.IsSigningTransaction(tx, vicConfig.ValidatorBlockSignContract) {
+			signTxs = append(signTxs, *tx)
+		}
+	}
+	return signTxs
+}
+
+func (b *chainTestBackend) PosvGetCreatorAttestorPairs(c *posv.Posv, config *params.ChainConfig, header, checkpointHeader *types.Header) (map[common.Address]common.Address, uint64, error) {
+	return viction.CalcCreatorAttestorPairs(config, checkpointHeader, header)
+}
+
+func (b *chainTestBackend) PosvGetEpochReward(c *posv.Posv, config *params.ChainConfig, posvConfig *params.PosvConfig, vicConfig *params.VictionConfig,
+	header *types.Header, chain consensus.ChainReader, statedb *state.StateDB, logger log.Logger) (*posv.EpochReward, error) {
+	return viction.CalcEpochReward(c, config, posvConfig, vicConfig, header, chain, statedb, logger)
+}
+
+func (b *chainTestBackend) PosvDistributeEpochRewards(header *types.Header, statedb *state.StateDB, epochReward *posv.EpochReward) error {
+	if epochReward == nil || statedb == nil {
+		return nil
+	}
+	for addr, amount := range epochReward.StakholderRewards {
+		if amount != nil && amount.Cmp(new(big.Int)) > 0 {
+			statedb.AddBalance(addr, amount)
+		}
+	}
+	return nil
+}
+
+func (b *chainTestBackend) PosvGetPenalties(c *posv.Posv, config *params.ChainConfig, posvConfig *params.PosvConfig, vicConfig *params.VictionConfig, header *types.Header, chain consensus.ChainReader) ([]common.Address, error) {
+	return viction.CalcPenalties(c, config, posvConfig, vicConfig, header, chain, log.Root())
+}
+
+func (b *chainTestBackend) PosvGetState(header *types.Header) (*state.StateDB, error) {
+	return b.chain.StateAt(header.Root)
+}
+
+func (b *chainTestBackend) PosvGetValidators(vicConfig *params.VictionConfig, header *types.Header, chain consensus.ChainReader) ([]common.Address, error) {
+	if header == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	statedb, err := b.chain.StateAt(header.Root)
+	if err != nil {
+		return nil, err
+	}
+	return viction.GetValidators(vicConfig, statedb), nil
+}